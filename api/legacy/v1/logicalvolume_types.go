@@ -30,6 +30,102 @@ type LogicalVolumeSpec struct {
 	// This field is populated only when LogicalVolume has a source.
 	// +kubebuilder:validation:Optional
 	AccessType string `json:"accessType,omitempty"`
+
+	// 'pvcName' and 'pvcNamespace' specify the PersistentVolumeClaim that the logical volume is provisioned for.
+	// 'pvName' specifies the PersistentVolume.
+	// These fields are populated only when external-provisioner runs with --extra-create-metadata.
+	// They are written to the LVM logical volume as tags.
+	// +kubebuilder:validation:Optional
+	PVCName string `json:"pvcName,omitempty"`
+	// +kubebuilder:validation:Optional
+	PVCNamespace string `json:"pvcNamespace,omitempty"`
+	// +kubebuilder:validation:Optional
+	PVName string `json:"pvName,omitempty"`
+
+	// 'ioLimits' specifies the I/O limits applied to the pods consuming the logical volume.
+	// +kubebuilder:validation:Optional
+	IOLimits *IOLimits `json:"ioLimits,omitempty"`
+
+	// 'readAhead' specifies the read-ahead of the logical volume.
+	// It is "auto", "none", or the number of 512-byte sectors.
+	// +kubebuilder:validation:Optional
+	ReadAhead string `json:"readAhead,omitempty"`
+
+	// 'tags' specifies the LVM tags added to the logical volume in addition to the ones managed by TopoLVM.
+	// +kubebuilder:validation:Optional
+	Tags []string `json:"tags,omitempty"`
+
+	// 'encrypted' specifies whether the logical volume is encrypted with dm-crypt/LUKS.
+	// It cannot be changed after creation.
+	// +kubebuilder:validation:Optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// 'fstrimInterval' specifies the interval at which topolvm-node runs fstrim on the mounted filesystem
+	// to return the unused blocks to the thin pool. fstrim is not run if this field is not set.
+	// +kubebuilder:validation:Optional
+	FSTrimInterval *metav1.Duration `json:"fstrimInterval,omitempty"`
+
+	// 'fsType' specifies the filesystem type created on the logical volume, and
+	// 'mkfsOptions' specifies the arguments passed to mkfs when it is created.
+	// They are recorded at creation so that the filesystem is always created in the same way
+	// even if the StorageClass is re-created with different parameters. They cannot be changed after creation.
+	// +kubebuilder:validation:Optional
+	FsType string `json:"fsType,omitempty"`
+	// +kubebuilder:validation:Optional
+	MkfsOptions []string `json:"mkfsOptions,omitempty"`
+
+	// 'fsckPolicy' specifies how topolvm-node checks the filesystem before the first mount after the node boots.
+	// The filesystem is checked as before, i.e. repaired by 'fsck -a' on every mount, if not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=never;check-only;auto-repair-safe
+	FsckPolicy FsckPolicy `json:"fsckPolicy,omitempty"`
+
+	// 'freezeFilesystem' specifies whether topolvm-node freezes the filesystem of the source volume
+	// while it takes the snapshot so that the snapshot is application-consistent.
+	// This is effective only for snapshots.
+	// +kubebuilder:validation:Optional
+	FreezeFilesystem bool `json:"freezeFilesystem,omitempty"`
+
+	// 'groupSnapshot' specifies the group snapshot the snapshot belongs to.
+	// The snapshots in a group are taken at the same point in time.
+	// +kubebuilder:validation:Optional
+	GroupSnapshot *GroupSnapshot `json:"groupSnapshot,omitempty"`
+}
+
+// GroupSnapshot specifies a group of snapshots taken at the same point in time.
+type GroupSnapshot struct {
+	// 'name' is the name of the group snapshot.
+	Name string `json:"name"`
+
+	// 'members' is the names of the LogicalVolumes of all the snapshots in the group.
+	// +kubebuilder:validation:MinItems=1
+	Members []string `json:"members"`
+}
+
+// FsckPolicy is the policy to check the filesystem on the logical volume.
+type FsckPolicy string
+
+const (
+	// FsckNever never checks the filesystem.
+	FsckNever = FsckPolicy("never")
+	// FsckCheckOnly checks the filesystem without modifying it and reports the errors found.
+	FsckCheckOnly = FsckPolicy("check-only")
+	// FsckAutoRepairSafe repairs only the errors that can be fixed without human intervention,
+	// and refuses to mount the filesystem if errors remain.
+	FsckAutoRepairSafe = FsckPolicy("auto-repair-safe")
+)
+
+// IOLimits specifies the I/O limits of the logical volume.
+// They are applied as cgroup v2 io.max entries. Zero means unlimited.
+type IOLimits struct {
+	// +kubebuilder:validation:Minimum=0
+	ReadIOPS int64 `json:"readIOPS,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	WriteIOPS int64 `json:"writeIOPS,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	ReadBytesPerSecond int64 `json:"readBytesPerSecond,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	WriteBytesPerSecond int64 `json:"writeBytesPerSecond,omitempty"`
 }
 
 // LogicalVolumeStatus defines the observed state of LogicalVolume
@@ -40,11 +136,167 @@ type LogicalVolumeStatus struct {
 	Code        codes.Code         `json:"code,omitempty"`
 	Message     string             `json:"message,omitempty"`
 	CurrentSize *resource.Quantity `json:"currentSize,omitempty"`
+
+	// 'lvName' is the name of the LVM logical volume, which lvmd names after 'lv-name-template' of the device class.
+	// It is empty for the logical volumes created before the field was introduced, whose names are 'volumeID'.
+	// +kubebuilder:validation:Optional
+	LVName string `json:"lvName,omitempty"`
+
+	// 'createAttempts' is the number of attempts to create the LVM logical volume.
+	// +kubebuilder:validation:Optional
+	CreateAttempts int32 `json:"createAttempts,omitempty"`
+
+	// 'nextRetryTime' is the time when the failed creation will be retried.
+	// It is not set when the failure is not retryable or no more retry is allowed.
+	// +kubebuilder:validation:Optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// 'readAhead' and 'tags' are the attributes in 'spec' that have been applied to the LVM logical volume.
+	// +kubebuilder:validation:Optional
+	ReadAhead string `json:"readAhead,omitempty"`
+	// +kubebuilder:validation:Optional
+	Tags []string `json:"tags,omitempty"`
+
+	// 'filesystem' is the usage of the filesystem on the logical volume.
+	// topolvm-node updates it when kubelet collects the volume stats.
+	// +kubebuilder:validation:Optional
+	Filesystem *FilesystemUsage `json:"filesystem,omitempty"`
+
+	// 'lastFsckTime' is the time when topolvm-node checked the filesystem according to 'spec.fsckPolicy' last time.
+	// +kubebuilder:validation:Optional
+	LastFsckTime *metav1.Time `json:"lastFsckTime,omitempty"`
+
+	// 'copy' is the state of copying the contents of another volume into the logical volume.
+	// It is set only while topolvm-node is copying them, and tells that the copy was interrupted
+	// when topolvm-node finds it without running the copy after a restart.
+	// +kubebuilder:validation:Optional
+	Copy *CopyStatus `json:"copy,omitempty"`
+
+	// 'migration' is the state of the migration of the logical volume to another node.
+	// +kubebuilder:validation:Optional
+	Migration *MigrationStatus `json:"migration,omitempty"`
+
+	// 'conditions' represents the latest available observations of the logical volume.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CopyMode is the purpose of copying the contents of another volume.
+type CopyMode string
+
+const (
+	// CopyCreate copies the contents of a volume on another node into the logical volume being created.
+	CopyCreate = CopyMode("Create")
+	// CopySync applies the changes of the origin of 'spec.source' made after the copy to the logical volume.
+	CopySync = CopyMode("Sync")
+)
+
+// CopyStatus is the state of copying the contents of another volume into the logical volume.
+type CopyStatus struct {
+	// +kubebuilder:validation:Enum=Create;Sync
+	Mode CopyMode `json:"mode"`
+	// 'sourceVolumeID' and 'sourceNodeName' are the volume whose contents are copied and its node.
+	SourceVolumeID string `json:"sourceVolumeID"`
+	SourceNodeName string `json:"sourceNodeName"`
+	// 'copiedBytes' and 'totalBytes' are the progress recorded last time.
+	// +kubebuilder:validation:Optional
+	CopiedBytes int64 `json:"copiedBytes,omitempty"`
+	// +kubebuilder:validation:Optional
+	TotalBytes int64       `json:"totalBytes,omitempty"`
+	StartTime  metav1.Time `json:"startTime"`
+}
+
+// MigrationStatus is the state of the migration of the logical volume to another node.
+type MigrationStatus struct {
+	// 'step' is the last step the migration reached, which is also the reason of the Migrating condition.
+	Step string `json:"step"`
+	// 'snapshotVolumeID' is the volume ID of the snapshot copied to the node.
+	// The copy can be synced only with the changes made after this snapshot.
+	// +kubebuilder:validation:Optional
+	SnapshotVolumeID string `json:"snapshotVolumeID,omitempty"`
 }
 
+// FilesystemUsage is the usage of the filesystem on the logical volume.
+type FilesystemUsage struct {
+	CapacityBytes int64       `json:"capacityBytes"`
+	UsedBytes     int64       `json:"usedBytes"`
+	ObservedTime  metav1.Time `json:"observedTime"`
+}
+
+// Condition types of LogicalVolume.
+const (
+	// LogicalVolumeCreated indicates whether the LVM logical volume has been created.
+	LogicalVolumeCreated = "Created"
+	// LogicalVolumeResizing indicates whether the LVM logical volume is being resized.
+	LogicalVolumeResizing = "Resizing"
+	// LogicalVolumeReady indicates whether the LVM logical volume is available in the requested size.
+	// Its reason summarizes the phase of the LogicalVolume.
+	LogicalVolumeReady = "Ready"
+	// LogicalVolumeFailed indicates whether the last operation on the LVM logical volume has failed.
+	LogicalVolumeFailed = "Failed"
+	// LogicalVolumeDeletionPending indicates whether the LogicalVolume is waiting for deletion.
+	LogicalVolumeDeletionPending = "DeletionPending"
+	// LogicalVolumeDrifted indicates whether the LVM logical volume differs from what the LogicalVolume records.
+	LogicalVolumeDrifted = "Drifted"
+	// LogicalVolumeReleased indicates whether the PersistentVolume of the logical volume has been released
+	// from its claim and the logical volume is retained for re-binding.
+	LogicalVolumeReleased = "Released"
+	// LogicalVolumeWiping indicates whether the LVM logical volume is being wiped before removal.
+	LogicalVolumeWiping = "Wiping"
+	// LogicalVolumeMigrating indicates whether the logical volume is being migrated to another node.
+	// Its reason is the phase of the migration.
+	LogicalVolumeMigrating = "Migrating"
+	// LogicalVolumeSynced indicates whether the changes of the source volume made after the copy have been applied.
+	LogicalVolumeSynced = "Synced"
+)
+
+// Condition reasons of LogicalVolume.
+const (
+	ReasonPending                   = "Pending"
+	ReasonAvailable                 = "Available"
+	ReasonResizing                  = "Resizing"
+	ReasonResized                   = "Resized"
+	ReasonFailed                    = "Failed"
+	ReasonSucceeded                 = "Succeeded"
+	ReasonDeleting                  = "Deleting"
+	ReasonPendingDeletionAnnotation = "PendingDeletionAnnotation"
+	ReasonCreated                   = "Created"
+	ReasonAdopted                   = "Adopted"
+	ReasonCreateFailed              = "CreateFailed"
+	ReasonRetrying                  = "Retrying"
+	ReasonDrifted                   = "Drifted"
+	ReasonLVMissing                 = "LVMissing"
+	ReasonSizeMismatch              = "SizeMismatch"
+	ReasonTagMismatch               = "TagMismatch"
+	ReasonInSync                    = "InSync"
+	ReasonResizeFailed              = "ResizeFailed"
+	ReasonModifyFailed              = "ModifyFailed"
+	ReasonRemoveFailed              = "RemoveFailed"
+	ReasonReleased                  = "Released"
+	ReasonBound                     = "Bound"
+	ReasonWiping                    = "Wiping"
+	ReasonCopying                   = "Copying"
+	ReasonMigrating                 = "Migrating"
+	ReasonSnapshotting              = "Snapshotting"
+	ReasonWaitingForConsumer        = "WaitingForConsumer"
+	ReasonSyncing                   = "Syncing"
+	ReasonSynced                    = "Synced"
+	ReasonSyncFailed                = "SyncFailed"
+	ReasonSwitching                 = "Switching"
+	ReasonMigrationFailed           = "MigrationFailed"
+	ReasonMigrationCanceled         = "MigrationCanceled"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+//+kubebuilder:printcolumn:name="DeviceClass",type=string,JSONPath=`.spec.deviceClass`
+//+kubebuilder:printcolumn:name="Size",type=string,JSONPath=`.spec.size`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // LogicalVolume is the Schema for the logicalvolumes API
 type LogicalVolume struct {
@@ -55,6 +307,17 @@ type LogicalVolume struct {
 	Status LogicalVolumeStatus `json:"status,omitempty"`
 }
 
+// LVName returns the name of the LVM logical volume.
+func (lv *LogicalVolume) LVName() string {
+	if lv.Status.LVName != "" {
+		return lv.Status.LVName
+	}
+	if lv.Status.VolumeID != "" {
+		return lv.Status.VolumeID
+	}
+	return string(lv.UID)
+}
+
 // IsCompatibleWith returns true if the LogicalVolume is compatible.
 func (lv *LogicalVolume) IsCompatibleWith(lv2 *LogicalVolume) bool {
 	if lv.Spec.Name != lv2.Spec.Name {
@@ -66,6 +329,20 @@ func (lv *LogicalVolume) IsCompatibleWith(lv2 *LogicalVolume) bool {
 	if lv.Spec.Size.Cmp(lv2.Spec.Size) != 0 {
 		return false
 	}
+	if lv.Spec.Encrypted != lv2.Spec.Encrypted {
+		return false
+	}
+	if lv.Spec.FsType != lv2.Spec.FsType {
+		return false
+	}
+	if len(lv.Spec.MkfsOptions) != len(lv2.Spec.MkfsOptions) {
+		return false
+	}
+	for i := range lv.Spec.MkfsOptions {
+		if lv.Spec.MkfsOptions[i] != lv2.Spec.MkfsOptions[i] {
+			return false
+		}
+	}
 	return true
 }
 
@@ -6,9 +6,77 @@
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CopyStatus) DeepCopyInto(out *CopyStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyStatus.
+func (in *CopyStatus) DeepCopy() *CopyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CopyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemUsage) DeepCopyInto(out *FilesystemUsage) {
+	*out = *in
+	in.ObservedTime.DeepCopyInto(&out.ObservedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemUsage.
+func (in *FilesystemUsage) DeepCopy() *FilesystemUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSnapshot) DeepCopyInto(out *GroupSnapshot) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSnapshot.
+func (in *GroupSnapshot) DeepCopy() *GroupSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOLimits) DeepCopyInto(out *IOLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOLimits.
+func (in *IOLimits) DeepCopy() *IOLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(IOLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogicalVolume) DeepCopyInto(out *LogicalVolume) {
 	*out = *in
@@ -72,6 +140,31 @@ func (in *LogicalVolumeList) DeepCopyObject() runtime.Object {
 func (in *LogicalVolumeSpec) DeepCopyInto(out *LogicalVolumeSpec) {
 	*out = *in
 	out.Size = in.Size.DeepCopy()
+	if in.IOLimits != nil {
+		in, out := &in.IOLimits, &out.IOLimits
+		*out = new(IOLimits)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FSTrimInterval != nil {
+		in, out := &in.FSTrimInterval, &out.FSTrimInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MkfsOptions != nil {
+		in, out := &in.MkfsOptions, &out.MkfsOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupSnapshot != nil {
+		in, out := &in.GroupSnapshot, &out.GroupSnapshot
+		*out = new(GroupSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeSpec.
@@ -92,6 +185,41 @@ func (in *LogicalVolumeStatus) DeepCopyInto(out *LogicalVolumeStatus) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Filesystem != nil {
+		in, out := &in.Filesystem, &out.Filesystem
+		*out = new(FilesystemUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastFsckTime != nil {
+		in, out := &in.LastFsckTime, &out.LastFsckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Copy != nil {
+		in, out := &in.Copy, &out.Copy
+		*out = new(CopyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeStatus.
@@ -103,3 +231,18 @@ func (in *LogicalVolumeStatus) DeepCopy() *LogicalVolumeStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotScheduleSpec defines the desired state of SnapshotSchedule
+type SnapshotScheduleSpec struct {
+	// 'schedule' specifies when to take snapshots in the cron format of five fields,
+	// "minute hour day-of-month month day-of-week", or one of @hourly, @daily, @weekly, @monthly and @yearly.
+	// The schedule is evaluated in UTC.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// 'claimSelector' selects the PersistentVolumeClaims in the namespace to take snapshots of.
+	// All the PersistentVolumeClaims of TopoLVM in the namespace are selected if it is not set.
+	// +kubebuilder:validation:Optional
+	ClaimSelector *metav1.LabelSelector `json:"claimSelector,omitempty"`
+
+	// 'volumeSnapshotClassName' specifies the VolumeSnapshotClass of the snapshots.
+	// The default VolumeSnapshotClass is used if it is not set.
+	// +kubebuilder:validation:Optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// 'retention' specifies how long the snapshots taken by the schedule are kept.
+	// +kubebuilder:validation:Optional
+	Retention SnapshotRetention `json:"retention,omitempty"`
+
+	// 'thinPoolDataPercentLimit' pauses taking snapshots of the volumes in the thin pools
+	// whose data usage in percent is above the limit. The old snapshots are pruned even while paused.
+	// 0 pauses taking snapshots once the thin pools hold any data.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=90
+	ThinPoolDataPercentLimit *int32 `json:"thinPoolDataPercentLimit,omitempty"`
+
+	// 'suspend' stops taking snapshots while it is true. The old snapshots are pruned even while suspended.
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// SnapshotRetention specifies how long the snapshots of each PersistentVolumeClaim are kept.
+// The snapshots are kept forever if neither of the fields is set.
+type SnapshotRetention struct {
+	// 'maxCount' specifies the number of the latest snapshots kept for each PersistentVolumeClaim.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxCount *int32 `json:"maxCount,omitempty"`
+
+	// 'maxAge' specifies the age after which the snapshots are deleted, such as "168h".
+	// +kubebuilder:validation:Optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// SnapshotScheduleStatus defines the observed state of SnapshotSchedule
+type SnapshotScheduleStatus struct {
+	// 'lastScheduleTime' is the last time when the snapshots were taken.
+	// +kubebuilder:validation:Optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// 'nextScheduleTime' is the next time when the snapshots will be taken.
+	// +kubebuilder:validation:Optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// 'conditions' are the latest available observations of the schedule.
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Condition types of SnapshotSchedule.
+const (
+	// SnapshotScheduleScheduled indicates whether the schedule is valid and snapshots are taken as scheduled.
+	SnapshotScheduleScheduled = "Scheduled"
+	// SnapshotSchedulePaused indicates whether snapshots of some volumes were skipped because their thin pools
+	// are filling up.
+	SnapshotSchedulePaused = "Paused"
+)
+
+// Condition reasons of SnapshotSchedule.
+const (
+	ReasonScheduled        = "Scheduled"
+	ReasonInvalidSchedule  = "InvalidSchedule"
+	ReasonSuspended        = "Suspended"
+	ReasonThinPoolFull     = "ThinPoolFull"
+	ReasonThinPoolHasSpace = "ThinPoolHasSpace"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+//+kubebuilder:printcolumn:name="Last",type=date,JSONPath=`.status.lastScheduleTime`
+//+kubebuilder:printcolumn:name="Next",type=string,JSONPath=`.status.nextScheduleTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SnapshotSchedule is the Schema for the snapshotschedules API
+type SnapshotSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotScheduleSpec   `json:"spec,omitempty"`
+	Status SnapshotScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SnapshotScheduleList contains a list of SnapshotSchedule
+type SnapshotScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotSchedule{}, &SnapshotScheduleList{})
+}
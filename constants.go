@@ -30,6 +30,17 @@ func GetCapacityKeyPrefix() string {
 	return fmt.Sprintf("capacity.%s/", GetPluginName())
 }
 
+// GetThinPoolDataPercentKeyPrefix returns the key prefix of Node annotation that represents
+// the data usage of the thin pool of a device-class in percent.
+func GetThinPoolDataPercentKeyPrefix() string {
+	return fmt.Sprintf("thinpool.%s/data-percent-", GetPluginName())
+}
+
+// GetSnapshotScheduleKey returns the key of VolumeSnapshot label that represents the SnapshotSchedule taking the snapshot.
+func GetSnapshotScheduleKey() string {
+	return fmt.Sprintf("%s/snapshot-schedule", GetPluginName())
+}
+
 // GetCapacityResource returns the resource name of topolvm capacity.
 func GetCapacityResource() corev1.ResourceName {
 	return corev1.ResourceName(fmt.Sprintf("%s/capacity", GetPluginName()))
@@ -40,6 +51,12 @@ func GetTopologyNodeKey() string {
 	return fmt.Sprintf("topology.%s/node", GetPluginName())
 }
 
+// GetTransferEndpointKey returns the key of Node annotation that represents the address
+// where topolvm-node on the node serves the contents of volumes to other nodes.
+func GetTransferEndpointKey() string {
+	return fmt.Sprintf("transfer.%s/endpoint", GetPluginName())
+}
+
 // GetDeviceClassKey returns the key used in CSI volume create requests to specify a device-class.
 func GetDeviceClassKey() string {
 	return fmt.Sprintf("%s/device-class", GetPluginName())
@@ -50,6 +67,113 @@ func GetLvcreateOptionClassKey() string {
 	return fmt.Sprintf("%s/lvcreate-option-class", GetPluginName())
 }
 
+// GetReadIOPSKey returns the key used in CSI volume create requests to specify the read IOPS limit.
+func GetReadIOPSKey() string {
+	return fmt.Sprintf("%s/read-iops", GetPluginName())
+}
+
+// GetWriteIOPSKey returns the key used in CSI volume create requests to specify the write IOPS limit.
+func GetWriteIOPSKey() string {
+	return fmt.Sprintf("%s/write-iops", GetPluginName())
+}
+
+// GetReadBytesPerSecondKey returns the key used in CSI volume create requests to specify the read bandwidth limit.
+func GetReadBytesPerSecondKey() string {
+	return fmt.Sprintf("%s/read-bytes-per-second", GetPluginName())
+}
+
+// GetWriteBytesPerSecondKey returns the key used in CSI volume create requests to specify the write bandwidth limit.
+func GetWriteBytesPerSecondKey() string {
+	return fmt.Sprintf("%s/write-bytes-per-second", GetPluginName())
+}
+
+// GetReadAheadKey returns the key used in CSI volume create and modify requests to specify the read-ahead of the LV.
+func GetReadAheadKey() string {
+	return fmt.Sprintf("%s/read-ahead", GetPluginName())
+}
+
+// GetTagsKey returns the key used in CSI volume create and modify requests to specify comma-separated LVM tags.
+func GetTagsKey() string {
+	return fmt.Sprintf("%s/tags", GetPluginName())
+}
+
+// GetThinZeroingKey returns the key of thin zeroing.
+// It is always rejected because zeroing is a setting of the thin pool shared by all the volumes in the pool.
+func GetThinZeroingKey() string {
+	return fmt.Sprintf("%s/thin-zeroing", GetPluginName())
+}
+
+// GetEncryptedKey returns the key used in CSI volume create requests to specify whether the volume is encrypted.
+func GetEncryptedKey() string {
+	return fmt.Sprintf("%s/encrypted", GetPluginName())
+}
+
+// GetFSTrimKey returns the key used in CSI volume create and modify requests to specify whether
+// topolvm-node periodically runs fstrim on the filesystem of the volume.
+func GetFSTrimKey() string {
+	return fmt.Sprintf("%s/fstrim", GetPluginName())
+}
+
+// GetFSTrimIntervalKey returns the key used in CSI volume create and modify requests to specify the interval of fstrim.
+func GetFSTrimIntervalKey() string {
+	return fmt.Sprintf("%s/fstrim-interval", GetPluginName())
+}
+
+// GetMkfsBlockSizeKey returns the key used in CSI volume create requests to specify the block size of the filesystem.
+func GetMkfsBlockSizeKey() string {
+	return fmt.Sprintf("%s/mkfs-block-size", GetPluginName())
+}
+
+// GetMkfsInodeRatioKey returns the key used in CSI volume create requests to specify the bytes-per-inode ratio of ext4.
+func GetMkfsInodeRatioKey() string {
+	return fmt.Sprintf("%s/mkfs-inode-ratio", GetPluginName())
+}
+
+// GetMkfsReflinkKey returns the key used in CSI volume create requests to specify whether reflink is enabled on xfs.
+func GetMkfsReflinkKey() string {
+	return fmt.Sprintf("%s/mkfs-reflink", GetPluginName())
+}
+
+// GetMkfsJournalOptionsKey returns the key used in CSI volume create requests to specify the journal options of ext4
+// or the log options of xfs.
+func GetMkfsJournalOptionsKey() string {
+	return fmt.Sprintf("%s/mkfs-journal-options", GetPluginName())
+}
+
+// GetFsckPolicyKey returns the key used in CSI volume create requests to specify the policy to check the filesystem.
+func GetFsckPolicyKey() string {
+	return fmt.Sprintf("%s/fsck-policy", GetPluginName())
+}
+
+// GetFreezeFilesystemKey returns the key used in CSI snapshot create requests to specify
+// whether the filesystem of the source volume is frozen while the snapshot is taken.
+func GetFreezeFilesystemKey() string {
+	return fmt.Sprintf("%s/freeze-filesystem", GetPluginName())
+}
+
+// GetAutoResizeLimitKey returns the key of the PVC annotation that enables the automatic expansion of the PVC
+// and specifies the maximum size.
+func GetAutoResizeLimitKey() string {
+	return fmt.Sprintf("%s/auto-resize-limit", GetPluginName())
+}
+
+// GetAutoResizeThresholdKey returns the key of the PVC annotation that specifies the filesystem usage
+// in percent at which the PVC is expanded automatically.
+func GetAutoResizeThresholdKey() string {
+	return fmt.Sprintf("%s/auto-resize-threshold", GetPluginName())
+}
+
+// GetAutoResizeIncreaseKey returns the key of the PVC annotation that specifies the amount of the automatic expansion
+// either in percent of the current size or as a quantity.
+func GetAutoResizeIncreaseKey() string {
+	return fmt.Sprintf("%s/auto-resize-increase", GetPluginName())
+}
+
+// GetAutoResizedAtKey returns the key of the PVC annotation that represents the timestamp of the last automatic expansion.
+func GetAutoResizedAtKey() string {
+	return fmt.Sprintf("%s/auto-resized-at", GetPluginName())
+}
+
 // GetResizeRequestedAtKey returns the key of LogicalVolume that represents the timestamp of the resize request.
 func GetResizeRequestedAtKey() string {
 	return fmt.Sprintf("%s/resize-requested-at", GetPluginName())
@@ -60,6 +184,35 @@ func GetLVPendingDeletionKey() string {
 	return fmt.Sprintf("%s/pendingdeletion", GetPluginName())
 }
 
+// GetAdoptLVNameKey returns the key of LogicalVolume annotation that specifies the name of an existing LV to be adopted.
+func GetAdoptLVNameKey() string {
+	return fmt.Sprintf("%s/adopt-lv-name", GetPluginName())
+}
+
+// GetRebindToKey returns the key of PersistentVolume annotation that specifies the PVC to re-bind a released volume to.
+// The value is in the form of "<namespace>/<name>".
+func GetRebindToKey() string {
+	return fmt.Sprintf("%s/rebind-to", GetPluginName())
+}
+
+// GetMigrateToKey returns the key of PersistentVolumeClaim annotation that requests to migrate the volume to the node.
+// The annotation is copied to the source LogicalVolume while the migration is in progress.
+func GetMigrateToKey() string {
+	return fmt.Sprintf("%s/migrate-to", GetPluginName())
+}
+
+// GetMigrationClaimKey returns the key of LogicalVolume annotation that holds the PersistentVolumeClaim
+// to be re-created at the end of the migration.
+func GetMigrationClaimKey() string {
+	return fmt.Sprintf("%s/migration-claim", GetPluginName())
+}
+
+// GetSyncRequestedKey returns the key of LogicalVolume annotation that requests to apply the changes of
+// the source volume made after the volume was copied.
+func GetSyncRequestedKey() string {
+	return fmt.Sprintf("%s/sync-requested", GetPluginName())
+}
+
 // GetLogicalVolumeFinalizer returns the name of LogicalVolume finalizer
 func GetLogicalVolumeFinalizer() string {
 	return fmt.Sprintf("%s/logicalvolume", GetPluginName())
@@ -70,12 +223,36 @@ func GetNodeFinalizer() string {
 	return fmt.Sprintf("%s/node", GetPluginName())
 }
 
+// LVOwnerTagPrefix is the prefix of the LVM tag that records the name of the LogicalVolume owning the LV.
+// It does not depend on UseLegacy so that the tag survives the migration from the legacy plugin name.
+const LVOwnerTagPrefix = pluginName + "/logicalvolume="
+
+// GetLVOwnerTag returns the LVM tag that records the LogicalVolume owning the LV.
+func GetLVOwnerTag(lvName string) string {
+	return LVOwnerTagPrefix + lvName
+}
+
+// Prefixes of the LVM tags that record the Kubernetes objects the LV is provisioned for.
+// Like LVOwnerTagPrefix, they do not depend on UseLegacy.
+const (
+	LVPVCNameTagPrefix      = pluginName + "/pvc-name="
+	LVPVCNamespaceTagPrefix = pluginName + "/pvc-namespace="
+	LVPVNameTagPrefix       = pluginName + "/pv-name="
+)
+
+// LVReservedTagPrefix is the prefix of the LVM tags managed by TopoLVM.
+// User-specified tags must not have it.
+const LVReservedTagPrefix = pluginName + "/"
+
 // PVCFinalizer is a finalizer of PVC.
 const PVCFinalizer = pluginName + "/pvc"
 
 // LegacyPVCFinalizer is a legacy finalizer of PVC.
 const LegacyPVCFinalizer = legacyPluginName + "/pvc"
 
+// DeviceDirectory is a directory where TopoLVM Node service creates device files.
+const DeviceDirectory = "/dev/topolvm"
+
 // DefaultCSISocket is the default path of the CSI socket file.
 const DefaultCSISocket = "/run/topolvm/csi-topolvm.sock"
 
@@ -94,6 +271,9 @@ const DefaultSizeGb = 1
 // DefaultSize is DefaultSizeGb in bytes
 const DefaultSize = int64(DefaultSizeGb << 30)
 
+// MinimumSectorSize is the unit of volume sizes. The requested sizes are rounded up to a multiple of it.
+const MinimumSectorSize = 4096
+
 // Label key that indicates The controller/user who created this resource
 // https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/#labels
 const CreatedbyLabelKey = "app.kubernetes.io/created-by"
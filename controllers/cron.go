@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron expression of five fields.
+// Each field is a bit set of the values that match.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the field is "*", so that the other field alone restricts the days.
+	domStar, dowStar bool
+}
+
+// cronField is the range of the values of a field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	cronMinute = cronField{"minute", 0, 59}
+	cronHour   = cronField{"hour", 0, 23}
+	cronDom    = cronField{"day of month", 1, 31}
+	cronMonth  = cronField{"month", 1, 12}
+	// Both 0 and 7 are Sunday.
+	cronDow = cronField{"day of week", 0, 7}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxCronSearch limits how far the next time is searched, so that expressions like "0 0 30 2 *" never match.
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+// parseCronSchedule parses a cron expression.
+// Each field is "*", a value, a range "a-b", or either of them followed by a step "/n", and they can be
+// combined by commas. The day of month and the day of week match if either of them matches when both are restricted.
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields: %q", expr)
+	}
+
+	s := &cronSchedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	for i, p := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&s.minute, cronMinute},
+		{&s.hour, cronHour},
+		{&s.dom, cronDom},
+		{&s.month, cronMonth},
+		{&s.dow, cronDow},
+	} {
+		bits, err := parseCronField(fields[i], p.field)
+		if err != nil {
+			return nil, err
+		}
+		*p.bits = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, term := range strings.Split(value, ",") {
+		rng, stepStr, hasStep := strings.Cut(term, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s: %q", field.name, term)
+			}
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			lo, hi, _ := strings.Cut(rng, "-")
+			var err1, err2 error
+			start, err1 = strconv.Atoi(lo)
+			end, err2 = strconv.Atoi(hi)
+			if err1 != nil || err2 != nil || start > end {
+				return 0, fmt.Errorf("invalid range in %s: %q", field.name, term)
+			}
+		default:
+			v, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s: %q", field.name, term)
+			}
+			start = v
+			if !hasStep {
+				end = v
+			}
+		}
+		if start < field.min || end > field.max {
+			return 0, fmt.Errorf("%s must be between %d and %d: %q", field.name, field.min, field.max, term)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the earliest time after t that matches the schedule in UTC, or zero time if there is none.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronSearch)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/topolvm/topolvm/filesystem"
+	mountutil "k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
+)
+
+const fsfreezeCmd = "/sbin/fsfreeze"
+
+// DefaultFreezeTimeout is the default maximum duration for which a filesystem is frozen to take a snapshot.
+const DefaultFreezeTimeout = 10 * time.Second
+
+// errFreezeTimedOut is returned when the filesystem was thawed by the timeout before the snapshot was taken.
+var errFreezeTimedOut = errors.New("filesystem was thawed because the freeze timed out")
+
+// findFreezeMountPoint returns the path where device is mounted read-write.
+// It returns an empty string if device is not mounted or mounted only read-only, since such a filesystem is not written.
+func findFreezeMountPoint(mounts []mountutil.MountPoint, device string) (string, error) {
+	for _, m := range mounts {
+		if m.Device != device {
+			continue
+		}
+		readOnly := false
+		for _, opt := range m.Opts {
+			if opt == "ro" {
+				readOnly = true
+				break
+			}
+		}
+		if readOnly {
+			continue
+		}
+		// Confirm that the mount point still refers to the device because the device file may have been re-created.
+		mounted, err := filesystem.IsMounted(device, m.Path)
+		if err != nil {
+			return "", err
+		}
+		if mounted {
+			return m.Path, nil
+		}
+	}
+	return "", nil
+}
+
+// freezeFilesystem freezes the filesystem mounted at mountPoint and returns the function to thaw it.
+// The filesystem is thawed automatically after timeout so that writes are never blocked indefinitely.
+// In that case, the returned function reports errFreezeTimedOut.
+func freezeFilesystem(exec utilexec.Interface, mountPoint string, timeout time.Duration) (func() error, error) {
+	out, err := exec.Command(fsfreezeCmd, "-f", mountPoint).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("fsfreeze -f failed: output=%s, error=%v", string(out), err)
+	}
+
+	var once sync.Once
+	var timedOut bool
+	var thawErr error
+	thaw := func(expired bool) {
+		once.Do(func() {
+			timedOut = expired
+			out, err := exec.Command(fsfreezeCmd, "-u", mountPoint).CombinedOutput()
+			if err != nil {
+				thawErr = fmt.Errorf("fsfreeze -u failed: output=%s, error=%v", string(out), err)
+			}
+		})
+	}
+	timer := time.AfterFunc(timeout, func() { thaw(true) })
+
+	return func() error {
+		timer.Stop()
+		thaw(false)
+		if thawErr != nil {
+			return thawErr
+		}
+		if timedOut {
+			return errFreezeTimedOut
+		}
+		return nil
+	}, nil
+}
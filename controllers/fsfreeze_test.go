@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	utilexec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+type fakeFsfreeze struct {
+	mu       sync.Mutex
+	commands [][]string
+}
+
+func (f *fakeFsfreeze) exec(statuses ...int) *testingexec.FakeExec {
+	fakeExec := &testingexec.FakeExec{}
+	for _, code := range statuses {
+		code := code
+		fakeCmd := &testingexec.FakeCmd{
+			CombinedOutputScript: []testingexec.FakeAction{
+				func() ([]byte, []byte, error) {
+					if code == 0 {
+						return nil, nil, nil
+					}
+					return []byte("error"), nil, &testingexec.FakeExitError{Status: code}
+				},
+			},
+		}
+		fakeExec.CommandScript = append(fakeExec.CommandScript, func(cmd string, args ...string) utilexec.Cmd {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.commands = append(f.commands, append([]string{cmd}, args...))
+			return testingexec.InitFakeCmd(fakeCmd, cmd, args...)
+		})
+	}
+	return fakeExec
+}
+
+func (f *fakeFsfreeze) getCommands() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commands
+}
+
+var _ = Describe("freezeFilesystem", func() {
+	It("should thaw the filesystem", func() {
+		f := &fakeFsfreeze{}
+		thaw, err := freezeFilesystem(f.exec(0, 0), "/mnt", time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.getCommands()).To(Equal([][]string{{fsfreezeCmd, "-f", "/mnt"}}))
+
+		err = thaw()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.getCommands()).To(Equal([][]string{{fsfreezeCmd, "-f", "/mnt"}, {fsfreezeCmd, "-u", "/mnt"}}))
+	})
+
+	It("should thaw the filesystem after the timeout", func() {
+		f := &fakeFsfreeze{}
+		thaw, err := freezeFilesystem(f.exec(0, 0), "/mnt", 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(f.getCommands).Should(HaveLen(2))
+
+		err = thaw()
+		Expect(err).To(MatchError(errFreezeTimedOut))
+		Expect(f.getCommands()).To(Equal([][]string{{fsfreezeCmd, "-f", "/mnt"}, {fsfreezeCmd, "-u", "/mnt"}}))
+	})
+
+	It("should report the failures", func() {
+		f := &fakeFsfreeze{}
+		_, err := freezeFilesystem(f.exec(1), "/mnt", time.Minute)
+		Expect(err).To(HaveOccurred())
+
+		f = &fakeFsfreeze{}
+		thaw, err := freezeFilesystem(f.exec(0, 1), "/mnt", time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		err = thaw()
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(MatchError(errFreezeTimedOut))
+	})
+})
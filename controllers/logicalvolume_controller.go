@@ -2,7 +2,12 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-logr/logr"
 	"github.com/topolvm/topolvm"
@@ -11,10 +16,18 @@ import (
 	"github.com/topolvm/topolvm/lvmd/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	mountutil "k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -22,32 +35,113 @@ import (
 	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// Event reasons recorded on LogicalVolume.
+const (
+	EventReasonCreated      = "Created"
+	EventReasonAdopted      = "Adopted"
+	EventReasonCreateFailed = "CreateFailed"
+	EventReasonResized      = "Resized"
+	EventReasonResizeFailed = "ResizeFailed"
+	EventReasonModified     = "Modified"
+	EventReasonModifyFailed = "ModifyFailed"
+	EventReasonWiping       = "Wiping"
+	EventReasonRemoved      = "Removed"
+	EventReasonRemoveFailed = "RemoveFailed"
+
+	EventReasonFilesystemFrozen = "FilesystemFrozen"
+	EventReasonFreezeFailed     = "FreezeFailed"
+
+	EventReasonCopying    = "Copying"
+	EventReasonSyncing    = "Syncing"
+	EventReasonSynced     = "Synced"
+	EventReasonSyncFailed = "SyncFailed"
+)
+
+// maxConditionMessageLength limits the length of condition messages that may contain LVM stderr.
+const maxConditionMessageLength = 1024
+
+// wipePollInterval is the interval to check the progress of wiping LV before removal.
+const wipePollInterval = 10 * time.Second
+
+// createRetryPolicy defines how to retry a failed creation of an LVM logical volume.
+type createRetryPolicy struct {
+	// maxAttempts is the maximum number of attempts including the first one.
+	maxAttempts int32
+	// initialBackoff is the interval before the first retry.
+	initialBackoff time.Duration
+	// maxBackoff is the upper limit of the interval between retries.
+	maxBackoff time.Duration
+}
+
+var defaultCreateRetryPolicy = createRetryPolicy{
+	maxAttempts:    5,
+	initialBackoff: 2 * time.Second,
+	maxBackoff:     time.Minute,
+}
+
+// backoff returns the interval before the next retry after the given number of attempts.
+func (p createRetryPolicy) backoff(attempts int32) time.Duration {
+	d := p.initialBackoff
+	for i := int32(1); i < attempts; i++ {
+		d *= 2
+		if d >= p.maxBackoff {
+			return p.maxBackoff
+		}
+	}
+	return d
+}
+
+// shouldRetry returns true if the creation failed with code should be retried after the given number of attempts.
+// Internal and Unavailable are returned for transient failures such as lock contention or udev timeout.
+// Other codes such as ResourceExhausted or InvalidArgument will not be resolved by retrying.
+func (p createRetryPolicy) shouldRetry(code codes.Code, attempts int32) bool {
+	switch code {
+	case codes.Internal, codes.Unavailable:
+		return attempts < p.maxAttempts
+	}
+	return false
+}
+
 // LogicalVolumeReconciler reconciles a LogicalVolume object
 type LogicalVolumeReconciler struct {
-	client    client.Client
-	nodeName  string
-	vgService proto.VGServiceClient
-	lvService proto.LVServiceClient
+	client      client.Client
+	recorder    record.EventRecorder
+	nodeName    string
+	vgService   proto.VGServiceClient
+	lvService   proto.LVServiceClient
+	retryPolicy createRetryPolicy
+
+	mounter       mountutil.Interface
+	exec          utilexec.Interface
+	freezeTimeout time.Duration
+
+	transferCreds credentials.TransportCredentials
+	copyJobs      copyJobs
 }
 
 //+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // NewLogicalVolumeReconciler returns LogicalVolumeReconciler with creating lvService and vgService.
-func NewLogicalVolumeReconciler(client client.Client, nodeName string, conn *grpc.ClientConn) *LogicalVolumeReconciler {
-	return &LogicalVolumeReconciler{
-		client:    client,
-		nodeName:  nodeName,
-		vgService: proto.NewVGServiceClient(conn),
-		lvService: proto.NewLVServiceClient(conn),
-	}
+// freezeTimeout is the maximum duration for which the filesystem of a source volume is frozen to take a snapshot.
+// transferCreds is used to copy source volumes from other nodes. If it is nil, such volumes are not created.
+func NewLogicalVolumeReconciler(client client.Client, recorder record.EventRecorder, nodeName string, conn *grpc.ClientConn, freezeTimeout time.Duration, transferCreds credentials.TransportCredentials) *LogicalVolumeReconciler {
+	return NewLogicalVolumeReconcilerWithServices(client, recorder, nodeName, proto.NewVGServiceClient(conn), proto.NewLVServiceClient(conn), freezeTimeout, transferCreds)
 }
-func NewLogicalVolumeReconcilerWithServices(client client.Client, nodeName string, vgService proto.VGServiceClient, lvService proto.LVServiceClient) *LogicalVolumeReconciler {
+func NewLogicalVolumeReconcilerWithServices(client client.Client, recorder record.EventRecorder, nodeName string, vgService proto.VGServiceClient, lvService proto.LVServiceClient, freezeTimeout time.Duration, transferCreds credentials.TransportCredentials) *LogicalVolumeReconciler {
 	return &LogicalVolumeReconciler{
-		client:    client,
-		nodeName:  nodeName,
-		vgService: vgService,
-		lvService: lvService,
+		client:        client,
+		recorder:      recorder,
+		nodeName:      nodeName,
+		vgService:     vgService,
+		lvService:     lvService,
+		retryPolicy:   defaultCreateRetryPolicy,
+		mounter:       mountutil.New(""),
+		exec:          utilexec.New(),
+		freezeTimeout: freezeTimeout,
+		transferCreds: transferCreds,
 	}
 }
 
@@ -76,6 +170,13 @@ func (r *LogicalVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			} else {
 				log.Info("skipping finalizer for logical volume due to its pending deletion", "name", lv.Name)
 			}
+			if !meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeDeletionPending) {
+				setStatusCondition(lv, topolvmv1.LogicalVolumeDeletionPending, metav1.ConditionTrue, topolvmv1.ReasonPendingDeletionAnnotation, "")
+				if err := r.client.Status().Update(ctx, lv); err != nil {
+					log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+					return ctrl.Result{}, err
+				}
+			}
 			return ctrl.Result{}, nil
 		}
 	}
@@ -107,16 +208,29 @@ func (r *LogicalVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 
 		if lv.Status.VolumeID == "" {
-			err := r.createLV(ctx, log, lv)
+			result, err := r.createLV(ctx, log, lv)
 			if err != nil {
 				log.Error(err, "failed to create LV", "name", lv.Name)
 			}
-			return ctrl.Result{}, err
+			return result, err
+		}
+
+		if _, ok := lv.Annotations[topolvm.GetSyncRequestedKey()]; ok {
+			// The sync is not retried once it fails, so that the migration waiting for it can give up.
+			if synced := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeSynced); synced == nil || synced.Reason == topolvmv1.ReasonSyncing {
+				return r.syncLV(ctx, log, lv)
+			}
 		}
 
 		err := r.expandLV(ctx, log, lv)
 		if err != nil {
 			log.Error(err, "failed to expand LV", "name", lv.Name)
+			return ctrl.Result{}, err
+		}
+
+		err = r.modifyLV(ctx, log, lv)
+		if err != nil {
+			log.Error(err, "failed to modify LV", "name", lv.Name)
 		}
 		return ctrl.Result{}, err
 	}
@@ -128,10 +242,38 @@ func (r *LogicalVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	log.Info("start finalizing LogicalVolume", "name", lv.Name)
-	err := r.removeLVIfExists(ctx, log, lv)
+	r.copyJobs.remove(lv.UID)
+	if !meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeDeletionPending) {
+		setStatusCondition(lv, topolvmv1.LogicalVolumeDeletionPending, metav1.ConditionTrue, topolvmv1.ReasonDeleting, "")
+		if err := r.client.Status().Update(ctx, lv); err != nil {
+			log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+			return ctrl.Result{}, err
+		}
+	}
+
+	resp, err := r.removeLVIfExists(ctx, log, lv)
 	if err != nil {
+		setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionTrue, topolvmv1.ReasonRemoveFailed, err.Error())
+		if err2 := r.client.Status().Update(ctx, lv); err2 != nil {
+			// err2 is logged but not returned because err is more important
+			log.Error(err2, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		}
 		return ctrl.Result{}, err
 	}
+	if resp.GetWiping() {
+		// The finalizer is kept until lvmd finishes wiping and removes the LV,
+		// so that the capacity is not reused before that.
+		if !meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeWiping) {
+			r.recordEvent(lv, corev1.EventTypeNormal, EventReasonWiping, "wiping LV %s before removal", lv.UID)
+		}
+		setStatusCondition(lv, topolvmv1.LogicalVolumeWiping, metav1.ConditionTrue, topolvmv1.ReasonWiping,
+			fmt.Sprintf("wiped %d of %d bytes", resp.GetWipedBytes(), resp.GetTotalBytes()))
+		if err := r.client.Status().Update(ctx, lv); err != nil {
+			log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: wipePollInterval}, nil
+	}
 
 	lv2 := lv.DeepCopy()
 	controllerutil.RemoveFinalizer(lv2, topolvm.GetLogicalVolumeFinalizer())
@@ -154,77 +296,412 @@ func (r *LogicalVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return builder.WithEventFilter(&logicalVolumeFilter{r.nodeName}).Complete(r)
 }
 
-func (r *LogicalVolumeReconciler) removeLVIfExists(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+// createSnapshot creates a snapshot LV of the source volume on this node.
+// If lv.Spec.FreezeFilesystem is true, the filesystem of the source volume is frozen while the snapshot is taken.
+func (r *LogicalVolumeReconciler) createSnapshot(ctx context.Context, log logr.Logger, lv, sourcelv *topolvmv1.LogicalVolume, reqBytes int64) (*proto.LogicalVolume, error) {
+	sourceVolID := sourcelv.Status.VolumeID
+	snapshotCtx := ctx
+	var thaw func() error
+	if lv.Spec.FreezeFilesystem {
+		var err error
+		thaw, err = r.freezeSourceFilesystem(sourceVolID)
+		if err != nil {
+			log.Error(err, "failed to freeze filesystem", "name", lv.Name, "source", sourceVolID)
+			r.recordEvent(lv, corev1.EventTypeWarning, EventReasonFreezeFailed, "failed to freeze filesystem of %s: %v", sourceVolID, err)
+			lv.Status.Code = codes.Internal
+			lv.Status.Message = "failed to freeze filesystem"
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if thaw != nil {
+			var cancel context.CancelFunc
+			snapshotCtx, cancel = context.WithTimeout(ctx, r.freezeTimeout)
+			defer cancel()
+		}
+	}
+
+	// Create a snapshot lv
+	resp, err := r.lvService.CreateLVSnapshot(snapshotCtx, &proto.CreateLVSnapshotRequest{
+		Name:         string(lv.UID),
+		DeviceClass:  lv.Spec.DeviceClass,
+		SourceVolume: sourcelv.LVName(),
+		SizeGb:       sizeGB(reqBytes),
+		SizeBytes:    uint64(reqBytes),
+		Tags:         lvTags(lv),
+		AccessType:   lv.Spec.AccessType,
+	})
+	if thaw != nil {
+		var snapshotName string
+		if err == nil {
+			snapshotName = resp.Snapshot.Name
+		}
+		if thawErr := r.thawSourceFilesystem(ctx, lv, sourceVolID, snapshotName, thaw); thawErr != nil {
+			lv.Status.Code = codes.Unavailable
+			lv.Status.Message = "the filesystem was thawed before the snapshot was taken"
+			return nil, thawErr
+		}
+	}
+	if err != nil {
+		code, message := extractFromError(err)
+		log.Error(err, message)
+		lv.Status.Code = code
+		lv.Status.Message = message
+		return nil, err
+	}
+	return resp.Snapshot, nil
+}
+
+// startCopy starts copying the contents of sourcelv on another node into a new LV in the background,
+// and returns errCopyInProgress. The contents are read from a temporary snapshot of sourcelv taken on the node,
+// so that the copy is consistent even if sourcelv is written during the copy.
+func (r *LogicalVolumeReconciler) startCopy(ctx context.Context, log logr.Logger, lv, sourcelv *topolvmv1.LogicalVolume, reqBytes int64) error {
+	conn, err := r.dialTransfer(ctx, log, sourcelv.Spec.NodeName)
+	if err != nil {
+		lv.Status.Code, lv.Status.Message = extractFromError(err)
+		return err
+	}
+
+	exportReq := &proto.ExportLVRequest{
+		Name:        sourcelv.LVName(),
+		DeviceClass: sourcelv.Spec.DeviceClass,
+		SnapshotId:  string(lv.UID),
+	}
+	importReq := &proto.ImportLVRequest{
+		Volume: &proto.CreateLVRequest{
+			Name:                string(lv.UID),
+			DeviceClass:         lv.Spec.DeviceClass,
+			LvcreateOptionClass: lv.Spec.LvcreateOptionClass,
+			SizeGb:              sizeGB(reqBytes),
+			SizeBytes:           uint64(reqBytes),
+			Tags:                lvTags(lv),
+		},
+	}
+	r.copyJobs.start(lv.UID, func(ctx context.Context, job *copyJob) (*proto.LogicalVolume, error) {
+		defer conn.Close()
+		return copyVolume(ctx, proto.NewLVServiceClient(conn), r.lvService, exportReq, importReq, job)
+	})
+	lv.Status.Copy = &topolvmv1.CopyStatus{
+		Mode:           topolvmv1.CopyCreate,
+		SourceVolumeID: sourcelv.Status.VolumeID,
+		SourceNodeName: sourcelv.Spec.NodeName,
+		StartTime:      metav1.Now(),
+	}
+	log.Info("started copying volume from another node", "name", lv.Name, "uid", lv.UID, "source", sourcelv.Status.VolumeID, "node", sourcelv.Spec.NodeName)
+	r.recordEvent(lv, corev1.EventTypeNormal, EventReasonCopying, "copying %s from node %s", sourcelv.Status.VolumeID, sourcelv.Spec.NodeName)
+	return errCopyInProgress
+}
+
+// dialTransfer connects to topolvm-node on nodeName to read the volumes on the node.
+func (r *LogicalVolumeReconciler) dialTransfer(ctx context.Context, log logr.Logger, nodeName string) (*grpc.ClientConn, error) {
+	if r.transferCreds == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "volume transfer is not enabled on this node to read volumes on node %s", nodeName)
+	}
+	node := new(corev1.Node)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		log.Error(err, "unable to fetch source node", "node", nodeName)
+		return nil, err
+	}
+	endpoint := node.Annotations[topolvm.GetTransferEndpointKey()]
+	if endpoint == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "node %s does not serve volume transfer", nodeName)
+	}
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(r.transferCreds))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to connect to node %s: %v", nodeName, err)
+	}
+	return conn, nil
+}
+
+// waitForCopy records the progress of job and requeues lv to check it again.
+func (r *LogicalVolumeReconciler) waitForCopy(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, job *copyJob) (ctrl.Result, error) {
+	recordCopyProgress(lv, job)
+	setStatusCondition(lv, topolvmv1.LogicalVolumeCreated, metav1.ConditionFalse, topolvmv1.ReasonCopying,
+		fmt.Sprintf("copied %d of %d bytes", job.copied.Load(), job.total.Load()))
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: copyPollInterval}, nil
+}
+
+// recordCopyProgress records the progress of job in lv.Status.Copy.
+func recordCopyProgress(lv *topolvmv1.LogicalVolume, job *copyJob) {
+	if lv.Status.Copy == nil {
+		return
+	}
+	lv.Status.Copy.CopiedBytes = int64(job.copied.Load())
+	lv.Status.Copy.TotalBytes = int64(job.total.Load())
+}
+
+// errCopyInterrupted is returned when lv.Status.Copy is found without the copy job after a restart of topolvm-node.
+// lvmd removes the partial copy when the import is interrupted, so the copy can start over.
+var errCopyInterrupted = status.Error(codes.Unavailable, "copying volume was interrupted by a restart of topolvm-node")
+
+// finishCopy sets the result of the finished job to lv.
+func (r *LogicalVolumeReconciler) finishCopy(log logr.Logger, lv *topolvmv1.LogicalVolume, job *copyJob, reqBytes int64) error {
+	lv.Status.Copy = nil
+	if job.err != nil {
+		code, message := extractFromError(job.err)
+		log.Error(job.err, message)
+		lv.Status.Code = code
+		lv.Status.Message = message
+		return job.err
+	}
+	lv.Status.VolumeID = string(lv.UID)
+	lv.Status.LVName = job.volume.Name
+	lv.Status.CurrentSize = resource.NewQuantity(volumeBytes(job.volume, reqBytes), resource.BinarySI)
+	lv.Status.Code = codes.OK
+	lv.Status.Message = ""
+	return nil
+}
+
+// syncLV applies the changes of the source volume made after lv was copied from its snapshot lv.Spec.Source.
+// The changes are copied in the background, and the result is recorded in the Synced condition.
+func (r *LogicalVolumeReconciler) syncLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (ctrl.Result, error) {
+	job := r.copyJobs.get(lv.UID)
+	if job == nil {
+		if lv.Status.Copy != nil {
+			// The sync writes the changed ranges again, so the sync interrupted by a restart just starts over.
+			log.Info("restarting interrupted sync", "name", lv.Name, "uid", lv.UID, "copied", lv.Status.Copy.CopiedBytes)
+		}
+		var err error
+		job, err = r.startSync(ctx, log, lv)
+		if err != nil {
+			log.Error(err, "failed to start sync", "name", lv.Name, "uid", lv.UID)
+			return ctrl.Result{}, err
+		}
+	}
+
+	result := ctrl.Result{}
+	switch {
+	case !job.finished():
+		recordCopyProgress(lv, job)
+		setStatusCondition(lv, topolvmv1.LogicalVolumeSynced, metav1.ConditionFalse, topolvmv1.ReasonSyncing,
+			fmt.Sprintf("copied %d of %d bytes", job.copied.Load(), job.total.Load()))
+		result.RequeueAfter = copyPollInterval
+	case job.err != nil:
+		r.copyJobs.remove(lv.UID)
+		lv.Status.Copy = nil
+		_, message := extractFromError(job.err)
+		log.Error(job.err, "failed to sync LV", "name", lv.Name, "uid", lv.UID)
+		setStatusCondition(lv, topolvmv1.LogicalVolumeSynced, metav1.ConditionFalse, topolvmv1.ReasonSyncFailed, message)
+		r.recordEvent(lv, corev1.EventTypeWarning, EventReasonSyncFailed, "failed to sync LV %s: %v", lv.UID, job.err)
+	default:
+		r.copyJobs.remove(lv.UID)
+		lv.Status.Copy = nil
+		log.Info("synced LV", "name", lv.Name, "uid", lv.UID)
+		setStatusCondition(lv, topolvmv1.LogicalVolumeSynced, metav1.ConditionTrue, topolvmv1.ReasonSynced, "")
+		r.recordEvent(lv, corev1.EventTypeNormal, EventReasonSynced, "applied the changes of the source volume to LV %s", lv.UID)
+	}
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// startSync starts copying the ranges of the source volume changed since the snapshot lv.Spec.Source into lv.
+func (r *LogicalVolumeReconciler) startSync(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (*copyJob, error) {
+	snapshot := new(topolvmv1.LogicalVolume)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: lv.Spec.Source}, snapshot); err != nil {
+		log.Error(err, "unable to fetch snapshot", "name", lv.Name, "snapshot", lv.Spec.Source)
+		return nil, err
+	}
+	source := new(topolvmv1.LogicalVolume)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: snapshot.Spec.Source}, source); err != nil {
+		log.Error(err, "unable to fetch source", "name", lv.Name, "source", snapshot.Spec.Source)
+		return nil, err
+	}
+	conn, err := r.dialTransfer(ctx, log, source.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	exportReq := &proto.ExportLVRequest{
+		Name:        source.LVName(),
+		DeviceClass: source.Spec.DeviceClass,
+		BaseVolume:  snapshot.LVName(),
+	}
+	importReq := &proto.ImportLVRequest{
+		Volume: &proto.CreateLVRequest{
+			Name:        lv.LVName(),
+			DeviceClass: lv.Spec.DeviceClass,
+			SizeBytes:   uint64(lv.Status.CurrentSize.Value()),
+		},
+		Update: true,
+	}
+	job := r.copyJobs.start(lv.UID, func(ctx context.Context, job *copyJob) (*proto.LogicalVolume, error) {
+		defer conn.Close()
+		return copyVolume(ctx, proto.NewLVServiceClient(conn), r.lvService, exportReq, importReq, job)
+	})
+	lv.Status.Copy = &topolvmv1.CopyStatus{
+		Mode:           topolvmv1.CopySync,
+		SourceVolumeID: source.Status.VolumeID,
+		SourceNodeName: source.Spec.NodeName,
+		StartTime:      metav1.Now(),
+	}
+	log.Info("started syncing volume from another node", "name", lv.Name, "uid", lv.UID, "source", source.Status.VolumeID, "node", source.Spec.NodeName)
+	r.recordEvent(lv, corev1.EventTypeNormal, EventReasonSyncing, "syncing changes of %s from node %s", source.Status.VolumeID, source.Spec.NodeName)
+	return job, nil
+}
+
+// removeLVIfExists removes the LV of lv.
+// If lvmd is wiping the LV, the returned response tells the progress and the LV is not removed yet.
+func (r *LogicalVolumeReconciler) removeLVIfExists(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (*proto.RemoveLVResponse, error) {
 	// Finalizer's process ( RemoveLV then removeString ) is not atomic,
 	// so checking existence of LV to ensure its idempotence
 	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
 	if err != nil {
 		log.Error(err, "failed to list LV")
-		return err
+		return nil, err
 	}
 
-	for _, v := range respList.Volumes {
-		if v.Name != string(lv.UID) {
-			continue
-		}
-		_, err := r.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: string(lv.UID), DeviceClass: lv.Spec.DeviceClass})
+	if v := findLV(respList.Volumes, lv); v != nil {
+		resp, err := r.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: v.Name, DeviceClass: lv.Spec.DeviceClass, AsyncWipe: true})
 		if err != nil {
 			log.Error(err, "failed to remove LV", "name", lv.Name, "uid", lv.UID)
-			return err
+			r.recordEvent(lv, corev1.EventTypeWarning, EventReasonRemoveFailed, "failed to remove LV %s: %v", lv.UID, err)
+			return nil, err
+		}
+		if resp.GetWiping() {
+			log.Info("wiping LV", "name", lv.Name, "uid", lv.UID, "wiped", resp.GetWipedBytes(), "total", resp.GetTotalBytes())
+			return resp, nil
 		}
 		log.Info("removed LV", "name", lv.Name, "uid", lv.UID)
-		return nil
+		r.recordEvent(lv, corev1.EventTypeNormal, EventReasonRemoved, "removed LV %s", lv.UID)
+		return resp, nil
+	}
+	if meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeWiping) {
+		// lvmd removes the LV by itself once the wipe finishes.
+		log.Info("removed LV after wiping", "name", lv.Name, "uid", lv.UID)
+		r.recordEvent(lv, corev1.EventTypeNormal, EventReasonRemoved, "removed LV %s", lv.UID)
+		return &proto.RemoveLVResponse{}, nil
 	}
 	log.Info("LV already removed", "name", lv.Name, "uid", lv.UID)
-	return nil
+	return &proto.RemoveLVResponse{}, nil
 }
 
-func (r *LogicalVolumeReconciler) volumeExists(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (bool, error) {
+// findVolume returns the LV of lv, or nil if it does not exist.
+func (r *LogicalVolumeReconciler) findVolume(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (*proto.LogicalVolume, error) {
 	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
 	if err != nil {
 		log.Error(err, "failed to get list of LV")
-		return false, err
+		return nil, err
 	}
+	return findLV(respList.Volumes, lv), nil
+}
 
-	for _, v := range respList.Volumes {
-		if v.Name != string(lv.UID) {
-			continue
+// findLV returns the LV of lv in volumes, or nil if it is not found.
+// Until status.lvName is recorded, the LV may have been named by lv-name-template of lvmd,
+// so the LV tagged with the owner is also looked for.
+func findLV(volumes []*proto.LogicalVolume, lv *topolvmv1.LogicalVolume) *proto.LogicalVolume {
+	name := lv.LVName()
+	for _, v := range volumes {
+		if v.Name == name {
+			return v
 		}
-		return true, nil
 	}
-	return false, nil
+	if lv.Status.LVName != "" {
+		return nil
+	}
+	owner := topolvm.GetLVOwnerTag(lv.Name)
+	for _, v := range volumes {
+		for _, tag := range v.Tags {
+			if tag == owner {
+				return v
+			}
+		}
+	}
+	return nil
 }
 
-func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (ctrl.Result, error) {
+	// The volume being copied from another node is created when the copy finishes.
+	job := r.copyJobs.get(lv.UID)
+	if job != nil {
+		if !job.finished() {
+			return r.waitForCopy(ctx, log, lv, job)
+		}
+		r.copyJobs.remove(lv.UID)
+	}
+
 	// When lv.Status.Code is not codes.OK (== 0), CreateLV has already failed.
-	// LogicalVolume CRD will be deleted soon by the controller.
 	if lv.Status.Code != codes.OK {
-		return nil
+		// Without status.nextRetryTime, the failure is not retryable.
+		// LogicalVolume CRD will be deleted soon by the controller.
+		if lv.Status.NextRetryTime == nil {
+			return ctrl.Result{}, nil
+		}
+		if wait := time.Until(lv.Status.NextRetryTime.Time); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		log.Info("retrying to create LV", "name", lv.Name, "uid", lv.UID, "status.createAttempts", lv.Status.CreateAttempts)
+		lv.Status.Code = codes.OK
+		lv.Status.Message = ""
 	}
 
 	reqBytes := lv.Spec.Size.Value()
 
+	// The copy job is lost if topolvm-node restarted during the copy.
+	interrupted := job == nil && lv.Status.Copy != nil
+	// The attempt to copy a volume has been counted when the copy started.
+	if job == nil && !interrupted {
+		lv.Status.CreateAttempts++
+	}
+	lv.Status.NextRetryTime = nil
+	adoptLVName, adopting := lv.Annotations[topolvm.GetAdoptLVNameKey()]
 	err := func() error {
+		if adopting {
+			return r.adoptLV(ctx, log, lv, adoptLVName)
+		}
+		if job != nil {
+			return r.finishCopy(log, lv, job, reqBytes)
+		}
+
 		// In case the controller crashed just after LVM LV creation, LV may already exist.
-		found, err := r.volumeExists(ctx, log, lv)
+		found, err := r.findVolume(ctx, log, lv)
 		if err != nil {
 			lv.Status.Code = codes.Internal
 			lv.Status.Message = "failed to check volume existence"
 			return err
 		}
-		if found {
-			log.Info("set volumeID to existing LogicalVolume", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID)
+		if found != nil {
+			log.Info("set volumeID to existing LogicalVolume", "name", lv.Name, "uid", lv.UID, "lv", found.Name)
 			// Don't set CurrentSize here because the Spec.Size field may be updated after the LVM LV is created.
 			lv.Status.VolumeID = string(lv.UID)
+			lv.Status.LVName = found.Name
 			lv.Status.Code = codes.OK
 			lv.Status.Message = ""
+			lv.Status.Copy = nil
 			return nil
 		}
+		// The interrupted copy has not created the LV because lvmd names and tags it only after the copy finishes.
+		if interrupted {
+			log.Info("copy was interrupted", "name", lv.Name, "uid", lv.UID,
+				"source", lv.Status.Copy.SourceVolumeID, "copied", lv.Status.Copy.CopiedBytes)
+			lv.Status.Copy = nil
+			lv.Status.Code, lv.Status.Message = extractFromError(errCopyInterrupted)
+			return errCopyInterrupted
+		}
 
 		var volume *proto.LogicalVolume
 
-		// Create a snapshot LV
-		if lv.Spec.Source != "" {
+		if lv.Spec.GroupSnapshot != nil {
+			// Create the snapshot LVs of all the members of the group snapshot
+			snapshot, err := r.createGroupSnapshot(ctx, lv)
+			if err != nil {
+				// Only the errors from lvmd and the invalid groups count toward the retry limit.
+				// The pending members and the failures to get the members from the API server are just requeued.
+				if _, ok := status.FromError(err); !ok {
+					return err
+				}
+				code, message := extractFromError(err)
+				log.Error(err, message)
+				lv.Status.Code = code
+				lv.Status.Message = message
+				return err
+			}
+			volume = snapshot
+		} else if lv.Spec.Source != "" {
 			// accessType should be either "readonly" or "readwrite".
 			if lv.Spec.AccessType != "ro" && lv.Spec.AccessType != "rw" {
 				return fmt.Errorf("invalid access type for source volume: %s", lv.Spec.AccessType)
@@ -234,31 +711,24 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 				log.Error(err, "unable to fetch source LogicalVolume", "name", lv.Name)
 				return err
 			}
-			sourceVolID := sourcelv.Status.VolumeID
-
-			// Create a snapshot lv
-			resp, err := r.lvService.CreateLVSnapshot(ctx, &proto.CreateLVSnapshotRequest{
-				Name:         string(lv.UID),
-				DeviceClass:  lv.Spec.DeviceClass,
-				SourceVolume: sourceVolID,
-				SizeGb:       uint64(reqBytes >> 30),
-				AccessType:   lv.Spec.AccessType,
-			})
+			if sourcelv.Spec.NodeName != lv.Spec.NodeName {
+				// The source volume is on another node, so its contents are copied from the node.
+				return r.startCopy(ctx, log, lv, sourcelv, reqBytes)
+			}
+			snapshot, err := r.createSnapshot(ctx, log, lv, sourcelv, reqBytes)
 			if err != nil {
-				code, message := extractFromError(err)
-				log.Error(err, message)
-				lv.Status.Code = code
-				lv.Status.Message = message
 				return err
 			}
-			volume = resp.Snapshot
+			volume = snapshot
 		} else {
 			// Create a regular lv
 			resp, err := r.lvService.CreateLV(ctx, &proto.CreateLVRequest{
 				Name:                string(lv.UID),
 				DeviceClass:         lv.Spec.DeviceClass,
 				LvcreateOptionClass: lv.Spec.LvcreateOptionClass,
-				SizeGb:              uint64(reqBytes >> 30),
+				SizeGb:              sizeGB(reqBytes),
+				SizeBytes:           uint64(reqBytes),
+				Tags:                lvTags(lv),
 			})
 			if err != nil {
 				code, message := extractFromError(err)
@@ -270,27 +740,138 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 			volume = resp.Volume
 		}
 
-		lv.Status.VolumeID = volume.Name
-		lv.Status.CurrentSize = resource.NewQuantity(reqBytes, resource.BinarySI)
+		lv.Status.VolumeID = string(lv.UID)
+		lv.Status.LVName = volume.Name
+		lv.Status.CurrentSize = resource.NewQuantity(volumeBytes(volume, reqBytes), resource.BinarySI)
 		lv.Status.Code = codes.OK
 		lv.Status.Message = ""
 		return nil
 	}()
 
+	if errors.Is(err, errCopyInProgress) {
+		return r.waitForCopy(ctx, log, lv, r.copyJobs.get(lv.UID))
+	}
+	if errors.Is(err, errGroupMemberPending) {
+		return r.waitForGroupMembers(ctx, log, lv, err)
+	}
+
+	if err != nil && lv.Status.Code != codes.OK && r.retryPolicy.shouldRetry(lv.Status.Code, lv.Status.CreateAttempts) {
+		backoff := r.retryPolicy.backoff(lv.Status.CreateAttempts)
+		lv.Status.NextRetryTime = &metav1.Time{Time: time.Now().Add(backoff)}
+		setStatusCondition(lv, topolvmv1.LogicalVolumeCreated, metav1.ConditionFalse, topolvmv1.ReasonRetrying, err.Error())
+		setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionTrue, topolvmv1.ReasonCreateFailed, err.Error())
+		if err2 := r.client.Status().Update(ctx, lv); err2 != nil {
+			log.Error(err2, "failed to update status", "name", lv.Name, "uid", lv.UID)
+			return ctrl.Result{}, err2
+		}
+		log.Info("failed to create LV; will retry", "name", lv.Name, "uid", lv.UID,
+			"status.createAttempts", lv.Status.CreateAttempts, "backoff", backoff, "error", err.Error())
+		r.recordEvent(lv, corev1.EventTypeWarning, EventReasonCreateFailed, "failed to create LV %s (attempt %d), will retry in %s: %v",
+			lv.UID, lv.Status.CreateAttempts, backoff, err)
+		// The error is not returned because the retry is scheduled by RequeueAfter.
+		return ctrl.Result{RequeueAfter: backoff}, nil
+	}
+
 	if err != nil {
+		setStatusCondition(lv, topolvmv1.LogicalVolumeCreated, metav1.ConditionFalse, topolvmv1.ReasonCreateFailed, err.Error())
+		setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionTrue, topolvmv1.ReasonCreateFailed, err.Error())
 		if err2 := r.client.Status().Update(ctx, lv); err2 != nil {
 			// err2 is logged but not returned because err is more important
 			log.Error(err2, "failed to update status", "name", lv.Name, "uid", lv.UID)
 		}
-		return err
+		r.recordEvent(lv, corev1.EventTypeWarning, EventReasonCreateFailed, "failed to create LV %s (attempt %d): %v", lv.UID, lv.Status.CreateAttempts, err)
+		return ctrl.Result{}, err
 	}
 
+	createdReason := topolvmv1.ReasonCreated
+	if adopting {
+		createdReason = topolvmv1.ReasonAdopted
+	}
+	setStatusCondition(lv, topolvmv1.LogicalVolumeCreated, metav1.ConditionTrue, createdReason, "")
+	setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionFalse, topolvmv1.ReasonSucceeded, "")
 	if err := r.client.Status().Update(ctx, lv); err != nil {
 		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		return ctrl.Result{}, err
+	}
+
+	if adopting {
+		log.Info("adopted existing LV", "name", lv.Name, "uid", lv.UID, "lv", adoptLVName, "status.volumeID", lv.Status.VolumeID)
+		r.recordEvent(lv, corev1.EventTypeNormal, EventReasonAdopted, "adopted existing LV %s as %s with size %s", adoptLVName, lv.Status.VolumeID, lv.Status.CurrentSize.String())
+		return ctrl.Result{}, nil
+	}
+	log.Info("created new LV", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID, "status.lvName", lv.Status.LVName)
+	r.recordEvent(lv, corev1.EventTypeNormal, EventReasonCreated, "created LV %s with size %s", lv.UID, lv.Spec.Size.String())
+	return ctrl.Result{}, nil
+}
+
+// adoptLV adopts the existing LV for the LogicalVolume.
+// The LV is renamed as if it were created for the LogicalVolume and tagged like LVs created by TopoLVM.
+func (r *LogicalVolumeReconciler) adoptLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, name string) error {
+	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
+	if err != nil {
+		lv.Status.Code = codes.Internal
+		lv.Status.Message = "failed to check volume existence"
 		return err
 	}
 
-	log.Info("created new LV", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID)
+	var existing *proto.LogicalVolume
+	for _, v := range respList.Volumes {
+		if v.Name == name {
+			existing = v
+			break
+		}
+	}
+	if existing == nil {
+		// The LV has already been renamed if the previous attempt failed after renaming it.
+		existing = findLV(respList.Volumes, lv)
+	}
+	if existing == nil {
+		lv.Status.Code = codes.NotFound
+		lv.Status.Message = fmt.Sprintf("LV %s is not found in device-class %q", name, lv.Spec.DeviceClass)
+		return errors.New(lv.Status.Message)
+	}
+
+	// Refuse to take over LVs owned by other LogicalVolumes.
+	for _, tag := range existing.Tags {
+		if strings.HasPrefix(tag, topolvm.LVOwnerTagPrefix) && tag != topolvm.GetLVOwnerTag(lv.Name) {
+			lv.Status.Code = codes.FailedPrecondition
+			lv.Status.Message = fmt.Sprintf("LV %s is owned by another LogicalVolume: %s", name, tag)
+			return errors.New(lv.Status.Message)
+		}
+	}
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := r.client.List(ctx, lvList); err != nil {
+		lv.Status.Code = codes.Internal
+		lv.Status.Message = "failed to list LogicalVolumes"
+		return err
+	}
+	for _, other := range lvList.Items {
+		if other.UID != lv.UID && (string(other.UID) == existing.Name || other.LVName() == existing.Name) {
+			lv.Status.Code = codes.FailedPrecondition
+			lv.Status.Message = fmt.Sprintf("LV %s is owned by another LogicalVolume: %s", name, other.Name)
+			return errors.New(lv.Status.Message)
+		}
+	}
+
+	resp, err := r.lvService.AdoptLV(ctx, &proto.AdoptLVRequest{
+		Name:        name,
+		NewName:     string(lv.UID),
+		Tags:        lvTags(lv),
+		DeviceClass: lv.Spec.DeviceClass,
+	})
+	if err != nil {
+		code, message := extractFromError(err)
+		log.Error(err, message)
+		lv.Status.Code = code
+		lv.Status.Message = message
+		return err
+	}
+
+	lv.Status.VolumeID = string(lv.UID)
+	lv.Status.LVName = resp.Volume.Name
+	lv.Status.CurrentSize = resource.NewQuantity(int64(resp.Volume.SizeBytes), resource.BinarySI)
+	lv.Status.Code = codes.OK
+	lv.Status.Message = ""
 	return nil
 }
 
@@ -310,8 +891,22 @@ func (r *LogicalVolumeReconciler) expandLV(ctx context.Context, log logr.Logger,
 
 	reqBytes := lv.Spec.Size.Value()
 
+	if !meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeResizing) {
+		setStatusCondition(lv, topolvmv1.LogicalVolumeResizing, metav1.ConditionTrue, topolvmv1.ReasonResizing,
+			fmt.Sprintf("resizing to %s", lv.Spec.Size.String()))
+		if err := r.client.Status().Update(ctx, lv); err != nil {
+			log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+			return err
+		}
+	}
+
 	err := func() error {
-		_, err := r.lvService.ResizeLV(ctx, &proto.ResizeLVRequest{Name: string(lv.UID), SizeGb: uint64(reqBytes >> 30), DeviceClass: lv.Spec.DeviceClass})
+		resp, err := r.lvService.ResizeLV(ctx, &proto.ResizeLVRequest{
+			Name:        lv.LVName(),
+			SizeGb:      sizeGB(reqBytes),
+			SizeBytes:   uint64(reqBytes),
+			DeviceClass: lv.Spec.DeviceClass,
+		})
 		if err != nil {
 			code, message := extractFromError(err)
 			log.Error(err, message)
@@ -320,30 +915,312 @@ func (r *LogicalVolumeReconciler) expandLV(ctx context.Context, log logr.Logger,
 			return err
 		}
 
-		lv.Status.CurrentSize = resource.NewQuantity(reqBytes, resource.BinarySI)
+		lv.Status.CurrentSize = resource.NewQuantity(volumeBytes(resp.GetVolume(), reqBytes), resource.BinarySI)
 		lv.Status.Code = codes.OK
 		lv.Status.Message = ""
 		return nil
 	}()
 
 	if err != nil {
+		setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionTrue, topolvmv1.ReasonResizeFailed, err.Error())
 		if err2 := r.client.Status().Update(ctx, lv); err2 != nil {
 			// err2 is logged but not returned because err is more important
 			log.Error(err2, "failed to update status", "name", lv.Name, "uid", lv.UID)
 		}
+		r.recordEvent(lv, corev1.EventTypeWarning, EventReasonResizeFailed, "failed to resize LV %s to %s: %v", lv.UID, lv.Spec.Size.String(), err)
 		return err
 	}
 
+	setStatusCondition(lv, topolvmv1.LogicalVolumeResizing, metav1.ConditionFalse, topolvmv1.ReasonResized, "")
+	setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionFalse, topolvmv1.ReasonSucceeded, "")
 	if err := r.client.Status().Update(ctx, lv); err != nil {
 		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
 		return err
 	}
 
 	log.Info("expanded LV", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID,
-		"original status.currentSize", origBytes, "status.currentSize", reqBytes)
+		"original status.currentSize", origBytes, "status.currentSize", lv.Status.CurrentSize.Value())
+	r.recordEvent(lv, corev1.EventTypeNormal, EventReasonResized, "resized LV %s to %s", lv.UID, lv.Spec.Size.String())
+	return nil
+}
+
+func (r *LogicalVolumeReconciler) modifyLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+	addTags := subtractStrings(lv.Spec.Tags, lv.Status.Tags)
+	delTags := subtractStrings(lv.Status.Tags, lv.Spec.Tags)
+	if lv.Spec.ReadAhead == lv.Status.ReadAhead && len(addTags) == 0 && len(delTags) == 0 {
+		return nil
+	}
+
+	_, err := r.lvService.ModifyLV(ctx, &proto.ModifyLVRequest{
+		Name:        lv.LVName(),
+		DeviceClass: lv.Spec.DeviceClass,
+		ReadAhead:   lv.Spec.ReadAhead,
+		AddTags:     addTags,
+		DelTags:     delTags,
+	})
+	if err != nil {
+		code, message := extractFromError(err)
+		log.Error(err, message)
+		lv.Status.Code = code
+		lv.Status.Message = message
+		setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionTrue, topolvmv1.ReasonModifyFailed, err.Error())
+		if err2 := r.client.Status().Update(ctx, lv); err2 != nil {
+			// err2 is logged but not returned because err is more important
+			log.Error(err2, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		}
+		r.recordEvent(lv, corev1.EventTypeWarning, EventReasonModifyFailed, "failed to modify LV %s: %v", lv.UID, err)
+		return err
+	}
+
+	lv.Status.ReadAhead = lv.Spec.ReadAhead
+	lv.Status.Tags = lv.Spec.Tags
+	lv.Status.Code = codes.OK
+	lv.Status.Message = ""
+	setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionFalse, topolvmv1.ReasonSucceeded, "")
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		return err
+	}
+
+	log.Info("modified LV", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID,
+		"status.readAhead", lv.Status.ReadAhead, "added tags", addTags, "deleted tags", delTags)
+	r.recordEvent(lv, corev1.EventTypeNormal, EventReasonModified, "modified attributes of LV %s", lv.UID)
 	return nil
 }
 
+// subtractStrings returns the elements of a that are not contained in b.
+func subtractStrings(a, b []string) []string {
+	var result []string
+	for _, s := range a {
+		if !containsString(b, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeGB returns the size in GiB rounded up, for lvmd that does not know the size_bytes fields.
+func sizeGB(bytes int64) uint64 {
+	return uint64((bytes + 1<<30 - 1) >> 30)
+}
+
+// volumeBytes returns the actual size of the volume reported by lvmd, which is reqBytes rounded up to the extent size.
+// lvmd that does not know the size_bytes fields reports the size only in GiB, or does not report it on resize,
+// and it rounds reqBytes up to GiB.
+func volumeBytes(volume *proto.LogicalVolume, reqBytes int64) int64 {
+	if volume.GetSizeBytes() != 0 {
+		return int64(volume.GetSizeBytes())
+	}
+	return int64(sizeGB(reqBytes) << 30)
+}
+
+// lvTags returns the LVM tags to be added to the LV for the LogicalVolume.
+func lvTags(lv *topolvmv1.LogicalVolume) []string {
+	tags := []string{topolvm.GetLVOwnerTag(lv.Name)}
+	if lv.Spec.PVCName != "" {
+		tags = append(tags, topolvm.LVPVCNameTagPrefix+lv.Spec.PVCName)
+	}
+	if lv.Spec.PVCNamespace != "" {
+		tags = append(tags, topolvm.LVPVCNamespaceTagPrefix+lv.Spec.PVCNamespace)
+	}
+	if lv.Spec.PVName != "" {
+		tags = append(tags, topolvm.LVPVNameTagPrefix+lv.Spec.PVName)
+	}
+	return tags
+}
+
+// groupMemberPollInterval is the interval to check whether all the members of a group snapshot have been created.
+const groupMemberPollInterval = 5 * time.Second
+
+// errGroupMemberPending is returned while some members of a group snapshot have not been created yet.
+var errGroupMemberPending = errors.New("waiting for the members of the group snapshot")
+
+// waitForGroupMembers records that lv waits for the other members of its group snapshot and requeues lv.
+// The attempt is not counted because it did not reach lvmd.
+func (r *LogicalVolumeReconciler) waitForGroupMembers(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, err error) (ctrl.Result, error) {
+	lv.Status.CreateAttempts--
+	setStatusCondition(lv, topolvmv1.LogicalVolumeCreated, metav1.ConditionFalse, topolvmv1.ReasonPending, err.Error())
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		return ctrl.Result{}, err
+	}
+	log.Info("waiting for the members of the group snapshot", "name", lv.Name, "uid", lv.UID, "reason", err.Error())
+	return ctrl.Result{RequeueAfter: groupMemberPollInterval}, nil
+}
+
+// createGroupSnapshot creates the snapshot LVs of all the members of the group snapshot that lv belongs to
+// at the same point in time, and returns the snapshot LV of lv.
+// The other members adopt the LVs created here when they are reconciled.
+func (r *LogicalVolumeReconciler) createGroupSnapshot(ctx context.Context, lv *topolvmv1.LogicalVolume) (*proto.LogicalVolume, error) {
+	group := lv.Spec.GroupSnapshot
+	index := -1
+	members := make([]*proto.LVGroupSnapshotMember, 0, len(group.Members))
+	for i, name := range group.Members {
+		member := lv
+		if name == lv.Name {
+			index = i
+		} else {
+			member = new(topolvmv1.LogicalVolume)
+			if err := r.client.Get(ctx, types.NamespacedName{Name: name}, member); err != nil {
+				if apierrs.IsNotFound(err) {
+					// topolvm-controller creates the members one by one.
+					return nil, fmt.Errorf("%w: member %s of group snapshot %s is not found", errGroupMemberPending, name, group.Name)
+				}
+				return nil, err
+			}
+		}
+		if member.Spec.Source == "" || member.Spec.NodeName != lv.Spec.NodeName || member.Spec.DeviceClass != lv.Spec.DeviceClass {
+			return nil, status.Errorf(codes.InvalidArgument, "member %s of group snapshot %s must be a snapshot on the same node and device class", name, group.Name)
+		}
+
+		sourcelv := new(topolvmv1.LogicalVolume)
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: member.Namespace, Name: member.Spec.Source}, sourcelv); err != nil {
+			return nil, err
+		}
+		members = append(members, &proto.LVGroupSnapshotMember{
+			Name:         string(member.UID),
+			SourceVolume: sourcelv.LVName(),
+			Tags:         lvTags(member),
+			AccessType:   member.Spec.AccessType,
+		})
+	}
+	if index < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is not a member of group snapshot %s", lv.Name, group.Name)
+	}
+
+	resp, err := r.lvService.CreateLVGroupSnapshot(ctx, &proto.CreateLVGroupSnapshotRequest{
+		DeviceClass: lv.Spec.DeviceClass,
+		Members:     members,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Snapshots[index], nil
+}
+
+// freezeSourceFilesystem freezes the filesystem of the source volume and returns the function to thaw it.
+// It returns nil if the source volume is not mounted read-write on the node, because then nothing is written to it.
+func (r *LogicalVolumeReconciler) freezeSourceFilesystem(sourceVolID string) (func() error, error) {
+	mounts, err := r.mounter.List()
+	if err != nil {
+		return nil, err
+	}
+	mountPoint, err := findFreezeMountPoint(mounts, filepath.Join(topolvm.DeviceDirectory, sourceVolID))
+	if err != nil {
+		return nil, err
+	}
+	if mountPoint == "" {
+		return nil, nil
+	}
+	return freezeFilesystem(r.exec, mountPoint, r.freezeTimeout)
+}
+
+// thawSourceFilesystem thaws the filesystem of the source volume after the snapshot named snapshotName is taken.
+// If the filesystem was thawed by the timeout, the snapshot may contain writes in progress,
+// so it is removed and an error is returned to retry.
+func (r *LogicalVolumeReconciler) thawSourceFilesystem(ctx context.Context, lv *topolvmv1.LogicalVolume, sourceVolID, snapshotName string, thaw func() error) error {
+	log := crlog.FromContext(ctx)
+
+	err := thaw()
+	if err == nil {
+		r.recordEvent(lv, corev1.EventTypeNormal, EventReasonFilesystemFrozen, "froze filesystem of %s while taking the snapshot", sourceVolID)
+		return nil
+	}
+	if !errors.Is(err, errFreezeTimedOut) {
+		// The snapshot is consistent because it was taken before thawing.
+		log.Error(err, "failed to thaw filesystem", "name", lv.Name, "source", sourceVolID)
+		r.recordEvent(lv, corev1.EventTypeWarning, EventReasonFreezeFailed, "failed to thaw filesystem of %s: %v", sourceVolID, err)
+		return nil
+	}
+
+	log.Info("filesystem was thawed before the snapshot was taken", "name", lv.Name, "source", sourceVolID, "timeout", r.freezeTimeout)
+	r.recordEvent(lv, corev1.EventTypeWarning, EventReasonFreezeFailed, "filesystem of %s was thawed after %s before the snapshot was taken", sourceVolID, r.freezeTimeout)
+	if snapshotName != "" {
+		if _, err := r.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: snapshotName, DeviceClass: lv.Spec.DeviceClass}); err != nil {
+			log.Error(err, "failed to remove inconsistent snapshot", "name", lv.Name, "uid", lv.UID)
+			return err
+		}
+	}
+	return status.Error(codes.Unavailable, errFreezeTimedOut.Error())
+}
+
+// recordEvent records an Event for the LogicalVolume.
+func (r *LogicalVolumeReconciler) recordEvent(lv *topolvmv1.LogicalVolume, eventType, reason, messageFmt string, args ...interface{}) {
+	recordLogicalVolumeEvent(r.recorder, lv, eventType, reason, messageFmt, args...)
+}
+
+func recordLogicalVolumeEvent(recorder record.EventRecorder, lv *topolvmv1.LogicalVolume, eventType, reason, messageFmt string, args ...interface{}) {
+	var obj runtime.Object = lv
+	if topolvm.UseLegacy() {
+		// Events must refer to the API group actually served for the LogicalVolume.
+		obj = &topolvmlegacyv1.LogicalVolume{ObjectMeta: lv.ObjectMeta}
+	}
+	recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// setStatusCondition sets the condition of the given type and re-evaluates the Ready condition.
+func setStatusCondition(lv *topolvmv1.LogicalVolume, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	if len(message) > maxConditionMessageLength {
+		// Cut the message back to a rune boundary so that it stays valid UTF-8.
+		n := maxConditionMessageLength
+		for n > 0 && !utf8.RuneStart(message[n]) {
+			n--
+		}
+		message = message[:n]
+	}
+	meta.SetStatusCondition(&lv.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: lv.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+
+	ready := metav1.Condition{
+		Type:               topolvmv1.LogicalVolumeReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: lv.Generation,
+	}
+	switch {
+	case meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeDeletionPending):
+		ready.Reason = topolvmv1.ReasonDeleting
+	case meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeFailed):
+		ready.Reason = topolvmv1.ReasonFailed
+		ready.Message = meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeFailed).Message
+	case meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeDrifted):
+		ready.Reason = topolvmv1.ReasonDrifted
+		ready.Message = meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeDrifted).Message
+	case lv.Status.VolumeID == "":
+		ready.Reason = topolvmv1.ReasonPending
+		if created := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeCreated); created != nil && created.Reason == topolvmv1.ReasonCopying {
+			ready.Reason = topolvmv1.ReasonCopying
+			ready.Message = created.Message
+		}
+	case meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeResizing):
+		ready.Reason = topolvmv1.ReasonResizing
+	case meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReleased):
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = topolvmv1.ReasonReleased
+	case meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeMigrating):
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = topolvmv1.ReasonMigrating
+		ready.Message = meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeMigrating).Message
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = topolvmv1.ReasonAvailable
+	}
+	meta.SetStatusCondition(&lv.Status.Conditions, ready)
+}
+
 type logicalVolumeFilter struct {
 	nodeName string
 }
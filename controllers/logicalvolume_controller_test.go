@@ -2,7 +2,11 @@ package controllers
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -10,8 +14,11 @@ import (
 	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 	proto "github.com/topolvm/topolvm/lvmd/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	storegev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -21,6 +28,28 @@ import (
 
 var volumes = &[]*proto.LogicalVolume{}
 
+// createLVErrors are returned by MockLVServiceClient.CreateLV in order before it succeeds.
+var (
+	createLVErrorsMu sync.Mutex
+	createLVErrors   []error
+)
+
+func setCreateLVErrors(errs ...error) {
+	createLVErrorsMu.Lock()
+	defer createLVErrorsMu.Unlock()
+	createLVErrors = errs
+}
+
+// mockExtentSize is the extent size that MockLVServiceClient rounds the sizes up to.
+const mockExtentSize = 4 << 20
+
+// legacyLVMD makes MockLVServiceClient behave as lvmd that knows only the size_gb fields.
+var legacyLVMD atomic.Bool
+
+func setLegacyLVMD(legacy bool) {
+	legacyLVMD.Store(legacy)
+}
+
 type MockVGServiceClient struct {
 }
 
@@ -38,7 +67,18 @@ func (c MockVGServiceClient) GetLVList(ctx context.Context, in *proto.GetLVListR
 
 // Watch implements proto.VGServiceClient.
 func (MockVGServiceClient) Watch(ctx context.Context, in *proto.Empty, opts ...grpc.CallOption) (proto.VGService_WatchClient, error) {
-	panic("unimplemented")
+	return mockWatchClient{}, nil
+}
+
+type mockWatchClient struct {
+	grpc.ClientStream
+}
+
+// Recv implements proto.VGService_WatchClient.
+func (mockWatchClient) Recv() (*proto.WatchResponse, error) {
+	return &proto.WatchResponse{
+		Items: []*proto.WatchItem{{DeviceClass: topolvm.DefaultDeviceClassName}},
+	}, nil
 }
 
 type MockLVServiceClient struct {
@@ -46,9 +86,24 @@ type MockLVServiceClient struct {
 
 // CreateLV implements proto.LVServiceClient.
 func (c MockLVServiceClient) CreateLV(ctx context.Context, in *proto.CreateLVRequest, opts ...grpc.CallOption) (*proto.CreateLVResponse, error) {
+	createLVErrorsMu.Lock()
+	if len(createLVErrors) > 0 {
+		err := createLVErrors[0]
+		createLVErrors = createLVErrors[1:]
+		createLVErrorsMu.Unlock()
+		return nil, err
+	}
+	createLVErrorsMu.Unlock()
+
 	lv := proto.LogicalVolume{
-		Name:   in.Name,
-		SizeGb: in.SizeGb,
+		Name: in.Name,
+		Tags: in.Tags,
+	}
+	if legacyLVMD.Load() {
+		lv.SizeGb = in.SizeGb
+	} else {
+		lv.SizeBytes = (in.SizeBytes + mockExtentSize - 1) / mockExtentSize * mockExtentSize
+		lv.SizeGb = lv.SizeBytes >> 30
 	}
 	*volumes = append(*volumes, &lv)
 	createResponse := proto.CreateLVResponse{
@@ -57,21 +112,110 @@ func (c MockLVServiceClient) CreateLV(ctx context.Context, in *proto.CreateLVReq
 	return &createResponse, nil
 }
 
+// AdoptLV implements proto.LVServiceClient.
+func (MockLVServiceClient) AdoptLV(ctx context.Context, in *proto.AdoptLVRequest, opts ...grpc.CallOption) (*proto.AdoptLVResponse, error) {
+	for _, v := range *volumes {
+		if v.Name != in.Name && v.Name != in.NewName {
+			continue
+		}
+		v.Name = in.NewName
+		for _, tag := range in.Tags {
+			if !containsString(v.Tags, tag) {
+				v.Tags = append(v.Tags, tag)
+			}
+		}
+		return &proto.AdoptLVResponse{Volume: v}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "not found: %s", in.Name)
+}
+
 // CreateLVSnapshot implements proto.LVServiceClient.
 func (MockLVServiceClient) CreateLVSnapshot(ctx context.Context, in *proto.CreateLVSnapshotRequest, opts ...grpc.CallOption) (*proto.CreateLVSnapshotResponse, error) {
 	panic("unimplemented")
 }
 
-// RemoveLV implements proto.LVServiceClient.
-func (MockLVServiceClient) RemoveLV(ctx context.Context, in *proto.RemoveLVRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+// CreateLVGroupSnapshot implements proto.LVServiceClient.
+func (MockLVServiceClient) CreateLVGroupSnapshot(ctx context.Context, in *proto.CreateLVGroupSnapshotRequest, opts ...grpc.CallOption) (*proto.CreateLVGroupSnapshotResponse, error) {
+	var resp proto.CreateLVGroupSnapshotResponse
+	for _, member := range in.Members {
+		lv := proto.LogicalVolume{
+			Name:      member.Name,
+			SizeBytes: mockExtentSize,
+			Tags:      member.Tags,
+		}
+		*volumes = append(*volumes, &lv)
+		resp.Snapshots = append(resp.Snapshots, &lv)
+	}
+	return &resp, nil
+}
+
+// GetLVBlockMetadata implements proto.LVServiceClient.
+func (MockLVServiceClient) GetLVBlockMetadata(ctx context.Context, in *proto.GetLVBlockMetadataRequest, opts ...grpc.CallOption) (proto.LVService_GetLVBlockMetadataClient, error) {
 	panic("unimplemented")
 }
 
-// ResizeLV implements proto.LVServiceClient.
-func (MockLVServiceClient) ResizeLV(ctx context.Context, in *proto.ResizeLVRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+// ExportLV implements proto.LVServiceClient.
+func (MockLVServiceClient) ExportLV(ctx context.Context, in *proto.ExportLVRequest, opts ...grpc.CallOption) (proto.LVService_ExportLVClient, error) {
+	panic("unimplemented")
+}
+
+// ImportLV implements proto.LVServiceClient.
+func (MockLVServiceClient) ImportLV(ctx context.Context, opts ...grpc.CallOption) (proto.LVService_ImportLVClient, error) {
 	panic("unimplemented")
 }
 
+// RemoveLV implements proto.LVServiceClient.
+func (MockLVServiceClient) RemoveLV(ctx context.Context, in *proto.RemoveLVRequest, opts ...grpc.CallOption) (*proto.RemoveLVResponse, error) {
+	for i, v := range *volumes {
+		if v.Name == in.Name {
+			*volumes = append((*volumes)[:i], (*volumes)[i+1:]...)
+			return &proto.RemoveLVResponse{}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", in.Name)
+}
+
+// ModifyLV implements proto.LVServiceClient.
+func (MockLVServiceClient) ModifyLV(ctx context.Context, in *proto.ModifyLVRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+	for _, v := range *volumes {
+		if v.Name != in.Name {
+			continue
+		}
+		var tags []string
+		for _, tag := range v.Tags {
+			if !containsString(in.DelTags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		for _, tag := range in.AddTags {
+			if !containsString(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		v.Tags = tags
+		return &proto.Empty{}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", in.Name)
+}
+
+// ResizeLV implements proto.LVServiceClient.
+func (MockLVServiceClient) ResizeLV(ctx context.Context, in *proto.ResizeLVRequest, opts ...grpc.CallOption) (*proto.ResizeLVResponse, error) {
+	for _, v := range *volumes {
+		if v.Name != in.Name {
+			continue
+		}
+		if legacyLVMD.Load() {
+			// lvmd that knows only the size_gb fields returns Empty.
+			v.SizeGb = in.SizeGb
+			return &proto.ResizeLVResponse{}, nil
+		}
+		v.SizeBytes = (in.SizeBytes + mockExtentSize - 1) / mockExtentSize * mockExtentSize
+		v.SizeGb = v.SizeBytes >> 30
+		return &proto.ResizeLVResponse{Volume: v}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", in.Name)
+}
+
 var _ = Describe("LogicalVolume controller", func() {
 	ctx := context.Background()
 	var stopFunc func()
@@ -88,7 +232,7 @@ var _ = Describe("LogicalVolume controller", func() {
 		vgService = MockVGServiceClient{}
 		lvService = MockLVServiceClient{}
 
-		reconciler := NewLogicalVolumeReconcilerWithServices(mgr.GetClient(), "node"+suffix, vgService, lvService)
+		reconciler := NewLogicalVolumeReconcilerWithServices(mgr.GetClient(), mgr.GetEventRecorderFor("topolvm-node"), "node"+suffix, vgService, lvService, DefaultFreezeTimeout, nil)
 		err = reconciler.SetupWithManager(mgr)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -219,5 +363,435 @@ var _ = Describe("LogicalVolume controller", func() {
 			}
 			return !controllerutil.ContainsFinalizer(&lv, topolvm.GetLogicalVolumeFinalizer())
 		}, "2s").Should(BeTrue())
+
+		// ensure the pending deletion is reported in the status
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeDeletionPending)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			g.Expect(cond.Reason).To(Equal(topolvmv1.ReasonPendingDeletionAnnotation))
+			g.Expect(meta.IsStatusConditionFalse(lv.Status.Conditions, topolvmv1.LogicalVolumeReady)).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	It("should tag LV with the Kubernetes objects it is provisioned for", func() {
+		startReconciler("-tags")
+
+		ctx := context.Background()
+
+		// Setup
+		lv := topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "lv-tags",
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:         "lv-tags",
+				NodeName:     "node-tags",
+				PVCName:      "pvc-tags",
+				PVCNamespace: "ns-tags",
+				PVName:       "pv-tags",
+			},
+		}
+		err := k8sClient.Create(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Verify
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.VolumeID).NotTo(BeEmpty())
+
+			var tags []string
+			for _, v := range *volumes {
+				if v.Name == lv.Status.VolumeID {
+					tags = v.Tags
+				}
+			}
+			g.Expect(tags).To(ConsistOf(
+				topolvm.GetLVOwnerTag(lv.Name),
+				topolvm.LVPVCNameTagPrefix+"pvc-tags",
+				topolvm.LVPVCNamespaceTagPrefix+"ns-tags",
+				topolvm.LVPVNameTagPrefix+"pv-tags",
+			))
+		}).Should(Succeed())
+	})
+
+	It("should adopt the existing LV", func() {
+		startReconciler("-adopt")
+
+		ctx := context.Background()
+
+		// Setup
+		*volumes = append(*volumes, &proto.LogicalVolume{Name: "existing-adopt", SizeBytes: 5 << 30})
+		lv := topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "lv-adopt",
+				Annotations: map[string]string{
+					topolvm.GetAdoptLVNameKey(): "existing-adopt",
+				},
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     "lv-adopt",
+				NodeName: "node-adopt",
+				Size:     *resource.NewQuantity(5<<30, resource.BinarySI),
+			},
+		}
+		err := k8sClient.Create(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Verify
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.VolumeID).To(Equal(string(lv.UID)))
+			g.Expect(lv.Status.LVName).To(Equal(string(lv.UID)))
+			g.Expect(lv.Status.CurrentSize.Value()).To(BeEquivalentTo(5 << 30))
+			cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeCreated)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Reason).To(Equal(topolvmv1.ReasonAdopted))
+
+			var tags []string
+			for _, v := range *volumes {
+				g.Expect(v.Name).NotTo(Equal("existing-adopt"))
+				if v.Name == lv.Status.VolumeID {
+					tags = v.Tags
+				}
+			}
+			g.Expect(tags).To(ContainElement(topolvm.GetLVOwnerTag(lv.Name)))
+		}).Should(Succeed())
+	})
+
+	It("should modify the attributes of LV", func() {
+		startReconciler("-modify")
+
+		ctx := context.Background()
+
+		// Setup
+		lv := topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "lv-modify",
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:      "lv-modify",
+				NodeName:  "node-modify",
+				Size:      *resource.NewQuantity(1<<30, resource.BinarySI),
+				ReadAhead: "256",
+				Tags:      []string{"foo", "bar"},
+			},
+		}
+		err := k8sClient.Create(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		lvTagsOf := func(g Gomega) []string {
+			for _, v := range *volumes {
+				if v.Name == lv.Status.VolumeID {
+					return v.Tags
+				}
+			}
+			g.Expect(false).To(BeTrue(), "LV is not found")
+			return nil
+		}
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.ReadAhead).To(Equal("256"))
+			g.Expect(lv.Status.Tags).To(Equal([]string{"foo", "bar"}))
+			g.Expect(lvTagsOf(g)).To(ContainElements("foo", "bar", topolvm.GetLVOwnerTag(lv.Name)))
+		}).Should(Succeed())
+
+		// Modify
+		lv.Spec.Tags = []string{"bar", "baz"}
+		err = k8sClient.Update(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Verify
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.Tags).To(Equal([]string{"bar", "baz"}))
+			tags := lvTagsOf(g)
+			g.Expect(tags).To(ContainElements("bar", "baz", topolvm.GetLVOwnerTag(lv.Name)))
+			g.Expect(tags).NotTo(ContainElement("foo"))
+		}).Should(Succeed())
+	})
+
+	It("should record the actual size of LV", func() {
+		startReconciler("-actual-size")
+
+		ctx := context.Background()
+
+		// Setup
+		lv := topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "lv-actual-size",
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     "lv-actual-size",
+				NodeName: "node-actual-size",
+				Size:     *resource.NewQuantity(100<<20+4096, resource.BinarySI),
+			},
+		}
+		err := k8sClient.Create(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.CurrentSize).NotTo(BeNil())
+			g.Expect(lv.Status.CurrentSize.Value()).To(BeEquivalentTo(104 << 20))
+		}).Should(Succeed())
+
+		// Expand
+		lv.Spec.Size = *resource.NewQuantity(200<<20+4096, resource.BinarySI)
+		err = k8sClient.Update(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.CurrentSize.Value()).To(BeEquivalentTo(204 << 20))
+			g.Expect(meta.IsStatusConditionFalse(lv.Status.Conditions, topolvmv1.LogicalVolumeResizing)).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	It("should create and expand LV with lvmd that knows only size_gb", func() {
+		setLegacyLVMD(true)
+		DeferCleanup(setLegacyLVMD, false)
+		startReconciler("-legacy-lvmd")
+
+		ctx := context.Background()
+
+		// Setup
+		lv := topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "lv-legacy-lvmd",
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     "lv-legacy-lvmd",
+				NodeName: "node-legacy-lvmd",
+				Size:     *resource.NewQuantity(1<<30+4096, resource.BinarySI),
+			},
+		}
+		err := k8sClient.Create(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		lvSizeGb := func(g Gomega) uint64 {
+			for _, v := range *volumes {
+				if v.Name == lv.Status.VolumeID {
+					return v.SizeGb
+				}
+			}
+			g.Expect(false).To(BeTrue(), "LV is not found")
+			return 0
+		}
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.VolumeID).To(Equal(string(lv.UID)))
+			g.Expect(lvSizeGb(g)).To(BeEquivalentTo(2))
+			g.Expect(lv.Status.CurrentSize.Value()).To(BeEquivalentTo(2 << 30))
+		}).Should(Succeed())
+
+		// Expand
+		lv.Spec.Size = *resource.NewQuantity(3<<30+4096, resource.BinarySI)
+		err = k8sClient.Update(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lvSizeGb(g)).To(BeEquivalentTo(4))
+			g.Expect(lv.Status.CurrentSize.Value()).To(BeEquivalentTo(4 << 30))
+		}).Should(Succeed())
+	})
+
+	It("should retry creating LV when it fails with a retryable error", func() {
+		setCreateLVErrors(status.Error(codes.Internal, "lock contention"))
+		DeferCleanup(setCreateLVErrors)
+		startReconciler("-retry")
+
+		ctx := context.Background()
+
+		// Setup
+		lv := setupResources(ctx, "-retry")
+
+		// ensure the failure is recorded and the retry is scheduled
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.Code).To(Equal(codes.Internal))
+			g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(1))
+			g.Expect(lv.Status.NextRetryTime).NotTo(BeNil())
+		}).Should(Succeed())
+
+		// ensure LV is created by the retry
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.VolumeID).To(Equal(string(lv.UID)))
+			g.Expect(lv.Status.Code).To(Equal(codes.OK))
+			g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(2))
+			g.Expect(lv.Status.NextRetryTime).To(BeNil())
+			g.Expect(meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReady)).To(BeTrue())
+		}, "10s").Should(Succeed())
+	})
+
+	It("should retry the copy interrupted by a restart", func() {
+		ctx := context.Background()
+
+		// Setup the LogicalVolume left by topolvm-node restarted during the copy.
+		lv := setupResources(ctx, "-copy-interrupted")
+		lv.Status.CreateAttempts = 1
+		lv.Status.Copy = &topolvmv1.CopyStatus{
+			Mode:           topolvmv1.CopyCreate,
+			SourceVolumeID: "source",
+			SourceNodeName: "other-node",
+			CopiedBytes:    1 << 20,
+			TotalBytes:     1 << 30,
+			StartTime:      metav1.Now(),
+		}
+		err := k8sClient.Status().Update(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+		startReconciler("-copy-interrupted")
+
+		// ensure the interrupted copy is counted as a failed attempt
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.Code).To(Equal(codes.Unavailable))
+			g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(1))
+			g.Expect(lv.Status.NextRetryTime).NotTo(BeNil())
+			g.Expect(lv.Status.Copy).To(BeNil())
+		}).Should(Succeed())
+
+		// ensure LV is created by the retry
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.VolumeID).To(Equal(string(lv.UID)))
+			g.Expect(lv.Status.Code).To(Equal(codes.OK))
+			g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(2))
+		}, "10s").Should(Succeed())
+	})
+
+	It("should wait for the members of the group snapshot without counting the attempts", func() {
+		startReconciler("-group")
+
+		ctx := context.Background()
+
+		// Setup
+		source := setupResources(ctx, "-group")
+		member := func(name string) *topolvmv1.LogicalVolume {
+			return &topolvmv1.LogicalVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: topolvmv1.LogicalVolumeSpec{
+					NodeName:   source.Spec.NodeName,
+					Source:     source.Name,
+					AccessType: "ro",
+					GroupSnapshot: &topolvmv1.GroupSnapshot{
+						Name:    "group",
+						Members: []string{"lv-group-a", "lv-group-b"},
+					},
+				},
+			}
+		}
+		lvA := member("lv-group-a")
+		err := k8sClient.Create(ctx, lvA)
+		Expect(err).NotTo(HaveOccurred())
+
+		// ensure the missing member is waited for without consuming the retry budget
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(lvA), lvA)
+			g.Expect(err).NotTo(HaveOccurred())
+			created := meta.FindStatusCondition(lvA.Status.Conditions, topolvmv1.LogicalVolumeCreated)
+			g.Expect(created).NotTo(BeNil())
+			g.Expect(created.Reason).To(Equal(topolvmv1.ReasonPending))
+		}).Should(Succeed())
+		Consistently(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(lvA), lvA)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lvA.Status.Code).To(Equal(codes.OK))
+			g.Expect(lvA.Status.CreateAttempts).To(BeEquivalentTo(0))
+			g.Expect(lvA.Status.VolumeID).To(BeEmpty())
+		}, "3s").Should(Succeed())
+
+		// ensure the snapshots are created once all the members exist
+		lvB := member("lv-group-b")
+		err = k8sClient.Create(ctx, lvB)
+		Expect(err).NotTo(HaveOccurred())
+		for _, lv := range []*topolvmv1.LogicalVolume{lvA, lvB} {
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(lv), lv)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(lv.Status.VolumeID).To(Equal(string(lv.UID)))
+				g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(1))
+			}, "10s").Should(Succeed())
+		}
+	})
+
+	It("should not retry creating LV when it fails with a terminal error", func() {
+		setCreateLVErrors(status.Error(codes.ResourceExhausted, "no enough space left on VG"))
+		DeferCleanup(setCreateLVErrors)
+		startReconciler("-no-retry")
+
+		ctx := context.Background()
+
+		// Setup
+		lv := setupResources(ctx, "-no-retry")
+
+		// ensure the failure is recorded without retry
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.Code).To(Equal(codes.ResourceExhausted))
+			g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(1))
+			g.Expect(lv.Status.NextRetryTime).To(BeNil())
+		}).Should(Succeed())
+		Consistently(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(lv.Status.VolumeID).To(BeEmpty())
+			g.Expect(lv.Status.CreateAttempts).To(BeEquivalentTo(1))
+		}, "3s").Should(Succeed())
+	})
+})
+
+var _ = Describe("findLV", func() {
+	It("should find LV by the name in status or the owner tag", func() {
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "lv-find", UID: "e6a46a8d-5d6b-4b0e-9c35-ccd8d2bd4a6e"},
+		}
+		named := &proto.LogicalVolume{Name: "ns-pvc-e6a46a8d", Tags: []string{topolvm.GetLVOwnerTag("lv-find")}}
+		legacy := &proto.LogicalVolume{Name: "e6a46a8d-5d6b-4b0e-9c35-ccd8d2bd4a6e"}
+		other := &proto.LogicalVolume{Name: "other", Tags: []string{topolvm.GetLVOwnerTag("lv-other")}}
+
+		// status.lvName is not recorded yet.
+		Expect(findLV([]*proto.LogicalVolume{other, named}, lv)).To(Equal(named))
+		Expect(findLV([]*proto.LogicalVolume{named, legacy}, lv)).To(Equal(legacy))
+		Expect(findLV([]*proto.LogicalVolume{other}, lv)).To(BeNil())
+
+		lv.Status.VolumeID = string(lv.UID)
+		Expect(findLV([]*proto.LogicalVolume{named, legacy}, lv)).To(Equal(legacy))
+
+		lv.Status.LVName = "ns-pvc-e6a46a8d"
+		Expect(findLV([]*proto.LogicalVolume{legacy, named}, lv)).To(Equal(named))
+		Expect(findLV([]*proto.LogicalVolume{legacy, other}, lv)).To(BeNil())
+	})
+})
+
+var _ = Describe("setStatusCondition", func() {
+	It("should truncate long messages at a rune boundary", func() {
+		lv := &topolvmv1.LogicalVolume{}
+		// "あ" is encoded in 3 bytes, so the limit falls in the middle of a rune.
+		message := strings.Repeat("あ", maxConditionMessageLength/3+1)
+		setStatusCondition(lv, topolvmv1.LogicalVolumeFailed, metav1.ConditionTrue, topolvmv1.ReasonCreateFailed, message)
+
+		cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(utf8.ValidString(cond.Message)).To(BeTrue())
+		Expect(len(cond.Message)).To(BeNumerically("<=", maxConditionMessageLength))
+		Expect(cond.Message).To(Equal(strings.Repeat("あ", maxConditionMessageLength/3)))
 	})
 })
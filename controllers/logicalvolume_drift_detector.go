@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Event reasons recorded on LogicalVolume by LogicalVolumeDriftDetector.
+const (
+	EventReasonDriftDetected   = "DriftDetected"
+	EventReasonDriftResolved   = "DriftResolved"
+	EventReasonSizeDriftHealed = "SizeDriftHealed"
+)
+
+// Drift types used as the label of the metrics.
+const (
+	driftTypeMissing      = "missing"
+	driftTypeSizeMismatch = "size_mismatch"
+	driftTypeTagMismatch  = "tag_mismatch"
+)
+
+var driftTypes = []string{driftTypeMissing, driftTypeSizeMismatch, driftTypeTagMismatch}
+
+var driftReasons = map[string]string{
+	driftTypeMissing:      topolvmv1.ReasonLVMissing,
+	driftTypeSizeMismatch: topolvmv1.ReasonSizeMismatch,
+	driftTypeTagMismatch:  topolvmv1.ReasonTagMismatch,
+}
+
+var driftedVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "topolvm",
+	Subsystem: "logicalvolume",
+	Name:      "drifted_volumes",
+	Help:      "The number of LogicalVolumes that differ from the actual LVM logical volumes",
+}, []string{"node", "device_class", "type"})
+
+func init() {
+	metrics.Registry.MustRegister(driftedVolumes)
+}
+
+// LogicalVolumeDriftDetector periodically compares LogicalVolumes for the node with
+// the actual LVM logical volumes, and reports differences made outside of TopoLVM,
+// e.g. by running lvremove or lvextend by hand, or by a disk failure.
+type LogicalVolumeDriftDetector struct {
+	client    client.Client
+	recorder  record.EventRecorder
+	nodeName  string
+	vgService proto.VGServiceClient
+	lvService proto.LVServiceClient
+	interval  time.Duration
+	healSize  bool
+	log       logr.Logger
+}
+
+var _ manager.LeaderElectionRunnable = &LogicalVolumeDriftDetector{}
+
+// NewLogicalVolumeDriftDetector returns LogicalVolumeDriftDetector with creating lvService and vgService.
+// If healSize is true, the detector extends LVs that became smaller than status.currentSize.
+func NewLogicalVolumeDriftDetector(client client.Client, recorder record.EventRecorder, nodeName string, conn *grpc.ClientConn, interval time.Duration, healSize bool) *LogicalVolumeDriftDetector {
+	return NewLogicalVolumeDriftDetectorWithServices(client, recorder, nodeName, proto.NewVGServiceClient(conn), proto.NewLVServiceClient(conn), interval, healSize)
+}
+
+func NewLogicalVolumeDriftDetectorWithServices(client client.Client, recorder record.EventRecorder, nodeName string, vgService proto.VGServiceClient, lvService proto.LVServiceClient, interval time.Duration, healSize bool) *LogicalVolumeDriftDetector {
+	return &LogicalVolumeDriftDetector{
+		client:    client,
+		recorder:  recorder,
+		nodeName:  nodeName,
+		vgService: vgService,
+		lvService: lvService,
+		interval:  interval,
+		healSize:  healSize,
+		log:       ctrl.Log.WithName("controllers").WithName("LogicalVolumeDriftDetector"),
+	}
+}
+
+// Start implements controller-runtime's manager.Runnable.
+func (d *LogicalVolumeDriftDetector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := d.detect(ctx); err != nil {
+				d.log.Error(err, "failed to detect drift of logical volumes")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
+func (d *LogicalVolumeDriftDetector) NeedLeaderElection() bool {
+	return false
+}
+
+func (d *LogicalVolumeDriftDetector) detect(ctx context.Context) error {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := d.client.List(ctx, lvList); err != nil {
+		return err
+	}
+
+	// actualVolumes and extentSizes cache the result of GetLVList for each device class.
+	// nil means that GetLVList has failed for the device class.
+	actualVolumes := make(map[string]map[string]*proto.LogicalVolume)
+	extentSizes := make(map[string]uint64)
+	counts := make(map[string]map[string]int)
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		if lv.Spec.NodeName != d.nodeName || lv.Status.VolumeID == "" || lv.DeletionTimestamp != nil {
+			continue
+		}
+		if _, ok := lv.Annotations[topolvm.GetLVPendingDeletionKey()]; ok {
+			continue
+		}
+
+		volumes, ok := actualVolumes[lv.Spec.DeviceClass]
+		if !ok {
+			resp, err := d.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
+			if err != nil {
+				d.log.Error(err, "failed to list LV", "device_class", lv.Spec.DeviceClass)
+			} else {
+				volumes = make(map[string]*proto.LogicalVolume, len(resp.Volumes))
+				for _, v := range resp.Volumes {
+					volumes[v.Name] = v
+				}
+				extentSizes[lv.Spec.DeviceClass] = resp.ExtentSize
+			}
+			actualVolumes[lv.Spec.DeviceClass] = volumes
+		}
+		if volumes == nil {
+			continue
+		}
+		if counts[lv.Spec.DeviceClass] == nil {
+			counts[lv.Spec.DeviceClass] = make(map[string]int)
+		}
+
+		driftType, message := checkDrift(lv, volumes[lv.LVName()], extentSizes[lv.Spec.DeviceClass])
+		if driftType == driftTypeSizeMismatch && d.healSize {
+			healed, err := d.healSizeDrift(ctx, lv, volumes[lv.LVName()], extentSizes[lv.Spec.DeviceClass])
+			if err != nil {
+				d.log.Error(err, "failed to heal size drift", "name", lv.Name, "uid", lv.UID)
+			}
+			if healed {
+				driftType = ""
+			}
+		}
+		if driftType != "" {
+			counts[lv.Spec.DeviceClass][driftType]++
+		}
+
+		if err := d.updateStatus(ctx, lv, driftType, message); err != nil {
+			d.log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		}
+	}
+
+	for dc, c := range counts {
+		for _, t := range driftTypes {
+			driftedVolumes.WithLabelValues(d.nodeName, dc, t).Set(float64(c[t]))
+		}
+	}
+	return nil
+}
+
+// checkDrift compares the LogicalVolume with the actual LVM logical volume, and returns the type of the drift
+// and its description. The type is empty if no drift is found.
+// extentSize is the extent size of the volume group, or zero if lvmd does not report it.
+func checkDrift(lv *topolvmv1.LogicalVolume, actual *proto.LogicalVolume, extentSize uint64) (string, string) {
+	if actual == nil {
+		return driftTypeMissing, fmt.Sprintf("LV %s is not found in device-class %q", lv.LVName(), lv.Spec.DeviceClass)
+	}
+
+	for _, tag := range actual.Tags {
+		if strings.HasPrefix(tag, topolvm.LVOwnerTagPrefix) && tag != topolvm.GetLVOwnerTag(lv.Name) {
+			return driftTypeTagMismatch, fmt.Sprintf("LV %s has tag %q, expected %q", actual.Name, tag, topolvm.GetLVOwnerTag(lv.Name))
+		}
+	}
+
+	// Skip the size check while the LV is being resized to avoid false positives.
+	// Status.CurrentSize is smaller than Spec.Size until the resize completes.
+	if lv.Status.CurrentSize == nil || lv.Spec.Size.Cmp(*lv.Status.CurrentSize) > 0 ||
+		meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeResizing) {
+		return "", ""
+	}
+	expected := expectedSize(lv, extentSize)
+	if actual.SizeBytes != expected {
+		return driftTypeSizeMismatch, fmt.Sprintf("LV %s is %d bytes, expected %d bytes", actual.Name, actual.SizeBytes, expected)
+	}
+	return "", ""
+}
+
+// expectedSize returns the size of the LV for status.currentSize. LVM rounds the size up to the extent size
+// of the volume group. status.currentSize recorded before the actual size was reported by lvmd may be smaller.
+// If lvmd does not report the extent size, it rounds sizes up to GiB.
+func expectedSize(lv *topolvmv1.LogicalVolume, extentSize uint64) uint64 {
+	if extentSize == 0 {
+		extentSize = 1 << 30
+	}
+	size := uint64(lv.Status.CurrentSize.Value())
+	return (size + extentSize - 1) / extentSize * extentSize
+}
+
+// healSizeDrift extends the LV to status.currentSize. LVs larger than status.currentSize cannot be healed
+// because shrinking volumes is not allowed.
+func (d *LogicalVolumeDriftDetector) healSizeDrift(ctx context.Context, lv *topolvmv1.LogicalVolume, actual *proto.LogicalVolume, extentSize uint64) (bool, error) {
+	expected := expectedSize(lv, extentSize)
+	if actual.SizeBytes > expected {
+		return false, nil
+	}
+
+	_, err := d.lvService.ResizeLV(ctx, &proto.ResizeLVRequest{
+		Name:        actual.Name,
+		SizeGb:      sizeGB(int64(expected)),
+		SizeBytes:   expected,
+		DeviceClass: lv.Spec.DeviceClass,
+	})
+	if err != nil {
+		return false, err
+	}
+	d.log.Info("healed size drift", "name", lv.Name, "uid", lv.UID, "size", expected, "original_size", actual.SizeBytes)
+	recordLogicalVolumeEvent(d.recorder, lv, corev1.EventTypeNormal, EventReasonSizeDriftHealed,
+		"extended LV %s from %d bytes to %d bytes", actual.Name, actual.SizeBytes, expected)
+	return true, nil
+}
+
+func (d *LogicalVolumeDriftDetector) updateStatus(ctx context.Context, lv *topolvmv1.LogicalVolume, driftType, message string) error {
+	cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeDrifted)
+	if driftType == "" {
+		// Do not add the condition to LogicalVolumes that have never drifted.
+		if cond == nil || cond.Status != metav1.ConditionTrue {
+			return nil
+		}
+		setStatusCondition(lv, topolvmv1.LogicalVolumeDrifted, metav1.ConditionFalse, topolvmv1.ReasonInSync, "")
+		if err := d.client.Status().Update(ctx, lv); err != nil {
+			return err
+		}
+		d.log.Info("drift resolved", "name", lv.Name, "uid", lv.UID)
+		recordLogicalVolumeEvent(d.recorder, lv, corev1.EventTypeNormal, EventReasonDriftResolved, "LV %s is in sync", lv.LVName())
+		return nil
+	}
+
+	reason := driftReasons[driftType]
+	if cond != nil && cond.Status == metav1.ConditionTrue && cond.Reason == reason && cond.Message == message {
+		return nil
+	}
+	setStatusCondition(lv, topolvmv1.LogicalVolumeDrifted, metav1.ConditionTrue, reason, message)
+	if err := d.client.Status().Update(ctx, lv); err != nil {
+		return err
+	}
+	d.log.Info("drift detected", "name", lv.Name, "uid", lv.UID, "type", driftType, "message", message)
+	recordLogicalVolumeEvent(d.recorder, lv, corev1.EventTypeWarning, EventReasonDriftDetected, "%s", message)
+	return nil
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	proto "github.com/topolvm/topolvm/lvmd/proto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("LogicalVolumeDriftDetector", func() {
+	ctx := context.Background()
+
+	setupLogicalVolume := func(suffix string) *topolvmv1.LogicalVolume {
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "drift" + suffix,
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     "drift" + suffix,
+				NodeName: "node-drift",
+				Size:     *resource.NewQuantity(2<<30, resource.BinarySI),
+			},
+		}
+		err := k8sClient.Create(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		lv.Status.VolumeID = string(lv.UID)
+		lv.Status.CurrentSize = resource.NewQuantity(2<<30, resource.BinarySI)
+		err = k8sClient.Status().Update(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+		return lv
+	}
+
+	newDetector := func(recorder record.EventRecorder) *LogicalVolumeDriftDetector {
+		return NewLogicalVolumeDriftDetectorWithServices(k8sClient, recorder, "node-drift",
+			MockVGServiceClient{}, MockLVServiceClient{}, time.Minute, false)
+	}
+
+	It("should detect missing LVs and resolve them", func() {
+		lv := setupLogicalVolume("-missing")
+		recorder := record.NewFakeRecorder(10)
+		detector := newDetector(recorder)
+
+		err := detector.detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(lv), lv)
+		Expect(err).NotTo(HaveOccurred())
+		cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeDrifted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(topolvmv1.ReasonLVMissing))
+		Expect(meta.IsStatusConditionFalse(lv.Status.Conditions, topolvmv1.LogicalVolumeReady)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + EventReasonDriftDetected)))
+
+		// the event is not recorded again for the same drift
+		err = detector.detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		*volumes = append(*volumes, &proto.LogicalVolume{Name: string(lv.UID), SizeBytes: 2 << 30})
+		err = detector.detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(lv), lv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(meta.IsStatusConditionFalse(lv.Status.Conditions, topolvmv1.LogicalVolumeDrifted)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReady)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeNormal + " " + EventReasonDriftResolved)))
+	})
+
+	It("should detect size mismatch and wrong tags", func() {
+		lvSize := setupLogicalVolume("-size")
+		lvTag := setupLogicalVolume("-tag")
+		*volumes = append(*volumes,
+			&proto.LogicalVolume{Name: string(lvSize.UID), SizeBytes: 3 << 30},
+			&proto.LogicalVolume{Name: string(lvTag.UID), SizeBytes: 2 << 30, Tags: []string{"topolvm.io/logicalvolume=other"}},
+		)
+
+		err := newDetector(record.NewFakeRecorder(10)).detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(lvSize), lvSize)
+		Expect(err).NotTo(HaveOccurred())
+		cond := meta.FindStatusCondition(lvSize.Status.Conditions, topolvmv1.LogicalVolumeDrifted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(topolvmv1.ReasonSizeMismatch))
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(lvTag), lvTag)
+		Expect(err).NotTo(HaveOccurred())
+		cond = meta.FindStatusCondition(lvTag.Status.Conditions, topolvmv1.LogicalVolumeDrifted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(topolvmv1.ReasonTagMismatch))
+	})
+
+	It("should accept the size rounded up to the extent size", func() {
+		lv := &topolvmv1.LogicalVolume{
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Size: *resource.NewQuantity(100<<20+4096, resource.BinarySI),
+			},
+			Status: topolvmv1.LogicalVolumeStatus{
+				CurrentSize: resource.NewQuantity(100<<20+4096, resource.BinarySI),
+			},
+		}
+		driftType, _ := checkDrift(lv, &proto.LogicalVolume{SizeBytes: 104 << 20}, 4<<20)
+		Expect(driftType).To(BeEmpty())
+		driftType, _ = checkDrift(lv, &proto.LogicalVolume{SizeBytes: 100 << 20}, 4<<20)
+		Expect(driftType).To(Equal(driftTypeSizeMismatch))
+		driftType, _ = checkDrift(lv, &proto.LogicalVolume{SizeBytes: 108 << 20}, 4<<20)
+		Expect(driftType).To(Equal(driftTypeSizeMismatch))
+		driftType, _ = checkDrift(lv, &proto.LogicalVolume{SizeBytes: 2 << 30}, 4<<20)
+		Expect(driftType).To(Equal(driftTypeSizeMismatch))
+
+		// lvmd that does not report the extent size rounds the size up to GiB.
+		driftType, _ = checkDrift(lv, &proto.LogicalVolume{SizeBytes: 1 << 30}, 0)
+		Expect(driftType).To(BeEmpty())
+		driftType, _ = checkDrift(lv, &proto.LogicalVolume{SizeBytes: 104 << 20}, 0)
+		Expect(driftType).To(Equal(driftTypeSizeMismatch))
+	})
+})
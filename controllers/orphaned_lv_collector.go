@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Event reasons recorded on Node by OrphanedLVCollector.
+const (
+	EventReasonOrphanedLVDetected     = "OrphanedLVDetected"
+	EventReasonOrphanedLVDeleted      = "OrphanedLVDeleted"
+	EventReasonOrphanedLVDeleteFailed = "OrphanedLVDeleteFailed"
+)
+
+var (
+	orphanedVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "topolvm",
+		Subsystem: "orphaned",
+		Name:      "volumes",
+		Help:      "The number of LVs created by TopoLVM but not owned by any LogicalVolume",
+	}, []string{"node", "device_class"})
+
+	orphanedVolumeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "topolvm",
+		Subsystem: "orphaned",
+		Name:      "volume_bytes",
+		Help:      "The total size of LVs created by TopoLVM but not owned by any LogicalVolume",
+	}, []string{"node", "device_class"})
+
+	orphanedVolumesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "topolvm",
+		Subsystem: "orphaned",
+		Name:      "volumes_deleted_total",
+		Help:      "The number of orphaned LVs deleted by TopoLVM",
+	}, []string{"node", "device_class"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedVolumes, orphanedVolumeBytes, orphanedVolumesDeleted)
+}
+
+// OrphanedLVCollector periodically finds LVs that are not owned by any LogicalVolume,
+// e.g. because the LogicalVolume was deleted after its finalizer was removed by hand.
+// Only LVs having the TopoLVM ownership tag are considered, so that LVs TopoLVM
+// did not create are never touched.
+type OrphanedLVCollector struct {
+	client      client.Client
+	recorder    record.EventRecorder
+	nodeName    string
+	vgService   proto.VGServiceClient
+	lvService   proto.LVServiceClient
+	interval    time.Duration
+	gracePeriod time.Duration
+	log         logr.Logger
+
+	// firstSeen records when each orphaned LV was found for the first time.
+	firstSeen map[string]time.Time
+}
+
+var _ manager.LeaderElectionRunnable = &OrphanedLVCollector{}
+
+// NewOrphanedLVCollector returns OrphanedLVCollector with creating lvService and vgService.
+// Orphaned LVs are deleted after they have been orphaned for gracePeriod. If gracePeriod is 0,
+// they are only reported.
+func NewOrphanedLVCollector(client client.Client, recorder record.EventRecorder, nodeName string, conn *grpc.ClientConn, interval, gracePeriod time.Duration) *OrphanedLVCollector {
+	return NewOrphanedLVCollectorWithServices(client, recorder, nodeName, proto.NewVGServiceClient(conn), proto.NewLVServiceClient(conn), interval, gracePeriod)
+}
+
+func NewOrphanedLVCollectorWithServices(client client.Client, recorder record.EventRecorder, nodeName string, vgService proto.VGServiceClient, lvService proto.LVServiceClient, interval, gracePeriod time.Duration) *OrphanedLVCollector {
+	return &OrphanedLVCollector{
+		client:      client,
+		recorder:    recorder,
+		nodeName:    nodeName,
+		vgService:   vgService,
+		lvService:   lvService,
+		interval:    interval,
+		gracePeriod: gracePeriod,
+		log:         ctrl.Log.WithName("controllers").WithName("OrphanedLVCollector"),
+		firstSeen:   make(map[string]time.Time),
+	}
+}
+
+// Start implements controller-runtime's manager.Runnable.
+func (c *OrphanedLVCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.collect(ctx); err != nil {
+				c.log.Error(err, "failed to collect orphaned LVs")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
+func (c *OrphanedLVCollector) NeedLeaderElection() bool {
+	return false
+}
+
+func (c *OrphanedLVCollector) collect(ctx context.Context) error {
+	deviceClasses, err := c.deviceClasses(ctx)
+	if err != nil {
+		return err
+	}
+
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := c.client.List(ctx, lvList); err != nil {
+		return err
+	}
+	owned := make(map[string]struct{}, len(lvList.Items))
+	// unnamed has the owner tags of the LogicalVolumes whose LVs may have been created
+	// but status.lvName is not recorded yet.
+	unnamed := make(map[string]struct{})
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		owned[string(lv.UID)] = struct{}{}
+		if lv.Status.VolumeID != "" {
+			owned[lv.Status.VolumeID] = struct{}{}
+		}
+		if lv.Status.LVName != "" {
+			owned[lv.Status.LVName] = struct{}{}
+		} else {
+			unnamed[topolvm.GetLVOwnerTag(lv.Name)] = struct{}{}
+		}
+	}
+
+	node := new(corev1.Node)
+	if err := c.client.Get(ctx, types.NamespacedName{Name: c.nodeName}, node); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{})
+	for _, dc := range deviceClasses {
+		resp, err := c.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: dc})
+		if err != nil {
+			c.log.Error(err, "failed to list LV", "device_class", dc)
+			continue
+		}
+
+		var count int
+		var sizeBytes uint64
+		for _, v := range resp.Volumes {
+			if _, ok := owned[v.Name]; ok || !hasOwnerTag(v) || hasAnyTag(v, unnamed) {
+				continue
+			}
+
+			key := dc + "/" + v.Name
+			seen[key] = struct{}{}
+			first, ok := c.firstSeen[key]
+			if !ok {
+				first = time.Now()
+				c.firstSeen[key] = first
+				c.log.Info("found orphaned LV", "name", v.Name, "device_class", dc, "tags", v.Tags)
+				c.recorder.Eventf(node, corev1.EventTypeWarning, EventReasonOrphanedLVDetected,
+					"LV %s in device-class %q is not owned by any LogicalVolume", v.Name, dc)
+			}
+
+			if c.gracePeriod > 0 && time.Since(first) >= c.gracePeriod {
+				removeResp, err := c.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: v.Name, DeviceClass: dc, AsyncWipe: true})
+				if err != nil {
+					c.log.Error(err, "failed to remove orphaned LV", "name", v.Name, "device_class", dc)
+					c.recorder.Eventf(node, corev1.EventTypeWarning, EventReasonOrphanedLVDeleteFailed,
+						"failed to remove orphaned LV %s in device-class %q: %v", v.Name, dc, err)
+				} else if removeResp.GetWiping() {
+					// The LV is counted as orphaned until the wipe finishes.
+					c.log.Info("wiping orphaned LV", "name", v.Name, "device_class", dc,
+						"wiped", removeResp.GetWipedBytes(), "total", removeResp.GetTotalBytes())
+				} else {
+					c.log.Info("removed orphaned LV", "name", v.Name, "device_class", dc)
+					c.recorder.Eventf(node, corev1.EventTypeNormal, EventReasonOrphanedLVDeleted,
+						"removed orphaned LV %s in device-class %q", v.Name, dc)
+					orphanedVolumesDeleted.WithLabelValues(c.nodeName, dc).Inc()
+					delete(c.firstSeen, key)
+					continue
+				}
+			}
+			count++
+			sizeBytes += v.SizeBytes
+		}
+		orphanedVolumes.WithLabelValues(c.nodeName, dc).Set(float64(count))
+		orphanedVolumeBytes.WithLabelValues(c.nodeName, dc).Set(float64(sizeBytes))
+	}
+
+	// Forget LVs that are no longer orphaned, e.g. removed by hand.
+	for key := range c.firstSeen {
+		if _, ok := seen[key]; !ok {
+			delete(c.firstSeen, key)
+		}
+	}
+	return nil
+}
+
+// deviceClasses returns the names of device classes served by lvmd.
+func (c *OrphanedLVCollector) deviceClasses(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wc, err := c.vgService.Watch(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	res, err := wc.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	deviceClasses := make([]string, 0, len(res.Items))
+	for _, item := range res.Items {
+		deviceClasses = append(deviceClasses, item.DeviceClass)
+	}
+	return deviceClasses, nil
+}
+
+func hasOwnerTag(v *proto.LogicalVolume) bool {
+	for _, tag := range v.Tags {
+		if strings.HasPrefix(tag, topolvm.LVOwnerTagPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(v *proto.LogicalVolume, tags map[string]struct{}) bool {
+	for _, tag := range v.Tags {
+		if _, ok := tags[tag]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	proto "github.com/topolvm/topolvm/lvmd/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("OrphanedLVCollector", func() {
+	ctx := context.Background()
+
+	findVolume := func(name string) *proto.LogicalVolume {
+		for _, v := range *volumes {
+			if v.Name == name {
+				return v
+			}
+		}
+		return nil
+	}
+
+	It("should report and delete orphaned LVs having the ownership tag", func() {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-orphan",
+			},
+		}
+		err := k8sClient.Create(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+
+		*volumes = append(*volumes,
+			&proto.LogicalVolume{Name: "orphaned", SizeBytes: 1 << 30, Tags: []string{topolvm.GetLVOwnerTag("deleted")}},
+			&proto.LogicalVolume{Name: "not-created-by-topolvm", SizeBytes: 1 << 30},
+		)
+
+		recorder := record.NewFakeRecorder(10)
+		collector := NewOrphanedLVCollectorWithServices(k8sClient, recorder, "node-orphan",
+			MockVGServiceClient{}, MockLVServiceClient{}, time.Minute, time.Hour)
+
+		err = collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(And(
+			HavePrefix(corev1.EventTypeWarning+" "+EventReasonOrphanedLVDetected),
+			ContainSubstring("orphaned"),
+		)))
+		Expect(recorder.Events).NotTo(Receive())
+		Expect(findVolume("orphaned")).NotTo(BeNil())
+
+		// delete the orphaned LV after the grace period
+		collector.gracePeriod = time.Nanosecond
+		err = collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeNormal + " " + EventReasonOrphanedLVDeleted)))
+		Expect(findVolume("orphaned")).To(BeNil())
+		Expect(findVolume("not-created-by-topolvm")).NotTo(BeNil())
+	})
+})
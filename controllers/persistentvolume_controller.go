@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Event reasons recorded on LogicalVolume by PersistentVolumeReconciler.
+const (
+	EventReasonReleased     = "Released"
+	EventReasonRebinding    = "Rebinding"
+	EventReasonRebindFailed = "RebindFailed"
+	EventReasonRebound      = "Rebound"
+)
+
+// rebindRetryInterval is the interval to retry re-binding when the target PVC is not ready.
+const rebindRetryInterval = 30 * time.Second
+
+// PersistentVolumeReconciler reconciles PersistentVolumes provisioned by TopoLVM
+// to support the Retain reclaim policy.
+//
+// When the PVC of a PV with the Retain reclaim policy is deleted, the LogicalVolume is
+// marked as Released and detached from the PVC. The volume can be re-bound to a new PVC
+// by annotating the PV with topolvm.io/rebind-to.
+type PersistentVolumeReconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+// NewPersistentVolumeReconciler returns PersistentVolumeReconciler.
+func NewPersistentVolumeReconciler(client client.Client, recorder record.EventRecorder) *PersistentVolumeReconciler {
+	return &PersistentVolumeReconciler{
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile tracks the phase of PersistentVolume on its LogicalVolume.
+func (r *PersistentVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+	pv := &corev1.PersistentVolume{}
+	err := r.client.Get(ctx, req.NamespacedName, pv)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, r.detachDeletedPV(ctx, log, req.Name)
+	default:
+		return ctrl.Result{}, err
+	}
+
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != topolvm.GetPluginName() {
+		return ctrl.Result{}, nil
+	}
+
+	lv, err := r.findVolume(ctx, pv.Spec.CSI.VolumeHandle)
+	if err != nil {
+		log.Error(err, "failed to find LogicalVolume", "volume_id", pv.Spec.CSI.VolumeHandle)
+		return ctrl.Result{}, err
+	}
+	if lv == nil || lv.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	switch pv.Status.Phase {
+	case corev1.VolumeReleased:
+		if pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+			return ctrl.Result{}, nil
+		}
+		if err := r.release(ctx, log, pv, lv); err != nil {
+			log.Error(err, "failed to release LogicalVolume", "name", lv.Name)
+			return ctrl.Result{}, err
+		}
+		if target, ok := pv.Annotations[topolvm.GetRebindToKey()]; ok {
+			return r.rebind(ctx, log, pv, lv, target)
+		}
+	case corev1.VolumeBound:
+		if err := r.bind(ctx, log, pv, lv); err != nil {
+			log.Error(err, "failed to bind LogicalVolume", "name", lv.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// findVolume returns the LogicalVolume of the volume ID, or nil if it does not exist.
+func (r *PersistentVolumeReconciler) findVolume(ctx context.Context, volumeID string) (*topolvmv1.LogicalVolume, error) {
+	return findLogicalVolume(ctx, r.client, volumeID)
+}
+
+// findLogicalVolume returns the LogicalVolume of the volume ID, or nil if it does not exist.
+func findLogicalVolume(ctx context.Context, c client.Client, volumeID string) (*topolvmv1.LogicalVolume, error) {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := c.List(ctx, lvList); err != nil {
+		return nil, err
+	}
+	for i := range lvList.Items {
+		if lvList.Items[i].Status.VolumeID == volumeID {
+			return &lvList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// release marks the LogicalVolume as Released and detaches it from the deleted PVC.
+func (r *PersistentVolumeReconciler) release(ctx context.Context, log logr.Logger, pv *corev1.PersistentVolume, lv *topolvmv1.LogicalVolume) error {
+	if meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReleased) && lv.Spec.PVCName == "" {
+		return nil
+	}
+
+	var claim string
+	if pv.Spec.ClaimRef != nil {
+		claim = pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+	}
+	if lv.Spec.PVCName != "" || lv.Spec.PVCNamespace != "" || lv.Spec.PVName != pv.Name {
+		lv.Spec.PVCName = ""
+		lv.Spec.PVCNamespace = ""
+		lv.Spec.PVName = pv.Name
+		if err := r.client.Update(ctx, lv); err != nil {
+			return err
+		}
+	}
+
+	setStatusCondition(lv, topolvmv1.LogicalVolumeReleased, metav1.ConditionTrue, topolvmv1.ReasonReleased,
+		fmt.Sprintf("PersistentVolume %s was released from PersistentVolumeClaim %s", pv.Name, claim))
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		return err
+	}
+	log.Info("released LogicalVolume", "name", lv.Name, "pv", pv.Name, "pvc", claim)
+	recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeNormal, EventReasonReleased,
+		"PersistentVolume %s was released from PersistentVolumeClaim %s; the volume is retained", pv.Name, claim)
+	return nil
+}
+
+// rebind reserves the released PV for the PVC specified by target, which is in the form of "<namespace>/<name>".
+// Kubernetes then binds the PVC to the PV.
+func (r *PersistentVolumeReconciler) rebind(ctx context.Context, log logr.Logger, pv *corev1.PersistentVolume, lv *topolvmv1.LogicalVolume, target string) (ctrl.Result, error) {
+	namespace, name, ok := strings.Cut(target, "/")
+	if !ok || namespace == "" || name == "" {
+		recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeWarning, EventReasonRebindFailed,
+			"invalid %s annotation on PersistentVolume %s: %q", topolvm.GetRebindToKey(), pv.Name, target)
+		return ctrl.Result{}, nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pvc)
+	if apierrors.IsNotFound(err) {
+		recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeWarning, EventReasonRebindFailed,
+			"PersistentVolumeClaim %s is not found", target)
+		return ctrl.Result{RequeueAfter: rebindRetryInterval}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if msg := checkRebindTarget(pv, lv, pvc); msg != "" {
+		recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeWarning, EventReasonRebindFailed,
+			"cannot re-bind PersistentVolume %s to PersistentVolumeClaim %s: %s", pv.Name, target, msg)
+		return ctrl.Result{RequeueAfter: rebindRetryInterval}, nil
+	}
+
+	// The claimRef without UID pre-binds the PV to the PVC.
+	pv.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Namespace:  namespace,
+		Name:       name,
+	}
+	delete(pv.Annotations, topolvm.GetRebindToKey())
+	if err := r.client.Update(ctx, pv); err != nil {
+		log.Error(err, "failed to update PersistentVolume", "name", pv.Name)
+		return ctrl.Result{}, err
+	}
+	log.Info("re-binding PersistentVolume", "name", pv.Name, "pvc", target, "logicalvolume", lv.Name)
+	recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeNormal, EventReasonRebinding,
+		"re-binding PersistentVolume %s to PersistentVolumeClaim %s", pv.Name, target)
+	return ctrl.Result{}, nil
+}
+
+// checkRebindTarget returns the reason why the PVC cannot be bound to the PV, or empty string if it can.
+func checkRebindTarget(pv *corev1.PersistentVolume, lv *topolvmv1.LogicalVolume, pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.DeletionTimestamp != nil {
+		return "the claim is being deleted"
+	}
+	if pvc.Spec.VolumeName != "" && pvc.Spec.VolumeName != pv.Name {
+		return fmt.Sprintf("the claim is bound to PersistentVolume %s", pvc.Spec.VolumeName)
+	}
+	var storageClassName string
+	if pvc.Spec.StorageClassName != nil {
+		storageClassName = *pvc.Spec.StorageClassName
+	}
+	if storageClassName != pv.Spec.StorageClassName {
+		return fmt.Sprintf("StorageClass %q of the claim differs from %q", storageClassName, pv.Spec.StorageClassName)
+	}
+	if node, ok := pvc.Annotations[AnnSelectedNode]; ok && node != lv.Spec.NodeName {
+		return fmt.Sprintf("the claim is scheduled to node %s, but the volume is on node %s", node, lv.Spec.NodeName)
+	}
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+	if request.Cmp(capacity) > 0 {
+		return fmt.Sprintf("the claim requests %s, but the volume has %s", request.String(), capacity.String())
+	}
+	return ""
+}
+
+// bind records the PVC on the LogicalVolume that has been re-bound after release.
+func (r *PersistentVolumeReconciler) bind(ctx context.Context, log logr.Logger, pv *corev1.PersistentVolume, lv *topolvmv1.LogicalVolume) error {
+	if !meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReleased) || pv.Spec.ClaimRef == nil {
+		return nil
+	}
+
+	claim := pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+	lv.Spec.PVCName = pv.Spec.ClaimRef.Name
+	lv.Spec.PVCNamespace = pv.Spec.ClaimRef.Namespace
+	lv.Spec.PVName = pv.Name
+	if err := r.client.Update(ctx, lv); err != nil {
+		return err
+	}
+
+	setStatusCondition(lv, topolvmv1.LogicalVolumeReleased, metav1.ConditionFalse, topolvmv1.ReasonBound,
+		fmt.Sprintf("PersistentVolume %s is bound to PersistentVolumeClaim %s", pv.Name, claim))
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		return err
+	}
+	log.Info("re-bound LogicalVolume", "name", lv.Name, "pv", pv.Name, "pvc", claim)
+	recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeNormal, EventReasonRebound,
+		"PersistentVolume %s is bound to PersistentVolumeClaim %s", pv.Name, claim)
+	return nil
+}
+
+// detachDeletedPV clears spec.pvName of released LogicalVolumes whose PV has been deleted,
+// so that they can be bound by statically provisioned PVs.
+func (r *PersistentVolumeReconciler) detachDeletedPV(ctx context.Context, log logr.Logger, pvName string) error {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := r.client.List(ctx, lvList); err != nil {
+		return err
+	}
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		if lv.Spec.PVName != pvName || !meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReleased) {
+			continue
+		}
+		lv.Spec.PVName = ""
+		if err := r.client.Update(ctx, lv); err != nil {
+			return err
+		}
+		log.Info("detached LogicalVolume from deleted PersistentVolume", "name", lv.Name, "pv", pvName)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PersistentVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.PersistentVolume{}).
+		Complete(r)
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PersistentVolume controller", func() {
+	ctx := context.Background()
+	var stopFunc func()
+	errCh := make(chan error)
+
+	BeforeEach(func() {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme: scheme,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		reconciler := NewPersistentVolumeReconciler(mgr.GetClient(), mgr.GetEventRecorderFor("topolvm-controller"))
+		err = reconciler.SetupWithManager(mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		stopFunc = cancel
+		go func() {
+			errCh <- mgr.Start(ctx)
+		}()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	AfterEach(func() {
+		stopFunc()
+		Expect(<-errCh).NotTo(HaveOccurred())
+	})
+
+	It("should release the retained LogicalVolume and re-bind it to a new PVC", func() {
+		ctx := context.Background()
+		ns := createNamespace()
+
+		// Setup
+		lv := topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-retain",
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:         "pv-retain",
+				NodeName:     "node-retain",
+				Size:         *resource.NewQuantity(1<<30, resource.BinarySI),
+				PVCName:      "old-pvc",
+				PVCNamespace: ns,
+				PVName:       "pv-retain",
+			},
+		}
+		err := k8sClient.Create(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+		lv.Status.VolumeID = "volume-retain"
+		err = k8sClient.Status().Update(ctx, &lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		pv := corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-retain",
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: *resource.NewQuantity(1<<30, resource.BinarySI),
+				},
+				AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				StorageClassName:              "sc-retain",
+				ClaimRef: &corev1.ObjectReference{
+					Kind:       "PersistentVolumeClaim",
+					APIVersion: "v1",
+					Namespace:  ns,
+					Name:       "old-pvc",
+					UID:        "old-pvc-uid",
+				},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:       topolvm.GetPluginName(),
+						VolumeHandle: "volume-retain",
+					},
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, &pv)
+		Expect(err).NotTo(HaveOccurred())
+		pv.Status.Phase = corev1.VolumeReleased
+		err = k8sClient.Status().Update(ctx, &pv)
+		Expect(err).NotTo(HaveOccurred())
+
+		// ensure the LogicalVolume is released
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(meta.IsStatusConditionTrue(lv.Status.Conditions, topolvmv1.LogicalVolumeReleased)).To(BeTrue())
+			g.Expect(lv.Spec.PVCName).To(BeEmpty())
+			g.Expect(lv.Spec.PVCNamespace).To(BeEmpty())
+			g.Expect(lv.Spec.PVName).To(Equal(pv.Name))
+		}).Should(Succeed())
+
+		// request re-binding to a new PVC
+		scName := "sc-retain"
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "new-pvc",
+				Namespace: ns,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &scName,
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: *resource.NewQuantity(1<<30, resource.BinarySI),
+					},
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, &pvc)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&pv), &pv)
+		Expect(err).NotTo(HaveOccurred())
+		pv.Annotations = map[string]string{topolvm.GetRebindToKey(): ns + "/new-pvc"}
+		err = k8sClient.Update(ctx, &pv)
+		Expect(err).NotTo(HaveOccurred())
+
+		// ensure the PV is reserved for the new PVC
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&pv), &pv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pv.Annotations).NotTo(HaveKey(topolvm.GetRebindToKey()))
+			g.Expect(pv.Spec.ClaimRef).NotTo(BeNil())
+			g.Expect(pv.Spec.ClaimRef.Name).To(Equal("new-pvc"))
+			g.Expect(pv.Spec.ClaimRef.UID).To(BeEmpty())
+		}).Should(Succeed())
+
+		// simulate the binding by Kubernetes
+		pv.Status.Phase = corev1.VolumeBound
+		err = k8sClient.Status().Update(ctx, &pv)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&lv), &lv)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(meta.IsStatusConditionFalse(lv.Status.Conditions, topolvmv1.LogicalVolumeReleased)).To(BeTrue())
+			g.Expect(lv.Spec.PVCName).To(Equal("new-pvc"))
+			g.Expect(lv.Spec.PVCNamespace).To(Equal(ns))
+		}).Should(Succeed())
+	})
+})
@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Event reasons recorded on PersistentVolumeClaim by PVCAutoresizer.
+const (
+	EventReasonAutoResized       = "AutoResized"
+	EventReasonAutoResizeSkipped = "AutoResizeSkipped"
+	EventReasonAutoResizeFailed  = "AutoResizeFailed"
+)
+
+const (
+	// defaultAutoResizeThreshold is the filesystem usage in percent at which PVCs are expanded
+	// when the threshold annotation is not given.
+	defaultAutoResizeThreshold = 80
+
+	// defaultAutoResizeIncrease is the amount of the expansion when the increase annotation is not given.
+	defaultAutoResizeIncrease = "10%"
+)
+
+// autoResizePolicy is the policy of the automatic expansion given by the annotations of a PVC.
+type autoResizePolicy struct {
+	limit     int64
+	threshold int64
+
+	// Either increasePercent or increaseBytes is set.
+	increasePercent int64
+	increaseBytes   int64
+}
+
+// PVCAutoresizer periodically expands PVCs whose filesystem usage recorded in
+// the LogicalVolume exceeds the threshold given by the annotations of the PVC.
+type PVCAutoresizer struct {
+	client      client.Client
+	recorder    record.EventRecorder
+	interval    time.Duration
+	minInterval time.Duration
+	log         logr.Logger
+}
+
+var _ manager.LeaderElectionRunnable = &PVCAutoresizer{}
+
+// NewPVCAutoresizer returns PVCAutoresizer.
+// A PVC is not expanded again until minInterval has passed since the last expansion.
+func NewPVCAutoresizer(client client.Client, recorder record.EventRecorder, interval, minInterval time.Duration) *PVCAutoresizer {
+	return &PVCAutoresizer{
+		client:      client,
+		recorder:    recorder,
+		interval:    interval,
+		minInterval: minInterval,
+		log:         ctrl.Log.WithName("controllers").WithName("PVCAutoresizer"),
+	}
+}
+
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Start implements controller-runtime's manager.Runnable.
+func (r *PVCAutoresizer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.resize(ctx, time.Now()); err != nil {
+				r.log.Error(err, "failed to resize PVCs automatically")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
+func (r *PVCAutoresizer) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *PVCAutoresizer) resize(ctx context.Context, now time.Time) error {
+	pvcList := new(corev1.PersistentVolumeClaimList)
+	if err := r.client.List(ctx, pvcList); err != nil {
+		return err
+	}
+
+	var lvs map[string]*topolvmv1.LogicalVolume
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if _, ok := pvc.Annotations[topolvm.GetAutoResizeLimitKey()]; !ok {
+			continue
+		}
+		if pvc.DeletionTimestamp != nil || pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+
+		// LogicalVolumes are listed only when there is a PVC to be checked.
+		if lvs == nil {
+			lvList := new(topolvmv1.LogicalVolumeList)
+			if err := r.client.List(ctx, lvList); err != nil {
+				return err
+			}
+			lvs = make(map[string]*topolvmv1.LogicalVolume, len(lvList.Items))
+			for j := range lvList.Items {
+				lv := &lvList.Items[j]
+				if lv.Status.VolumeID != "" {
+					lvs[lv.Status.VolumeID] = lv
+				}
+			}
+		}
+
+		if err := r.resizePVC(ctx, pvc, lvs, now); err != nil {
+			r.log.Error(err, "failed to resize PVC", "namespace", pvc.Namespace, "name", pvc.Name)
+			r.recorder.Eventf(pvc, corev1.EventTypeWarning, EventReasonAutoResizeFailed,
+				"failed to expand the volume automatically: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *PVCAutoresizer) resizePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim, lvs map[string]*topolvmv1.LogicalVolume, now time.Time) error {
+	log := r.log.WithValues("namespace", pvc.Namespace, "name", pvc.Name)
+
+	policy, err := parseAutoResizePolicy(pvc.Annotations)
+	if err != nil {
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, EventReasonAutoResizeSkipped, "invalid annotation: %v", err)
+		return nil
+	}
+
+	if pvc.Spec.StorageClassName == nil {
+		return nil
+	}
+	sc := new(storagev1.StorageClass)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, sc); err != nil {
+		return err
+	}
+	if sc.Provisioner != topolvm.GetPluginName() {
+		return nil
+	}
+	if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+		log.Info("skipped because the StorageClass does not allow volume expansion", "storage_class", sc.Name)
+		return nil
+	}
+
+	pv := new(corev1.PersistentVolume)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+		return err
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != topolvm.GetPluginName() {
+		return nil
+	}
+	lv, ok := lvs[pv.Spec.CSI.VolumeHandle]
+	if !ok || lv.Status.Filesystem == nil {
+		// The usage is not recorded until kubelet collects the volume stats.
+		return nil
+	}
+
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if capacity.Cmp(request) < 0 {
+		log.Info("skipped because the volume is being expanded")
+		return nil
+	}
+
+	if v, ok := pvc.Annotations[topolvm.GetAutoResizedAtKey()]; ok {
+		resizedAt, err := time.Parse(time.RFC3339, v)
+		if err == nil {
+			if now.Sub(resizedAt) < r.minInterval {
+				return nil
+			}
+			// The usage observed before the last expansion is stale.
+			if lv.Status.Filesystem.ObservedTime.Time.Before(resizedAt) {
+				return nil
+			}
+		}
+	}
+
+	fs := lv.Status.Filesystem
+	if fs.CapacityBytes <= 0 || fs.UsedBytes*100 < policy.threshold*fs.CapacityBytes {
+		return nil
+	}
+
+	current := request.Value()
+	if current >= policy.limit {
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, EventReasonAutoResizeSkipped,
+			"filesystem usage %d%% exceeds the threshold %d%% but the volume has reached the limit %d",
+			fs.UsedBytes*100/fs.CapacityBytes, policy.threshold, policy.limit)
+		return nil
+	}
+	newSize := policy.newSize(current)
+
+	free, err := r.freeBytes(ctx, lv)
+	if err != nil {
+		return err
+	}
+	if newSize-current > free {
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, EventReasonAutoResizeSkipped,
+			"not enough free space on node %s to expand the volume by %d bytes: free=%d",
+			lv.Spec.NodeName, newSize-current, free)
+		return nil
+	}
+
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *resource.NewQuantity(newSize, resource.BinarySI)
+	pvc.Annotations[topolvm.GetAutoResizedAtKey()] = now.UTC().Format(time.RFC3339)
+	if err := r.client.Update(ctx, pvc); err != nil {
+		return err
+	}
+
+	log.Info("expanded the volume", "used_bytes", fs.UsedBytes, "capacity_bytes", fs.CapacityBytes,
+		"from", current, "to", newSize)
+	r.recorder.Eventf(pvc, corev1.EventTypeNormal, EventReasonAutoResized,
+		"expanded the volume from %d to %d bytes because filesystem usage %d%% exceeds the threshold %d%%",
+		current, newSize, fs.UsedBytes*100/fs.CapacityBytes, policy.threshold)
+	return nil
+}
+
+// freeBytes returns the free capacity of the device class of lv on the node.
+func (r *PVCAutoresizer) freeBytes(ctx context.Context, lv *topolvmv1.LogicalVolume) (int64, error) {
+	node := new(corev1.Node)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: lv.Spec.NodeName}, node); err != nil {
+		return 0, err
+	}
+
+	deviceClass := lv.Spec.DeviceClass
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		deviceClass = topolvm.DefaultDeviceClassAnnotationName
+	}
+	c, ok := node.Annotations[topolvm.GetCapacityKeyPrefix()+deviceClass]
+	if !ok {
+		return 0, fmt.Errorf("capacity of device class %q is not found on node %s", lv.Spec.DeviceClass, node.Name)
+	}
+	return strconv.ParseInt(c, 10, 64)
+}
+
+// parseAutoResizePolicy parses the annotations for the automatic expansion.
+func parseAutoResizePolicy(annotations map[string]string) (*autoResizePolicy, error) {
+	limit, err := resource.ParseQuantity(annotations[topolvm.GetAutoResizeLimitKey()])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", topolvm.GetAutoResizeLimitKey(), err)
+	}
+	policy := &autoResizePolicy{
+		limit:     limit.Value(),
+		threshold: defaultAutoResizeThreshold,
+	}
+	if policy.limit <= 0 {
+		return nil, fmt.Errorf("%s must be positive", topolvm.GetAutoResizeLimitKey())
+	}
+
+	if v, ok := annotations[topolvm.GetAutoResizeThresholdKey()]; ok {
+		threshold, err := strconv.ParseInt(strings.TrimSuffix(v, "%"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", topolvm.GetAutoResizeThresholdKey(), err)
+		}
+		if threshold <= 0 || threshold > 100 {
+			return nil, fmt.Errorf("%s must be between 1 and 100", topolvm.GetAutoResizeThresholdKey())
+		}
+		policy.threshold = threshold
+	}
+
+	increase := defaultAutoResizeIncrease
+	if v, ok := annotations[topolvm.GetAutoResizeIncreaseKey()]; ok {
+		increase = v
+	}
+	if strings.HasSuffix(increase, "%") {
+		percent, err := strconv.ParseInt(strings.TrimSuffix(increase, "%"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", topolvm.GetAutoResizeIncreaseKey(), err)
+		}
+		if percent <= 0 {
+			return nil, fmt.Errorf("%s must be positive", topolvm.GetAutoResizeIncreaseKey())
+		}
+		policy.increasePercent = percent
+	} else {
+		q, err := resource.ParseQuantity(increase)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", topolvm.GetAutoResizeIncreaseKey(), err)
+		}
+		if q.Value() <= 0 {
+			return nil, fmt.Errorf("%s must be positive", topolvm.GetAutoResizeIncreaseKey())
+		}
+		policy.increaseBytes = q.Value()
+	}
+	return policy, nil
+}
+
+// newSize returns the size to which a volume of current bytes is expanded.
+// The size is rounded up to GiB so that volumes grow in whole GiB, and capped at the limit.
+func (p *autoResizePolicy) newSize(current int64) int64 {
+	increase := p.increaseBytes
+	if p.increasePercent > 0 {
+		increase = int64(math.Ceil(float64(current) * float64(p.increasePercent) / 100))
+	}
+	size := current + increase
+	if rem := size % (1 << 30); rem != 0 {
+		size += 1<<30 - rem
+	}
+	if size > p.limit {
+		size = p.limit
+	}
+	return size
+}
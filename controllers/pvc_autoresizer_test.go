@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PVCAutoresizer", func() {
+	ctx := context.Background()
+
+	It("should parse the annotations", func() {
+		policy, err := parseAutoResizePolicy(map[string]string{
+			topolvm.GetAutoResizeLimitKey(): "100Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(&autoResizePolicy{limit: 100 << 30, threshold: 80, increasePercent: 10}))
+
+		policy, err = parseAutoResizePolicy(map[string]string{
+			topolvm.GetAutoResizeLimitKey():     "100Gi",
+			topolvm.GetAutoResizeThresholdKey(): "90%",
+			topolvm.GetAutoResizeIncreaseKey():  "5Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(&autoResizePolicy{limit: 100 << 30, threshold: 90, increaseBytes: 5 << 30}))
+
+		for _, annotations := range []map[string]string{
+			{topolvm.GetAutoResizeLimitKey(): "foo"},
+			{topolvm.GetAutoResizeLimitKey(): "0"},
+			{topolvm.GetAutoResizeLimitKey(): "1Gi", topolvm.GetAutoResizeThresholdKey(): "101"},
+			{topolvm.GetAutoResizeLimitKey(): "1Gi", topolvm.GetAutoResizeIncreaseKey(): "-1%"},
+			{topolvm.GetAutoResizeLimitKey(): "1Gi", topolvm.GetAutoResizeIncreaseKey(): "foo"},
+		} {
+			_, err := parseAutoResizePolicy(annotations)
+			Expect(err).To(HaveOccurred(), "%v", annotations)
+		}
+	})
+
+	It("should calculate the new size", func() {
+		policy := &autoResizePolicy{limit: 20 << 30, increasePercent: 10}
+		Expect(policy.newSize(10 << 30)).To(Equal(int64(11 << 30)))
+		Expect(policy.newSize(1 << 30)).To(Equal(int64(2 << 30)))
+		Expect(policy.newSize(19 << 30)).To(Equal(int64(20 << 30)))
+
+		policy = &autoResizePolicy{limit: 20 << 30, increaseBytes: 1<<30 + 1}
+		Expect(policy.newSize(10 << 30)).To(Equal(int64(12 << 30)))
+	})
+
+	It("should expand the PVC when the filesystem usage exceeds the threshold", func() {
+		ns := createNamespace()
+		allowExpansion := true
+		sc := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "autoresize"},
+			Provisioner:          topolvm.GetPluginName(),
+			AllowVolumeExpansion: &allowExpansion,
+		}
+		err := k8sClient.Create(ctx, sc)
+		Expect(err).NotTo(HaveOccurred())
+
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-autoresize",
+				Annotations: map[string]string{
+					topolvm.GetCapacityKeyPrefix() + topolvm.DefaultDeviceClassAnnotationName: "2147483648",
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-autoresize"},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     "pv-autoresize",
+				NodeName: "node-autoresize",
+				Size:     *resource.NewQuantity(10<<30, resource.BinarySI),
+			},
+		}
+		err = k8sClient.Create(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+		lv.Status.VolumeID = "volume-autoresize"
+		lv.Status.Filesystem = &topolvmv1.FilesystemUsage{
+			CapacityBytes: 10 << 30,
+			UsedBytes:     9 << 30,
+			ObservedTime:  metav1.Now(),
+		}
+		err = k8sClient.Status().Update(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-autoresize"},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: *resource.NewQuantity(10<<30, resource.BinarySI),
+				},
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:       topolvm.GetPluginName(),
+						VolumeHandle: "volume-autoresize",
+					},
+				},
+				StorageClassName: "autoresize",
+			},
+		}
+		err = k8sClient.Create(ctx, pv)
+		Expect(err).NotTo(HaveOccurred())
+
+		scName := "autoresize"
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pvc-autoresize",
+				Namespace: ns,
+				Annotations: map[string]string{
+					topolvm.GetAutoResizeLimitKey(): "12Gi",
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: *resource.NewQuantity(10<<30, resource.BinarySI),
+					},
+				},
+				StorageClassName: &scName,
+				VolumeName:       "pv-autoresize",
+			},
+		}
+		err = k8sClient.Create(ctx, pvc)
+		Expect(err).NotTo(HaveOccurred())
+		pvc.Status.Phase = corev1.ClaimBound
+		pvc.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceStorage: *resource.NewQuantity(10<<30, resource.BinarySI),
+		}
+		err = k8sClient.Status().Update(ctx, pvc)
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder := record.NewFakeRecorder(10)
+		autoresizer := NewPVCAutoresizer(k8sClient, recorder, time.Minute, 10*time.Minute)
+		now := time.Now()
+		err = autoresizer.resize(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeNormal + " " + EventReasonAutoResized)))
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)
+		Expect(err).NotTo(HaveOccurred())
+		request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		Expect(request.Value()).To(Equal(int64(11 << 30)))
+		Expect(pvc.Annotations).To(HaveKey(topolvm.GetAutoResizedAtKey()))
+
+		// the PVC is not expanded while the expansion is in progress
+		err = autoresizer.resize(ctx, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		// the PVC is not expanded beyond the free space of the node
+		pvc.Status.Capacity[corev1.ResourceStorage] = request
+		err = k8sClient.Status().Update(ctx, pvc)
+		Expect(err).NotTo(HaveOccurred())
+		lv.Status.Filesystem.ObservedTime = metav1.NewTime(now.Add(time.Hour))
+		err = k8sClient.Status().Update(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+		node.Annotations[topolvm.GetCapacityKeyPrefix()+topolvm.DefaultDeviceClassAnnotationName] = "0"
+		err = k8sClient.Update(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = autoresizer.resize(ctx, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + EventReasonAutoResizeSkipped)))
+	})
+})
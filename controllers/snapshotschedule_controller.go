@@ -0,0 +1,406 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Event reasons recorded on SnapshotSchedule by SnapshotScheduleReconciler.
+const (
+	EventReasonSnapshotCreated = "SnapshotCreated"
+	EventReasonSnapshotFailed  = "SnapshotFailed"
+	EventReasonSnapshotSkipped = "SnapshotSkipped"
+	EventReasonSnapshotPruned  = "SnapshotPruned"
+	EventReasonInvalidSchedule = "InvalidSchedule"
+)
+
+// snapshotNameTimeFormat is the format of the scheduled time in the names of the snapshots.
+const snapshotNameTimeFormat = "20060102-1504"
+
+// snapshotNameHashLength is the length of the hash in the truncated names of the snapshots.
+const snapshotNameHashLength = 8
+
+// defaultThinPoolDataPercentLimit is used when spec.thinPoolDataPercentLimit is not defaulted by the API server.
+const defaultThinPoolDataPercentLimit = 90
+
+// SnapshotScheduleReconciler takes VolumeSnapshots of the PersistentVolumeClaims selected by
+// SnapshotSchedule periodically, and deletes the snapshots beyond the retention.
+//
+// Snapshots of the volumes in a thin pool whose data usage is above the limit are skipped, because
+// the snapshots share the pool with the volumes and make it fill up faster.
+type SnapshotScheduleReconciler struct {
+	client    client.Client
+	apiReader client.Reader
+	recorder  record.EventRecorder
+}
+
+// NewSnapshotScheduleReconciler returns SnapshotScheduleReconciler.
+func NewSnapshotScheduleReconciler(client client.Client, apiReader client.Reader, recorder record.EventRecorder) *SnapshotScheduleReconciler {
+	return &SnapshotScheduleReconciler{
+		client:    client,
+		apiReader: apiReader,
+		recorder:  recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups=topolvm.io,resources=snapshotschedules,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=snapshotschedules/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile takes the snapshots if the scheduled time has come, and prunes the old snapshots.
+func (r *SnapshotScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+	ss := new(topolvmv1.SnapshotSchedule)
+	if err := r.client.Get(ctx, req.NamespacedName, ss); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch SnapshotSchedule")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if ss.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	orig := ss.DeepCopy()
+	sched, err := parseCronSchedule(ss.Spec.Schedule)
+	if err != nil {
+		log.Error(err, "invalid schedule", "schedule", ss.Spec.Schedule)
+		if cond := meta.FindStatusCondition(ss.Status.Conditions, topolvmv1.SnapshotScheduleScheduled); cond == nil || cond.Reason != topolvmv1.ReasonInvalidSchedule {
+			r.recorder.Eventf(ss, corev1.EventTypeWarning, EventReasonInvalidSchedule, "invalid schedule: %v", err)
+		}
+		meta.SetStatusCondition(&ss.Status.Conditions, metav1.Condition{
+			Type:    topolvmv1.SnapshotScheduleScheduled,
+			Status:  metav1.ConditionFalse,
+			Reason:  topolvmv1.ReasonInvalidSchedule,
+			Message: err.Error(),
+		})
+		ss.Status.NextScheduleTime = nil
+		return ctrl.Result{}, r.updateStatus(ctx, orig, ss)
+	}
+
+	pruneAfter, err := r.prune(ctx, log, ss, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	last := ss.CreationTimestamp.Time
+	if ss.Status.LastScheduleTime != nil {
+		last = ss.Status.LastScheduleTime.Time
+	}
+	if ss.Spec.Suspend {
+		meta.SetStatusCondition(&ss.Status.Conditions, metav1.Condition{
+			Type:    topolvmv1.SnapshotScheduleScheduled,
+			Status:  metav1.ConditionFalse,
+			Reason:  topolvmv1.ReasonSuspended,
+			Message: "the schedule is suspended",
+		})
+	} else {
+		if scheduled := latestScheduleTime(sched, last, now); !scheduled.IsZero() {
+			if err := r.takeSnapshots(ctx, log, ss, scheduled); err != nil {
+				return ctrl.Result{}, err
+			}
+			ss.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+			last = scheduled
+		}
+		meta.SetStatusCondition(&ss.Status.Conditions, metav1.Condition{
+			Type:    topolvmv1.SnapshotScheduleScheduled,
+			Status:  metav1.ConditionTrue,
+			Reason:  topolvmv1.ReasonScheduled,
+			Message: "snapshots are taken as scheduled",
+		})
+	}
+
+	ss.Status.NextScheduleTime = nil
+	requeueAfter := pruneAfter
+	if next := sched.next(last); !next.IsZero() && !ss.Spec.Suspend {
+		ss.Status.NextScheduleTime = &metav1.Time{Time: next}
+		if d := next.Sub(now); requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+	if err := r.updateStatus(ctx, orig, ss); err != nil {
+		return ctrl.Result{}, err
+	}
+	if requeueAfter > 0 {
+		// Make sure that the scheduled time has passed when requeued.
+		requeueAfter += time.Second
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *SnapshotScheduleReconciler) updateStatus(ctx context.Context, orig, ss *topolvmv1.SnapshotSchedule) error {
+	return r.client.Status().Patch(ctx, ss, client.MergeFrom(orig))
+}
+
+// takeSnapshots creates the VolumeSnapshots of the selected PersistentVolumeClaims for the scheduled time.
+func (r *SnapshotScheduleReconciler) takeSnapshots(ctx context.Context, log logr.Logger, ss *topolvmv1.SnapshotSchedule, scheduled time.Time) error {
+	selector := labels.Everything()
+	if ss.Spec.ClaimSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(ss.Spec.ClaimSelector)
+		if err != nil {
+			return err
+		}
+	}
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.client.List(ctx, &pvcs, client.InNamespace(ss.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		log.Error(err, "unable to list PersistentVolumeClaims")
+		return err
+	}
+
+	limit := int32(defaultThinPoolDataPercentLimit)
+	if ss.Spec.ThinPoolDataPercentLimit != nil {
+		limit = *ss.Spec.ThinPoolDataPercentLimit
+	}
+	var full []string
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if pvc.DeletionTimestamp != nil || pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		dataPercent, ok, err := r.thinPoolDataPercent(ctx, pvc)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// Only the volumes in thin pools can be snapshotted.
+			continue
+		}
+		if dataPercent > float64(limit) {
+			full = append(full, pvc.Name)
+			continue
+		}
+
+		vs := &snapapi.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      scheduledSnapshotName(ss.Name, pvc.Name, scheduled),
+				Namespace: ss.Namespace,
+				Labels: map[string]string{
+					topolvm.GetSnapshotScheduleKey(): ss.Name,
+				},
+			},
+			Spec: snapapi.VolumeSnapshotSpec{
+				Source: snapapi.VolumeSnapshotSource{
+					PersistentVolumeClaimName: &pvc.Name,
+				},
+				VolumeSnapshotClassName: ss.Spec.VolumeSnapshotClassName,
+			},
+		}
+		if err := r.client.Create(ctx, vs); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			log.Error(err, "unable to create VolumeSnapshot", "name", vs.Name, "pvc", pvc.Name)
+			r.recorder.Eventf(ss, corev1.EventTypeWarning, EventReasonSnapshotFailed,
+				"failed to create VolumeSnapshot %s of %s: %v", vs.Name, pvc.Name, err)
+			continue
+		}
+		log.Info("created VolumeSnapshot", "name", vs.Name, "pvc", pvc.Name)
+		r.recorder.Eventf(ss, corev1.EventTypeNormal, EventReasonSnapshotCreated,
+			"created VolumeSnapshot %s of %s", vs.Name, pvc.Name)
+	}
+
+	if len(full) == 0 {
+		meta.SetStatusCondition(&ss.Status.Conditions, metav1.Condition{
+			Type:    topolvmv1.SnapshotSchedulePaused,
+			Status:  metav1.ConditionFalse,
+			Reason:  topolvmv1.ReasonThinPoolHasSpace,
+			Message: "all the thin pools are below the limit",
+		})
+		return nil
+	}
+	msg := fmt.Sprintf("skipped the snapshots of %s because the data usage of the thin pools exceeds %d%%",
+		strings.Join(full, ", "), limit)
+	log.Info("skipped snapshots", "pvcs", full, "limit", limit)
+	r.recorder.Event(ss, corev1.EventTypeWarning, EventReasonSnapshotSkipped, msg)
+	meta.SetStatusCondition(&ss.Status.Conditions, metav1.Condition{
+		Type:    topolvmv1.SnapshotSchedulePaused,
+		Status:  metav1.ConditionTrue,
+		Reason:  topolvmv1.ReasonThinPoolFull,
+		Message: msg,
+	})
+	return nil
+}
+
+// thinPoolDataPercent returns the data usage of the thin pool where the volume of the claim resides.
+// It returns false if the claim is not provisioned by TopoLVM or the volume is not in a thin pool.
+func (r *SnapshotScheduleReconciler) thinPoolDataPercent(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (float64, bool, error) {
+	pv := new(corev1.PersistentVolume)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+		return 0, false, client.IgnoreNotFound(err)
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != topolvm.GetPluginName() {
+		return 0, false, nil
+	}
+	lv, err := findLogicalVolume(ctx, r.client, pv.Spec.CSI.VolumeHandle)
+	if err != nil || lv == nil {
+		return 0, false, err
+	}
+
+	node := new(corev1.Node)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: lv.Spec.NodeName}, node); err != nil {
+		return 0, false, client.IgnoreNotFound(err)
+	}
+	deviceClass := lv.Spec.DeviceClass
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		deviceClass = topolvm.DefaultDeviceClassAnnotationName
+	}
+	v, ok := node.Annotations[topolvm.GetThinPoolDataPercentKeyPrefix()+deviceClass]
+	if !ok {
+		return 0, false, nil
+	}
+	dataPercent, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid data usage of the thin pool on node %s: %w", node.Name, err)
+	}
+	return dataPercent, true, nil
+}
+
+// prune deletes the snapshots taken by the schedule beyond the retention.
+// It returns the duration until the next snapshot expires, or zero if there is none.
+func (r *SnapshotScheduleReconciler) prune(ctx context.Context, log logr.Logger, ss *topolvmv1.SnapshotSchedule, now time.Time) (time.Duration, error) {
+	retention := ss.Spec.Retention
+	if retention.MaxCount == nil && retention.MaxAge == nil {
+		return 0, nil
+	}
+
+	// query directly to API server because VolumeSnapshots are not cached.
+	var vsList snapapi.VolumeSnapshotList
+	if err := r.apiReader.List(ctx, &vsList, client.InNamespace(ss.Namespace),
+		client.MatchingLabels{topolvm.GetSnapshotScheduleKey(): ss.Name}); err != nil {
+		log.Error(err, "unable to list VolumeSnapshots")
+		return 0, err
+	}
+
+	expired, nextExpiry := expiredSnapshots(vsList.Items, retention, now)
+	for _, vs := range expired {
+		if err := r.client.Delete(ctx, vs); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to delete VolumeSnapshot", "name", vs.Name)
+			return 0, err
+		}
+		log.Info("deleted VolumeSnapshot", "name", vs.Name)
+		r.recorder.Eventf(ss, corev1.EventTypeNormal, EventReasonSnapshotPruned, "deleted VolumeSnapshot %s", vs.Name)
+	}
+	if nextExpiry.IsZero() {
+		return 0, nil
+	}
+	return nextExpiry.Sub(now), nil
+}
+
+// expiredSnapshots returns the snapshots beyond the retention, and the time when the next snapshot expires.
+// The retention is applied to the snapshots of each PersistentVolumeClaim separately.
+// All the snapshots are counted for MaxCount, but the newest one ready to use is never expired
+// so that failing or pending snapshots do not push out the last good one.
+// The failed snapshots older than a ready one are expired at once because they will never be used.
+func expiredSnapshots(snapshots []snapapi.VolumeSnapshot, retention topolvmv1.SnapshotRetention, now time.Time) ([]*snapapi.VolumeSnapshot, time.Time) {
+	byClaim := make(map[string][]*snapapi.VolumeSnapshot)
+	for i := range snapshots {
+		vs := &snapshots[i]
+		if vs.DeletionTimestamp != nil || vs.Spec.Source.PersistentVolumeClaimName == nil {
+			continue
+		}
+		claim := *vs.Spec.Source.PersistentVolumeClaimName
+		byClaim[claim] = append(byClaim[claim], vs)
+	}
+
+	var expired []*snapapi.VolumeSnapshot
+	var nextExpiry time.Time
+	for _, list := range byClaim {
+		// newest first
+		sort.Slice(list, func(i, j int) bool {
+			return list[j].CreationTimestamp.Before(&list[i].CreationTimestamp)
+		})
+		readyFound := false
+		for i, vs := range list {
+			if !readyFound && isSnapshotReady(vs) {
+				readyFound = true
+				continue
+			}
+			if readyFound && isSnapshotFailed(vs) {
+				expired = append(expired, vs)
+				continue
+			}
+			if retention.MaxCount != nil && i >= int(*retention.MaxCount) {
+				expired = append(expired, vs)
+				continue
+			}
+			if retention.MaxAge == nil {
+				continue
+			}
+			expiry := vs.CreationTimestamp.Add(retention.MaxAge.Duration)
+			if !expiry.After(now) {
+				expired = append(expired, vs)
+				continue
+			}
+			if nextExpiry.IsZero() || expiry.Before(nextExpiry) {
+				nextExpiry = expiry
+			}
+		}
+	}
+	return expired, nextExpiry
+}
+
+func isSnapshotReady(vs *snapapi.VolumeSnapshot) bool {
+	return vs.Status != nil && vs.Status.ReadyToUse != nil && *vs.Status.ReadyToUse
+}
+
+func isSnapshotFailed(vs *snapapi.VolumeSnapshot) bool {
+	return !isSnapshotReady(vs) && vs.Status != nil && vs.Status.Error != nil
+}
+
+// latestScheduleTime returns the latest scheduled time after last and not after now, or zero time if there is none.
+// The runs missed while topolvm-controller was stopped are merged into one.
+func latestScheduleTime(sched *cronSchedule, last, now time.Time) time.Time {
+	var latest time.Time
+	for t := sched.next(last); !t.IsZero() && !t.After(now); t = sched.next(t) {
+		latest = t
+	}
+	return latest
+}
+
+// scheduledSnapshotName returns the name of the snapshot of the claim taken at the scheduled time.
+// If the name is too long, the names of the schedule and the claim are truncated and followed by their hash.
+func scheduledSnapshotName(schedule, claim string, scheduled time.Time) string {
+	prefix := schedule + "-" + claim
+	suffix := "-" + scheduled.UTC().Format(snapshotNameTimeFormat)
+	if len(prefix)+len(suffix) <= validation.DNS1123SubdomainMaxLength {
+		return prefix + suffix
+	}
+	sum := sha256.Sum256([]byte(prefix))
+	suffix = "-" + hex.EncodeToString(sum[:])[:snapshotNameHashLength] + suffix
+	return strings.TrimRight(prefix[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.") + suffix
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topolvmv1.SnapshotSchedule{}).
+		Complete(r)
+}
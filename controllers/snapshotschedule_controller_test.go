@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var _ = Describe("SnapshotSchedule controller", func() {
+	base := time.Date(2024, time.January, 31, 10, 30, 15, 0, time.UTC) // Wednesday
+
+	It("should compute the next scheduled time", func() {
+		cases := []struct {
+			expr string
+			next time.Time
+		}{
+			{"* * * * *", time.Date(2024, time.January, 31, 10, 31, 0, 0, time.UTC)},
+			{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 45, 0, 0, time.UTC)},
+			{"0 3 * * *", time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC)},
+			{"@hourly", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+			{"@weekly", time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+			{"0 0 * * 7", time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+			{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+			{"30 10 1,15 * 5", time.Date(2024, time.February, 1, 10, 30, 0, 0, time.UTC)},
+			{"0 9-17/4 * * 1-5", time.Date(2024, time.January, 31, 13, 0, 0, 0, time.UTC)},
+			{"0 0 1 */3 *", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		for _, c := range cases {
+			sched, err := parseCronSchedule(c.expr)
+			Expect(err).ShouldNot(HaveOccurred(), c.expr)
+			Expect(sched.next(base)).To(Equal(c.next), c.expr)
+		}
+
+		sched, err := parseCronSchedule("0 0 30 2 *")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sched.next(base).IsZero()).To(BeTrue())
+	})
+
+	It("should reject invalid schedules", func() {
+		for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+			"*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 1h"} {
+			_, err := parseCronSchedule(expr)
+			Expect(err).Should(HaveOccurred(), expr)
+		}
+	})
+
+	It("should merge the missed runs into the latest one", func() {
+		sched, err := parseCronSchedule("0 * * * *")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(latestScheduleTime(sched, base.Add(-5*time.Hour), base)).To(Equal(time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)))
+		Expect(latestScheduleTime(sched, time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC), base).IsZero()).To(BeTrue())
+	})
+
+	It("should select the snapshots beyond the retention", func() {
+		snapshot := func(name, claim string, age time.Duration) snapapi.VolumeSnapshot {
+			ready := true
+			return snapapi.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(base.Add(-age))},
+				Spec: snapapi.VolumeSnapshotSpec{
+					Source: snapapi.VolumeSnapshotSource{PersistentVolumeClaimName: &claim},
+				},
+				Status: &snapapi.VolumeSnapshotStatus{ReadyToUse: &ready},
+			}
+		}
+		snapshots := []snapapi.VolumeSnapshot{
+			snapshot("a-3", "a", 3*time.Hour),
+			snapshot("a-1", "a", 1*time.Hour),
+			snapshot("a-2", "a", 2*time.Hour),
+			snapshot("a-4", "a", 4*time.Hour),
+			snapshot("b-5", "b", 5*time.Hour),
+			snapshot("b-1", "b", 1*time.Hour),
+		}
+		names := func(list []*snapapi.VolumeSnapshot) []string {
+			var ret []string
+			for _, vs := range list {
+				ret = append(ret, vs.Name)
+			}
+			return ret
+		}
+
+		maxCount := int32(2)
+		expired, next := expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxCount: &maxCount}, base)
+		Expect(names(expired)).To(ConsistOf("a-3", "a-4"))
+		Expect(next.IsZero()).To(BeTrue())
+
+		maxAge := &metav1.Duration{Duration: 150 * time.Minute}
+		expired, next = expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxAge: maxAge}, base)
+		Expect(names(expired)).To(ConsistOf("a-3", "a-4", "b-5"))
+		Expect(next).To(Equal(base.Add(-2 * time.Hour).Add(150 * time.Minute)))
+
+		expired, _ = expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxCount: &maxCount, MaxAge: maxAge}, base)
+		Expect(names(expired)).To(ConsistOf("a-3", "a-4", "b-5"))
+
+		expired, next = expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{}, base)
+		Expect(expired).To(BeEmpty())
+		Expect(next.IsZero()).To(BeTrue())
+
+		// the newest ready snapshot is kept even if it is too old.
+		maxAge = &metav1.Duration{Duration: 30 * time.Minute}
+		expired, _ = expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxAge: maxAge}, base)
+		Expect(names(expired)).To(ConsistOf("a-2", "a-3", "a-4", "b-5"))
+	})
+
+	It("should keep the newest ready snapshot and expire the failed ones", func() {
+		snapshot := func(name string, age time.Duration, ready *bool, failed bool) snapapi.VolumeSnapshot {
+			claim := "a"
+			vs := snapapi.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(base.Add(-age))},
+				Spec: snapapi.VolumeSnapshotSpec{
+					Source: snapapi.VolumeSnapshotSource{PersistentVolumeClaimName: &claim},
+				},
+			}
+			if ready != nil {
+				vs.Status = &snapapi.VolumeSnapshotStatus{ReadyToUse: ready}
+			}
+			if failed {
+				msg := "failed"
+				vs.Status.Error = &snapapi.VolumeSnapshotError{Message: &msg}
+			}
+			return vs
+		}
+		names := func(list []*snapapi.VolumeSnapshot) []string {
+			var ret []string
+			for _, vs := range list {
+				ret = append(ret, vs.Name)
+			}
+			return ret
+		}
+		ready, notReady := true, false
+		snapshots := []snapapi.VolumeSnapshot{
+			snapshot("failed-1", 1*time.Hour, &notReady, true),
+			snapshot("pending-2", 2*time.Hour, nil, false),
+			snapshot("failed-3", 3*time.Hour, &notReady, true),
+			snapshot("ready-4", 4*time.Hour, &ready, false),
+			snapshot("failed-5", 5*time.Hour, &notReady, true),
+			snapshot("ready-6", 6*time.Hour, &ready, false),
+		}
+
+		// the snapshots not ready to use are counted, but the newest ready one is kept.
+		maxCount := int32(1)
+		expired, _ := expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxCount: &maxCount}, base)
+		Expect(names(expired)).To(ConsistOf("pending-2", "failed-3", "failed-5", "ready-6"))
+
+		// the failed snapshots older than a ready one are expired within the count.
+		maxCount = int32(10)
+		expired, _ = expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxCount: &maxCount}, base)
+		Expect(names(expired)).To(ConsistOf("failed-5"))
+
+		maxAge := &metav1.Duration{Duration: 150 * time.Minute}
+		expired, _ = expiredSnapshots(snapshots, topolvmv1.SnapshotRetention{MaxAge: maxAge}, base)
+		Expect(names(expired)).To(ConsistOf("failed-3", "failed-5", "ready-6"))
+
+		// the failed snapshots are kept until a newer snapshot gets ready.
+		expired, _ = expiredSnapshots(snapshots[:3], topolvmv1.SnapshotRetention{MaxCount: &maxCount}, base)
+		Expect(expired).To(BeEmpty())
+	})
+
+	It("should keep the names of the snapshots short enough", func() {
+		scheduled := time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)
+		Expect(scheduledSnapshotName("daily", "data", scheduled)).To(Equal("daily-data-20240131-1030"))
+
+		schedule := strings.Repeat("s", 200)
+		name := scheduledSnapshotName(schedule, strings.Repeat("c", 200), scheduled)
+		Expect(len(name)).To(Equal(validation.DNS1123SubdomainMaxLength))
+		Expect(name).To(HavePrefix(schedule))
+		Expect(name).To(HaveSuffix("-20240131-1030"))
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(scheduledSnapshotName(schedule, strings.Repeat("c", 201), scheduled)).NotTo(Equal(name))
+		Expect(scheduledSnapshotName(schedule, strings.Repeat("c", 200), scheduled.Add(time.Hour))).NotTo(Equal(name))
+
+		// the truncated name does not end with a hyphen before the hash.
+		name = scheduledSnapshotName(strings.Repeat("s", 229), "claim", scheduled)
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(name).NotTo(ContainSubstring("--"))
+	})
+})
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// copyPollInterval is the interval to check the progress of copying a volume from another node.
+const copyPollInterval = 10 * time.Second
+
+// errCopyInProgress is returned when the contents of a volume are being copied from another node.
+var errCopyInProgress = errors.New("copying volume from another node")
+
+// copyJob is a background job to copy the contents of a volume from another node.
+type copyJob struct {
+	total  atomic.Uint64
+	copied atomic.Uint64
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// volume and err are the result of the job, which are valid after done is closed.
+	volume *proto.LogicalVolume
+	err    error
+}
+
+func (j *copyJob) finished() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// copyJobs holds the copy jobs by the UID of LogicalVolume.
+type copyJobs struct {
+	mu   sync.Mutex
+	jobs map[types.UID]*copyJob
+}
+
+func (c *copyJobs) get(uid types.UID) *copyJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.jobs[uid]
+}
+
+// start runs fn in the background as the copy job for uid.
+func (c *copyJobs) start(uid types.UID, fn func(ctx context.Context, job *copyJob) (*proto.LogicalVolume, error)) *copyJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &copyJob{cancel: cancel, done: make(chan struct{})}
+
+	c.mu.Lock()
+	if c.jobs == nil {
+		c.jobs = make(map[types.UID]*copyJob)
+	}
+	c.jobs[uid] = job
+	c.mu.Unlock()
+
+	go func() {
+		defer close(job.done)
+		defer cancel()
+		job.volume, job.err = fn(ctx, job)
+	}()
+	return job
+}
+
+// remove cancels the copy job for uid if it is running, and forgets it.
+func (c *copyJobs) remove(uid types.UID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if job, ok := c.jobs[uid]; ok {
+		job.cancel()
+		delete(c.jobs, uid)
+	}
+}
+
+// copyVolume writes the contents exported by remote into the volume that local imports as requested by importReq.
+// importReq is the first request of ImportLV, which creates a volume or updates an existing one.
+// The progress is recorded in job.
+// If copyVolume fails or ctx is canceled, lvmd removes the volume being created.
+func copyVolume(ctx context.Context, remote, local proto.LVServiceClient, exportReq *proto.ExportLVRequest, importReq *proto.ImportLVRequest, job *copyJob) (*proto.LogicalVolume, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	src, err := remote.ExportLV(ctx, exportReq)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := src.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if volume := importReq.GetVolume(); !importReq.GetUpdate() && resp.GetSizeBytes() > volume.GetSizeBytes() {
+		// LVM rounds the size of the source up to its extent size, so the volume is created
+		// as large as the source. The requested size is not smaller than that of the source.
+		importReq = &proto.ImportLVRequest{
+			Volume: &proto.CreateLVRequest{
+				Name:                volume.GetName(),
+				SizeBytes:           resp.GetSizeBytes(),
+				Tags:                volume.GetTags(),
+				DeviceClass:         volume.GetDeviceClass(),
+				LvcreateOptionClass: volume.GetLvcreateOptionClass(),
+			},
+		}
+	}
+	job.total.Store(resp.GetSizeBytes())
+
+	dst, err := local.ImportLV(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Send returns io.EOF when lvmd aborted the import, and CloseAndRecv returns the reason.
+	err = dst.Send(importReq)
+	for err == nil {
+		resp, err = src.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		chunk := resp.GetChunk()
+		if chunk == nil {
+			return nil, status.Error(codes.Internal, "exported data has no chunk")
+		}
+		err = dst.Send(&proto.ImportLVRequest{Chunk: chunk})
+		job.copied.Store(chunk.GetOffset() + chunk.GetLength())
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	imported, err := dst.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	job.copied.Store(job.total.Load())
+	return imported.GetVolume(), nil
+}
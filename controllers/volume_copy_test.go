@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeExportClient struct {
+	grpc.ClientStream
+	responses []*proto.ExportLVResponse
+}
+
+func (c *fakeExportClient) Recv() (*proto.ExportLVResponse, error) {
+	if len(c.responses) == 0 {
+		return nil, io.EOF
+	}
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+type fakeImportClient struct {
+	grpc.ClientStream
+	requests []*proto.ImportLVRequest
+	// abortErr makes Send fail after the first request, and is returned by CloseAndRecv.
+	abortErr error
+}
+
+func (c *fakeImportClient) Send(req *proto.ImportLVRequest) error {
+	if c.abortErr != nil && len(c.requests) > 0 {
+		return io.EOF
+	}
+	c.requests = append(c.requests, req)
+	return nil
+}
+
+func (c *fakeImportClient) CloseAndRecv() (*proto.ImportLVResponse, error) {
+	if c.abortErr != nil {
+		return nil, c.abortErr
+	}
+	return &proto.ImportLVResponse{
+		Volume: &proto.LogicalVolume{Name: c.requests[0].GetVolume().GetName()},
+	}, nil
+}
+
+type fakeTransferLVService struct {
+	MockLVServiceClient
+	export *fakeExportClient
+	imp    *fakeImportClient
+}
+
+func (s fakeTransferLVService) ExportLV(ctx context.Context, in *proto.ExportLVRequest, opts ...grpc.CallOption) (proto.LVService_ExportLVClient, error) {
+	return s.export, nil
+}
+
+func (s fakeTransferLVService) ImportLV(ctx context.Context, opts ...grpc.CallOption) (proto.LVService_ImportLVClient, error) {
+	return s.imp, nil
+}
+
+var _ = Describe("copyVolume", func() {
+	exportReq := &proto.ExportLVRequest{Name: "source", DeviceClass: "ssd"}
+	importReq := &proto.ImportLVRequest{
+		Volume: &proto.CreateLVRequest{Name: "target", DeviceClass: "ssd", SizeBytes: 1 << 30, Tags: []string{"tag"}},
+	}
+
+	exportResponses := func(size uint64) []*proto.ExportLVResponse {
+		return []*proto.ExportLVResponse{
+			{SizeBytes: size},
+			{Chunk: &proto.LVDataChunk{Offset: 0, Length: 4096}},
+			{Chunk: &proto.LVDataChunk{Offset: 1 << 20, Length: 4096}},
+		}
+	}
+
+	It("should relay the exported chunks to the import", func() {
+		remote := fakeTransferLVService{export: &fakeExportClient{responses: exportResponses(1 << 30)}}
+		local := fakeTransferLVService{imp: &fakeImportClient{}}
+		job := &copyJob{}
+
+		volume, err := copyVolume(context.Background(), remote, local, exportReq, importReq, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volume.GetName()).To(Equal("target"))
+
+		requests := local.imp.requests
+		Expect(requests).To(HaveLen(3))
+		Expect(requests[0]).To(Equal(importReq))
+		Expect(requests[0].GetChunk()).To(BeNil())
+		Expect(requests[1].GetChunk().GetOffset()).To(Equal(uint64(0)))
+		Expect(requests[2].GetChunk().GetOffset()).To(Equal(uint64(1 << 20)))
+		Expect(job.total.Load()).To(Equal(uint64(1 << 30)))
+		Expect(job.copied.Load()).To(Equal(uint64(1 << 30)))
+	})
+
+	It("should return the reason why the import was aborted", func() {
+		remote := fakeTransferLVService{export: &fakeExportClient{responses: exportResponses(1 << 30)}}
+		local := fakeTransferLVService{imp: &fakeImportClient{abortErr: status.Error(codes.ResourceExhausted, "no enough space")}}
+
+		_, err := copyVolume(context.Background(), remote, local, exportReq, importReq, &copyJob{})
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+	})
+
+	It("should create the volume as large as the source", func() {
+		remote := fakeTransferLVService{export: &fakeExportClient{responses: exportResponses(1<<30 + 4<<20)}}
+		local := fakeTransferLVService{imp: &fakeImportClient{}}
+
+		_, err := copyVolume(context.Background(), remote, local, exportReq, importReq, &copyJob{})
+		Expect(err).NotTo(HaveOccurred())
+		requests := local.imp.requests
+		Expect(requests).NotTo(BeEmpty())
+		Expect(requests[0].GetVolume().GetName()).To(Equal("target"))
+		Expect(requests[0].GetVolume().GetSizeBytes()).To(Equal(uint64(1<<30 + 4<<20)))
+		Expect(requests[0].GetVolume().GetTags()).To(Equal([]string{"tag"}))
+		Expect(importReq.GetVolume().GetSizeBytes()).To(Equal(uint64(1 << 30)))
+	})
+})
@@ -0,0 +1,629 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/topolvm/topolvm"
+	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Event reasons recorded on LogicalVolume and PersistentVolumeClaim by VolumeMigrationReconciler.
+const (
+	EventReasonMigrating         = "Migrating"
+	EventReasonMigrated          = "Migrated"
+	EventReasonMigrationFailed   = "MigrationFailed"
+	EventReasonMigrationCanceled = "MigrationCanceled"
+)
+
+// migrationPollInterval is the interval to check the progress of a migration.
+const migrationPollInterval = 10 * time.Second
+
+// VolumeMigrationReconciler migrates the volume of a PersistentVolumeClaim annotated with
+// topolvm.io/migrate-to to the node specified by the annotation.
+//
+// The migration takes a snapshot of the source volume, and copies it to a new LogicalVolume on the target node
+// while the volume is in use. After the pods using the claim stop, the changes made after the snapshot are
+// applied to the copy, and the claim is re-created to be bound to a new PersistentVolume of the copy.
+// The progress is recorded in the Migrating condition of the source LogicalVolume.
+type VolumeMigrationReconciler struct {
+	client    client.Client
+	apiReader client.Reader
+	recorder  record.EventRecorder
+}
+
+// NewVolumeMigrationReconciler returns VolumeMigrationReconciler.
+func NewVolumeMigrationReconciler(client client.Client, apiReader client.Reader, recorder record.EventRecorder) *VolumeMigrationReconciler {
+	return &VolumeMigrationReconciler{
+		client:    client,
+		apiReader: apiReader,
+		recorder:  recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile advances the migration of the LogicalVolume.
+func (r *VolumeMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+	lv := new(topolvmv1.LogicalVolume)
+	if err := r.client.Get(ctx, req.NamespacedName, lv); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch LogicalVolume")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if lv.DeletionTimestamp != nil || lv.Status.VolumeID == "" {
+		return ctrl.Result{}, nil
+	}
+
+	target, migrating := lv.Annotations[topolvm.GetMigrateToKey()]
+	if _, ok := lv.Annotations[topolvm.GetMigrationClaimKey()]; ok {
+		// The claim may have been deleted, so the migration is completed without it.
+		return r.switchVolume(ctx, log, lv, target)
+	}
+
+	pv, err := r.findPersistentVolume(ctx, lv.Status.VolumeID)
+	if err != nil {
+		log.Error(err, "failed to find PersistentVolume", "name", lv.Name)
+		return ctrl.Result{}, err
+	}
+	var pvc *corev1.PersistentVolumeClaim
+	if pv != nil && pv.Spec.ClaimRef != nil {
+		pvc = new(corev1.PersistentVolumeClaim)
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: pv.Spec.ClaimRef.Namespace, Name: pv.Spec.ClaimRef.Name}, pvc)
+		if apierrors.IsNotFound(err) || (err == nil && pvc.Spec.VolumeName != pv.Name) {
+			pvc = nil
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var requested string
+	if pvc != nil && pvc.DeletionTimestamp == nil {
+		requested = pvc.Annotations[topolvm.GetMigrateToKey()]
+	}
+	switch {
+	case !migrating && requested == "":
+		return ctrl.Result{}, nil
+	case !migrating:
+		return r.start(ctx, log, lv, pv, pvc, requested)
+	case requested != target:
+		if err := r.cleanup(ctx, log, lv); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("canceled migration", "name", lv.Name, "node", target)
+		recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeNormal, EventReasonMigrationCanceled, "canceled migration to node %s", target)
+		setStatusCondition(lv, topolvmv1.LogicalVolumeMigrating, metav1.ConditionFalse, topolvmv1.ReasonMigrationCanceled,
+			fmt.Sprintf("migration to node %s was canceled", target))
+		lv.Status.Migration = nil
+		if err := r.client.Status().Update(ctx, lv); err != nil {
+			return ctrl.Result{}, err
+		}
+		// A migration to another node may have been requested.
+		return ctrl.Result{Requeue: requested != ""}, nil
+	}
+	return r.migrate(ctx, log, lv, pvc, target)
+}
+
+// start validates the migration requested on pvc, and records the target node on lv.
+func (r *VolumeMigrationReconciler) start(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim, target string) (ctrl.Result, error) {
+	if target == lv.Spec.NodeName {
+		return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("the volume is already on node %s", target))
+	}
+	node := new(corev1.Node)
+	err := r.client.Get(ctx, types.NamespacedName{Name: target}, node)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("node %s is not found", target))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pv.Spec.CSI == nil || pv.Status.Phase != corev1.VolumeBound {
+		return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("PersistentVolume %s is not bound", pv.Name))
+	}
+
+	if lv.Annotations == nil {
+		lv.Annotations = make(map[string]string)
+	}
+	lv.Annotations[topolvm.GetMigrateToKey()] = target
+	if err := r.client.Update(ctx, lv); err != nil {
+		log.Error(err, "failed to annotate LogicalVolume", "name", lv.Name)
+		return ctrl.Result{}, err
+	}
+	log.Info("started migration", "name", lv.Name, "pvc", pvc.Namespace+"/"+pvc.Name, "node", target)
+	r.recorder.Eventf(pvc, corev1.EventTypeNormal, EventReasonMigrating, "migrating the volume from node %s to node %s", lv.Spec.NodeName, target)
+	return r.setPhase(ctx, log, lv, topolvmv1.ReasonSnapshotting, "taking a snapshot of the volume")
+}
+
+// migrate copies lv to the target node until the copy is synced while the claim is not used.
+func (r *VolumeMigrationReconciler) migrate(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, pvc *corev1.PersistentVolumeClaim, target string) (ctrl.Result, error) {
+	snapshot := new(topolvmv1.LogicalVolume)
+	if lv.Status.Migration != nil && lv.Status.Migration.SnapshotVolumeID != "" {
+		// The copy has been made from the recorded snapshot, so another snapshot cannot replace it.
+		err := r.client.Get(ctx, types.NamespacedName{Name: migrationSnapshotName(lv)}, snapshot)
+		if apierrors.IsNotFound(err) || (err == nil && snapshot.Status.VolumeID != lv.Status.Migration.SnapshotVolumeID) {
+			return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("snapshot %s of the volume was lost", lv.Status.Migration.SnapshotVolumeID))
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		var err error
+		snapshot, err = r.ensureVolume(ctx, log, &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: migrationSnapshotName(lv)},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:        migrationSnapshotName(lv),
+				NodeName:    lv.Spec.NodeName,
+				DeviceClass: lv.Spec.DeviceClass,
+				Size:        lv.Spec.Size,
+				Source:      lv.Name,
+				AccessType:  "ro",
+			},
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if msg := creationFailure(snapshot); msg != "" {
+			return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("failed to take a snapshot: %s", msg))
+		}
+		if snapshot.Status.VolumeID == "" {
+			return r.setPhase(ctx, log, lv, topolvmv1.ReasonSnapshotting, "taking a snapshot of the volume")
+		}
+
+		// The snapshot is recorded before the copy starts.
+		if lv.Status.Migration == nil {
+			lv.Status.Migration = &topolvmv1.MigrationStatus{Step: topolvmv1.ReasonSnapshotting}
+		}
+		lv.Status.Migration.SnapshotVolumeID = snapshot.Status.VolumeID
+		if err := r.client.Status().Update(ctx, lv); err != nil {
+			log.Error(err, "failed to update status", "name", lv.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	spec := *lv.Spec.DeepCopy()
+	spec.Name = migrationTargetName(lv)
+	spec.NodeName = target
+	spec.Source = snapshot.Name
+	spec.AccessType = "rw"
+	spec.PVName = migrationTargetName(lv)
+	copied, err := r.ensureVolume(ctx, log, &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: migrationTargetName(lv)},
+		Spec:       spec,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if msg := creationFailure(copied); msg != "" {
+		return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("failed to copy the volume: %s", msg))
+	}
+	if copied.Status.VolumeID == "" {
+		msg := fmt.Sprintf("copying the volume to node %s", target)
+		if created := meta.FindStatusCondition(copied.Status.Conditions, topolvmv1.LogicalVolumeCreated); created != nil && created.Reason == topolvmv1.ReasonCopying {
+			msg = created.Message
+		}
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonCopying, msg)
+	}
+
+	consumers, err := r.findConsumers(ctx, pvc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(consumers) > 0 {
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonWaitingForConsumer,
+			fmt.Sprintf("waiting for pods to stop using the volume: %s", strings.Join(consumers, ", ")))
+	}
+
+	if _, ok := copied.Annotations[topolvm.GetSyncRequestedKey()]; !ok {
+		if copied.Annotations == nil {
+			copied.Annotations = make(map[string]string)
+		}
+		copied.Annotations[topolvm.GetSyncRequestedKey()] = "true"
+		if err := r.client.Update(ctx, copied); err != nil {
+			log.Error(err, "failed to request sync", "name", copied.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	synced := meta.FindStatusCondition(copied.Status.Conditions, topolvmv1.LogicalVolumeSynced)
+	switch {
+	case synced == nil:
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonSyncing, "applying the changes made after the snapshot")
+	case synced.Reason == topolvmv1.ReasonSyncFailed:
+		return ctrl.Result{}, r.fail(ctx, log, lv, pvc, fmt.Sprintf("failed to apply the changes made after the snapshot: %s", synced.Message))
+	case synced.Status != metav1.ConditionTrue:
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonSyncing, synced.Message)
+	}
+
+	// From now on, the claim is re-created from the template, so the migration cannot be canceled.
+	claim, err := json.Marshal(newMigratedClaim(pvc, copied.Name, target))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	lv.Annotations[topolvm.GetMigrationClaimKey()] = string(claim)
+	if err := r.client.Update(ctx, lv); err != nil {
+		log.Error(err, "failed to annotate LogicalVolume", "name", lv.Name)
+		return ctrl.Result{}, err
+	}
+	return r.setPhase(ctx, log, lv, topolvmv1.ReasonSwitching, fmt.Sprintf("switching PersistentVolumeClaim to PersistentVolume %s", copied.Name))
+}
+
+// switchVolume re-creates the claim to be bound to a new PersistentVolume of the copy, and removes the source volume.
+func (r *VolumeMigrationReconciler) switchVolume(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, target string) (ctrl.Result, error) {
+	claim := new(corev1.PersistentVolumeClaim)
+	if err := json.Unmarshal([]byte(lv.Annotations[topolvm.GetMigrationClaimKey()]), claim); err != nil {
+		log.Error(err, "invalid claim template", "name", lv.Name)
+		return ctrl.Result{}, nil
+	}
+	copied := new(topolvmv1.LogicalVolume)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: migrationTargetName(lv)}, copied); err != nil {
+		return ctrl.Result{}, err
+	}
+	pv, err := r.findPersistentVolume(ctx, lv.Status.VolumeID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pvc := new(corev1.PersistentVolumeClaim)
+	err = r.client.Get(ctx, types.NamespacedName{Namespace: claim.Namespace, Name: claim.Name}, pvc)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := r.client.Create(ctx, claim); err != nil {
+			log.Error(err, "failed to re-create PersistentVolumeClaim", "name", lv.Name, "pvc", claim.Namespace+"/"+claim.Name)
+			return ctrl.Result{}, err
+		}
+		log.Info("re-created PersistentVolumeClaim", "name", lv.Name, "pvc", claim.Namespace+"/"+claim.Name, "pv", claim.Spec.VolumeName)
+		return ctrl.Result{RequeueAfter: migrationPollInterval}, nil
+	case err != nil:
+		return ctrl.Result{}, err
+	case pvc.DeletionTimestamp != nil:
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonSwitching, fmt.Sprintf("waiting for PersistentVolumeClaim %s to be deleted", pvc.Name))
+	case pvc.Spec.VolumeName != claim.Spec.VolumeName:
+		return r.replaceClaim(ctx, log, lv, copied, pv, pvc)
+	case pvc.Status.Phase != corev1.ClaimBound:
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonSwitching, fmt.Sprintf("waiting for PersistentVolumeClaim %s to be bound", pvc.Name))
+	}
+
+	// The source volume is removed last because it holds the state of the migration.
+	snapshot := &topolvmv1.LogicalVolume{ObjectMeta: metav1.ObjectMeta{Name: migrationSnapshotName(lv)}}
+	if err := r.client.Delete(ctx, snapshot); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "failed to delete snapshot", "name", snapshot.Name)
+		return ctrl.Result{}, err
+	}
+	if pv != nil {
+		if err := r.client.Delete(ctx, pv); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to delete PersistentVolume", "name", pv.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.client.Delete(ctx, lv); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "failed to delete LogicalVolume", "name", lv.Name)
+		return ctrl.Result{}, err
+	}
+	log.Info("completed migration", "name", lv.Name, "pvc", pvc.Namespace+"/"+pvc.Name, "node", target, "target", copied.Name)
+	recordLogicalVolumeEvent(r.recorder, copied, corev1.EventTypeNormal, EventReasonMigrated, "migrated from LogicalVolume %s on node %s", lv.Name, lv.Spec.NodeName)
+	r.recorder.Eventf(pvc, corev1.EventTypeNormal, EventReasonMigrated, "migrated the volume from node %s to node %s", lv.Spec.NodeName, target)
+	return ctrl.Result{}, nil
+}
+
+// replaceClaim creates a new PersistentVolume of the copy, and deletes the claim bound to the source volume.
+// If pods started to use the claim again, the migration goes back to wait for them to stop.
+func (r *VolumeMigrationReconciler) replaceClaim(ctx context.Context, log logr.Logger, lv, copied *topolvmv1.LogicalVolume, pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim) (ctrl.Result, error) {
+	consumers, err := r.findConsumers(ctx, pvc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(consumers) > 0 {
+		// The changes made by the pods must be applied again.
+		meta.RemoveStatusCondition(&copied.Status.Conditions, topolvmv1.LogicalVolumeSynced)
+		if err := r.client.Status().Update(ctx, copied); err != nil {
+			return ctrl.Result{}, err
+		}
+		delete(lv.Annotations, topolvm.GetMigrationClaimKey())
+		if err := r.client.Update(ctx, lv); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.setPhase(ctx, log, lv, topolvmv1.ReasonWaitingForConsumer,
+			fmt.Sprintf("waiting for pods to stop using the volume: %s", strings.Join(consumers, ", ")))
+	}
+	if pv == nil {
+		return ctrl.Result{}, r.fail(ctx, log, lv, pvc, "PersistentVolume of the volume is not found")
+	}
+
+	newPV := newMigratedPV(pv, copied)
+	if err := r.client.Create(ctx, newPV); err != nil && !apierrors.IsAlreadyExists(err) {
+		log.Error(err, "failed to create PersistentVolume", "name", newPV.Name)
+		return ctrl.Result{}, err
+	}
+	// The source volume must be retained until the claim is bound to the new PersistentVolume.
+	if pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		if err := r.client.Update(ctx, pv); err != nil {
+			log.Error(err, "failed to retain PersistentVolume", "name", pv.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.client.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "failed to delete PersistentVolumeClaim", "name", lv.Name, "pvc", pvc.Namespace+"/"+pvc.Name)
+		return ctrl.Result{}, err
+	}
+	log.Info("deleted PersistentVolumeClaim to switch PersistentVolume", "name", lv.Name, "pvc", pvc.Namespace+"/"+pvc.Name, "pv", newPV.Name)
+	return r.setPhase(ctx, log, lv, topolvmv1.ReasonSwitching, fmt.Sprintf("switching PersistentVolumeClaim to PersistentVolume %s", newPV.Name))
+}
+
+// fail aborts the migration of lv, and removes the request from pvc so that it is not retried.
+func (r *VolumeMigrationReconciler) fail(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, pvc *corev1.PersistentVolumeClaim, message string) error {
+	if err := r.cleanup(ctx, log, lv); err != nil {
+		return err
+	}
+	if pvc != nil {
+		if _, ok := pvc.Annotations[topolvm.GetMigrateToKey()]; ok {
+			delete(pvc.Annotations, topolvm.GetMigrateToKey())
+			if err := r.client.Update(ctx, pvc); err != nil {
+				log.Error(err, "failed to remove annotation", "pvc", pvc.Namespace+"/"+pvc.Name)
+				return err
+			}
+		}
+		r.recorder.Eventf(pvc, corev1.EventTypeWarning, EventReasonMigrationFailed, "failed to migrate the volume: %s", message)
+	}
+
+	log.Info("migration failed", "name", lv.Name, "message", message)
+	recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeWarning, EventReasonMigrationFailed, "failed to migrate the volume: %s", message)
+	setStatusCondition(lv, topolvmv1.LogicalVolumeMigrating, metav1.ConditionFalse, topolvmv1.ReasonMigrationFailed, message)
+	lv.Status.Migration = nil
+	return r.client.Status().Update(ctx, lv)
+}
+
+// cleanup deletes the snapshot and the copy of lv, and removes the target node from lv.
+func (r *VolumeMigrationReconciler) cleanup(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+	for _, name := range []string{migrationTargetName(lv), migrationSnapshotName(lv)} {
+		obj := &topolvmv1.LogicalVolume{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if err := r.client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to delete LogicalVolume", "name", name)
+			return err
+		}
+	}
+	if _, ok := lv.Annotations[topolvm.GetMigrateToKey()]; !ok {
+		return nil
+	}
+	delete(lv.Annotations, topolvm.GetMigrateToKey())
+	if err := r.client.Update(ctx, lv); err != nil {
+		log.Error(err, "failed to remove annotation", "name", lv.Name)
+		return err
+	}
+	return nil
+}
+
+// setPhase records the phase of the migration in the Migrating condition and status.migration,
+// and requeues lv to check the progress.
+func (r *VolumeMigrationReconciler) setPhase(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume, phase, message string) (ctrl.Result, error) {
+	result := ctrl.Result{RequeueAfter: migrationPollInterval}
+	current := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeMigrating)
+	recorded := lv.Status.Migration != nil && lv.Status.Migration.Step == phase
+	if recorded && current != nil && current.Status == metav1.ConditionTrue && current.Reason == phase && current.Message == message {
+		return result, nil
+	}
+	if lv.Status.Migration == nil {
+		lv.Status.Migration = &topolvmv1.MigrationStatus{}
+	}
+	lv.Status.Migration.Step = phase
+	if current == nil || current.Status != metav1.ConditionTrue || current.Reason != phase {
+		log.Info("migration phase changed", "name", lv.Name, "phase", phase)
+		recordLogicalVolumeEvent(r.recorder, lv, corev1.EventTypeNormal, EventReasonMigrating, "%s: %s", phase, message)
+	}
+	setStatusCondition(lv, topolvmv1.LogicalVolumeMigrating, metav1.ConditionTrue, phase, message)
+	if err := r.client.Status().Update(ctx, lv); err != nil {
+		log.Error(err, "failed to update status", "name", lv.Name)
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// ensureVolume creates lv unless it exists, and returns the current one.
+func (r *VolumeMigrationReconciler) ensureVolume(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (*topolvmv1.LogicalVolume, error) {
+	current := new(topolvmv1.LogicalVolume)
+	err := r.client.Get(ctx, types.NamespacedName{Name: lv.Name}, current)
+	if err == nil {
+		return current, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err := r.client.Create(ctx, lv); err != nil {
+		log.Error(err, "failed to create LogicalVolume", "name", lv.Name)
+		return nil, err
+	}
+	log.Info("created LogicalVolume for migration", "name", lv.Name, "node", lv.Spec.NodeName)
+	return lv, nil
+}
+
+// findPersistentVolume returns the PersistentVolume of the volume ID, or nil if it does not exist.
+func (r *VolumeMigrationReconciler) findPersistentVolume(ctx context.Context, volumeID string) (*corev1.PersistentVolume, error) {
+	pvList := new(corev1.PersistentVolumeList)
+	if err := r.client.List(ctx, pvList); err != nil {
+		return nil, err
+	}
+	for i := range pvList.Items {
+		csi := pvList.Items[i].Spec.CSI
+		if csi != nil && csi.Driver == topolvm.GetPluginName() && csi.VolumeHandle == volumeID {
+			return &pvList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// findConsumers returns the names of the pods running with pvc.
+func (r *VolumeMigrationReconciler) findConsumers(ctx context.Context, pvc *corev1.PersistentVolumeClaim) ([]string, error) {
+	var pods corev1.PodList
+	// query directly to API server to avoid latency for cache updates
+	if err := r.apiReader.List(ctx, &pods, client.InNamespace(pvc.Namespace)); err != nil {
+		return nil, err
+	}
+	return runningConsumers(pods.Items, pvc.Name), nil
+}
+
+// runningConsumers returns the names of the pods that use the claim and have been scheduled but not terminated.
+func runningConsumers(pods []corev1.Pod, claimName string) []string {
+	var names []string
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+				names = append(names, pod.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// creationFailure returns the reason why lv could not be created, or empty string if it is being created.
+func creationFailure(lv *topolvmv1.LogicalVolume) string {
+	if lv.Status.VolumeID != "" {
+		return ""
+	}
+	created := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeCreated)
+	if created == nil || created.Reason != topolvmv1.ReasonCreateFailed {
+		return ""
+	}
+	return created.Message
+}
+
+func migrationSnapshotName(lv *topolvmv1.LogicalVolume) string {
+	return "migration-" + string(lv.UID)
+}
+
+func migrationTargetName(lv *topolvmv1.LogicalVolume) string {
+	return "pvc-" + string(lv.UID)
+}
+
+// newMigratedPV returns a PersistentVolume of the copied volume, which is reserved for the claim of pv.
+func newMigratedPV(pv *corev1.PersistentVolume, copied *topolvmv1.LogicalVolume) *corev1.PersistentVolume {
+	annotations := make(map[string]string)
+	for k, v := range pv.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, "pv.kubernetes.io/bound-by-controller")
+	delete(annotations, topolvm.GetRebindToKey())
+
+	newPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        copied.Name,
+			Labels:      pv.Labels,
+			Annotations: annotations,
+		},
+		Spec: *pv.Spec.DeepCopy(),
+	}
+	newPV.Spec.CSI.VolumeHandle = copied.Status.VolumeID
+	newPV.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{
+		Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      topolvm.GetTopologyNodeKey(),
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{copied.Spec.NodeName},
+				}},
+			}},
+		},
+	}
+	// The claimRef without UID pre-binds the PV to the re-created claim.
+	newPV.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Namespace:  pv.Spec.ClaimRef.Namespace,
+		Name:       pv.Spec.ClaimRef.Name,
+	}
+	return newPV
+}
+
+// newMigratedClaim returns the claim to be re-created in place of pvc to use the PersistentVolume pvName on nodeName.
+func newMigratedClaim(pvc *corev1.PersistentVolumeClaim, pvName, nodeName string) *corev1.PersistentVolumeClaim {
+	annotations := make(map[string]string)
+	for k, v := range pvc.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, topolvm.GetMigrateToKey())
+	delete(annotations, "pv.kubernetes.io/bind-completed")
+	delete(annotations, "pv.kubernetes.io/bound-by-controller")
+	annotations[AnnSelectedNode] = nodeName
+
+	claim := &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pvc.Name,
+			Namespace:       pvc.Namespace,
+			Labels:          pvc.Labels,
+			Annotations:     annotations,
+			OwnerReferences: pvc.OwnerReferences,
+		},
+		Spec: *pvc.Spec.DeepCopy(),
+	}
+	claim.Spec.VolumeName = pvName
+	// The data source has been used to populate the volume already.
+	claim.Spec.DataSource = nil
+	claim.Spec.DataSourceRef = nil
+	return claim
+}
+
+// claimToLogicalVolume maps a PersistentVolumeClaim requesting migration to its LogicalVolume.
+func (r *VolumeMigrationReconciler) claimToLogicalVolume(ctx context.Context, obj client.Object) []reconcile.Request {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok || pvc.Spec.VolumeName == "" {
+		return nil
+	}
+	pv := new(corev1.PersistentVolume)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+		return nil
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != topolvm.GetPluginName() {
+		return nil
+	}
+	lv, err := findLogicalVolume(ctx, r.client, pv.Spec.CSI.VolumeHandle)
+	if err != nil || lv == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: lv.Name}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VolumeMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	requested := builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[topolvm.GetMigrateToKey()]
+		return ok
+	}))
+
+	b := ctrl.NewControllerManagedBy(mgr)
+	if topolvm.UseLegacy() {
+		b = b.For(&topolvmlegacyv1.LogicalVolume{}, requested)
+	} else {
+		b = b.For(&topolvmv1.LogicalVolume{}, requested)
+	}
+	return b.Watches(&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.claimToLogicalVolume), requested).
+		Complete(r)
+}
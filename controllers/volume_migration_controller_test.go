@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("VolumeMigration controller", func() {
+	ctx := context.Background()
+
+	// setupMigration creates the LogicalVolume being migrated at step, and its PersistentVolume and claim.
+	setupMigration := func(suffix, step string) (*topolvmv1.LogicalVolume, *corev1.PersistentVolumeClaim) {
+		ns := createNamespace()
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "migration" + suffix,
+				Annotations: map[string]string{topolvm.GetMigrateToKey(): "node-dst" + suffix},
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     "migration" + suffix,
+				NodeName: "node-src" + suffix,
+				Size:     resource.MustParse("1Gi"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, lv)).To(Succeed())
+		lv.Status.VolumeID = string(lv.UID)
+		lv.Status.Migration = &topolvmv1.MigrationStatus{Step: step}
+		setStatusCondition(lv, topolvmv1.LogicalVolumeMigrating, metav1.ConditionTrue, step, "")
+		Expect(k8sClient.Status().Update(ctx, lv)).To(Succeed())
+
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-migration" + suffix},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:    corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: topolvm.GetPluginName(), VolumeHandle: lv.Status.VolumeID},
+				},
+				ClaimRef: &corev1.ObjectReference{Namespace: ns, Name: "data"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pv)).To(Succeed())
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "data",
+				Namespace:   ns,
+				Annotations: map[string]string{topolvm.GetMigrateToKey(): "node-dst" + suffix},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+				VolumeName: pv.Name,
+			},
+		}
+		Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+		return lv, pvc
+	}
+
+	// createMigrationVolume creates the snapshot or the copy of the migration, which has been created if ready.
+	createMigrationVolume := func(name, nodeName, source string, ready bool) *topolvmv1.LogicalVolume {
+		v := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     name,
+				NodeName: nodeName,
+				Size:     resource.MustParse("1Gi"),
+				Source:   source,
+			},
+		}
+		Expect(k8sClient.Create(ctx, v)).To(Succeed())
+		if ready {
+			v.Status.VolumeID = string(v.UID)
+			Expect(k8sClient.Status().Update(ctx, v)).To(Succeed())
+		}
+		return v
+	}
+
+	recordSnapshot := func(lv *topolvmv1.LogicalVolume, volumeID string) {
+		lv.Status.Migration.SnapshotVolumeID = volumeID
+		Expect(k8sClient.Status().Update(ctx, lv)).To(Succeed())
+	}
+
+	// reconcileMigration runs the reconciler as if it restarted, and returns the step recorded on lv.
+	reconcileMigration := func(lv *topolvmv1.LogicalVolume) string {
+		r := NewVolumeMigrationReconciler(k8sClient, k8sClient, record.NewFakeRecorder(100))
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: lv.Name}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(lv), lv)).To(Succeed())
+		migrating := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeMigrating)
+		Expect(migrating).NotTo(BeNil())
+		if migrating.Status != metav1.ConditionTrue {
+			return migrating.Reason
+		}
+		Expect(lv.Status.Migration).NotTo(BeNil())
+		Expect(lv.Status.Migration.Step).To(Equal(migrating.Reason))
+		return lv.Status.Migration.Step
+	}
+
+	It("should resume the migration from each persisted step", func() {
+		By("waiting for the snapshot being taken")
+		lv, _ := setupMigration("-snapshotting", topolvmv1.ReasonSnapshotting)
+		createMigrationVolume(migrationSnapshotName(lv), lv.Spec.NodeName, lv.Name, false)
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonSnapshotting))
+		Expect(lv.Status.Migration.SnapshotVolumeID).To(BeEmpty())
+
+		By("recording the snapshot taken and starting the copy")
+		lv, _ = setupMigration("-snapshotted", topolvmv1.ReasonSnapshotting)
+		snapshot := createMigrationVolume(migrationSnapshotName(lv), lv.Spec.NodeName, lv.Name, true)
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonCopying))
+		Expect(lv.Status.Migration.SnapshotVolumeID).To(Equal(snapshot.Status.VolumeID))
+		copied := new(topolvmv1.LogicalVolume)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: migrationTargetName(lv)}, copied)).To(Succeed())
+		Expect(copied.Spec.Source).To(Equal(snapshot.Name))
+		Expect(copied.Spec.NodeName).To(Equal("node-dst-snapshotted"))
+
+		By("waiting for the copy")
+		lv, _ = setupMigration("-copying", topolvmv1.ReasonCopying)
+		snapshot = createMigrationVolume(migrationSnapshotName(lv), lv.Spec.NodeName, lv.Name, true)
+		recordSnapshot(lv, snapshot.Status.VolumeID)
+		createMigrationVolume(migrationTargetName(lv), "node-dst-copying", snapshot.Name, false)
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonCopying))
+
+		By("waiting for the pods using the claim")
+		lv, pvc := setupMigration("-waiting", topolvmv1.ReasonWaitingForConsumer)
+		snapshot = createMigrationVolume(migrationSnapshotName(lv), lv.Spec.NodeName, lv.Name, true)
+		recordSnapshot(lv, snapshot.Status.VolumeID)
+		createMigrationVolume(migrationTargetName(lv), "node-dst-waiting", snapshot.Name, true)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: pvc.Namespace},
+			Spec: corev1.PodSpec{
+				NodeName:   lv.Spec.NodeName,
+				Containers: []corev1.Container{{Name: "ubuntu", Image: "ubuntu"}},
+				Volumes: []corev1.Volume{{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+					},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonWaitingForConsumer))
+
+		By("requesting the sync and switching the claim after it")
+		lv, _ = setupMigration("-syncing", topolvmv1.ReasonSyncing)
+		snapshot = createMigrationVolume(migrationSnapshotName(lv), lv.Spec.NodeName, lv.Name, true)
+		recordSnapshot(lv, snapshot.Status.VolumeID)
+		copied = createMigrationVolume(migrationTargetName(lv), "node-dst-syncing", snapshot.Name, true)
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonSyncing))
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(copied), copied)).To(Succeed())
+		Expect(copied.Annotations).To(HaveKey(topolvm.GetSyncRequestedKey()))
+
+		setStatusCondition(copied, topolvmv1.LogicalVolumeSynced, metav1.ConditionTrue, topolvmv1.ReasonSynced, "")
+		Expect(k8sClient.Status().Update(ctx, copied)).To(Succeed())
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonSwitching))
+		Expect(lv.Annotations).To(HaveKey(topolvm.GetMigrationClaimKey()))
+
+		By("switching the claim to the copy")
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonSwitching))
+		newPV := new(corev1.PersistentVolume)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: copied.Name}, newPV)).To(Succeed())
+		Expect(newPV.Spec.CSI.VolumeHandle).To(Equal(copied.Status.VolumeID))
+	})
+
+	It("should fail the migration when the snapshot of the copy is lost", func() {
+		lv, pvc := setupMigration("-lost", topolvmv1.ReasonCopying)
+		recordSnapshot(lv, "lost")
+		copied := createMigrationVolume(migrationTargetName(lv), "node-dst-lost", migrationSnapshotName(lv), false)
+
+		Expect(reconcileMigration(lv)).To(Equal(topolvmv1.ReasonMigrationFailed))
+		Expect(lv.Status.Migration).To(BeNil())
+		Expect(lv.Annotations).NotTo(HaveKey(topolvm.GetMigrateToKey()))
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(copied), copied)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		snapshot := new(topolvmv1.LogicalVolume)
+		err = k8sClient.Get(ctx, types.NamespacedName{Name: migrationSnapshotName(lv)}, snapshot)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the snapshot should not be taken again")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).To(Succeed())
+		Expect(pvc.Annotations).NotTo(HaveKey(topolvm.GetMigrateToKey()))
+	})
+
+	It("should find the running pods using the claim", func() {
+		claimVolume := func(claimName string) corev1.Volume {
+			return corev1.Volume{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+				},
+			}
+		}
+		pod := func(name, nodeName, claimName string, phase corev1.PodPhase) corev1.Pod {
+			return corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+					Volumes:  []corev1.Volume{{Name: "config"}, claimVolume(claimName)},
+				},
+				Status: corev1.PodStatus{Phase: phase},
+			}
+		}
+
+		pods := []corev1.Pod{
+			pod("running", "node1", "data", corev1.PodRunning),
+			pod("pending", "", "data", corev1.PodPending),
+			pod("completed", "node1", "data", corev1.PodSucceeded),
+			pod("other", "node1", "other", corev1.PodRunning),
+			pod("starting", "node1", "data", corev1.PodPending),
+		}
+		Expect(runningConsumers(pods, "data")).To(Equal([]string{"running", "starting"}))
+	})
+
+	It("should build the PersistentVolume and the claim of the copied volume", func() {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pvc-source",
+				Annotations: map[string]string{
+					"pv.kubernetes.io/provisioned-by":      topolvm.GetPluginName(),
+					"pv.kubernetes.io/bound-by-controller": "yes",
+				},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				StorageClassName:              "topolvm-provisioner",
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:           topolvm.GetPluginName(),
+						VolumeHandle:     "source-volume",
+						VolumeAttributes: map[string]string{"key": "value"},
+					},
+				},
+				ClaimRef: &corev1.ObjectReference{Namespace: "ns", Name: "data", UID: "uid"},
+			},
+		}
+		copied := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-target"},
+			Spec:       topolvmv1.LogicalVolumeSpec{NodeName: "node2"},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: "target-volume"},
+		}
+
+		newPV := newMigratedPV(pv, copied)
+		Expect(newPV.Name).To(Equal("pvc-target"))
+		Expect(newPV.Annotations).To(Equal(map[string]string{"pv.kubernetes.io/provisioned-by": topolvm.GetPluginName()}))
+		Expect(newPV.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+		Expect(newPV.Spec.CSI.VolumeHandle).To(Equal("target-volume"))
+		Expect(newPV.Spec.CSI.VolumeAttributes).To(Equal(map[string]string{"key": "value"}))
+		Expect(newPV.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values).To(Equal([]string{"node2"}))
+		Expect(newPV.Spec.ClaimRef.Name).To(Equal("data"))
+		Expect(newPV.Spec.ClaimRef.UID).To(BeEmpty())
+		Expect(pv.Spec.CSI.VolumeHandle).To(Equal("source-volume"))
+
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data",
+				Namespace: "ns",
+				UID:       "uid",
+				Annotations: map[string]string{
+					topolvm.GetMigrateToKey():          "node2",
+					AnnSelectedNode:                    "node1",
+					"pv.kubernetes.io/bind-completed":  "yes",
+					"volume.kubernetes.io/provisioner": topolvm.GetPluginName(),
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeName:       "pvc-source",
+				DataSource:       &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "origin"},
+				StorageClassName: &pv.Spec.StorageClassName,
+			},
+		}
+		claim := newMigratedClaim(pvc, newPV.Name, "node2")
+		Expect(claim.Name).To(Equal("data"))
+		Expect(claim.UID).To(BeEmpty())
+		Expect(claim.Annotations).To(Equal(map[string]string{
+			AnnSelectedNode:                    "node2",
+			"volume.kubernetes.io/provisioner": topolvm.GetPluginName(),
+		}))
+		Expect(claim.Spec.VolumeName).To(Equal("pvc-target"))
+		Expect(claim.Spec.DataSource).To(BeNil())
+		Expect(*claim.Spec.StorageClassName).To(Equal("topolvm-provisioner"))
+		Expect(pvc.Annotations).To(HaveKey(topolvm.GetMigrateToKey()))
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,27 +16,44 @@ import (
 	"github.com/topolvm/topolvm/driver/internal/k8s"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 var ctrlLogger = ctrl.Log.WithName("driver").WithName("controller")
 
-// NewControllerServer returns a new ControllerServer.
-func NewControllerServer(mgr manager.Manager) (csi.ControllerServer, error) {
+// Keys of the parameters added by external-provisioner with --extra-create-metadata.
+const (
+	pvcNameKey      = "csi.storage.k8s.io/pvc/name"
+	pvcNamespaceKey = "csi.storage.k8s.io/pvc/namespace"
+	pvNameKey       = "csi.storage.k8s.io/pv/name"
+)
+
+// NewControllerServer returns a new ControllerServer and GroupControllerServer.
+func NewControllerServer(mgr manager.Manager) (csi.ControllerServer, csi.GroupControllerServer, error) {
 	lvService, err := k8s.NewLogicalVolumeService(mgr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &controllerServer{
-		lockByName:     NewLockWithID(),
+	lockByName := NewLockWithID()
+	controller := &controllerServer{
+		lockByName:     lockByName,
 		lockByVolumeID: NewLockWithID(),
 		server: &controllerServerNoLocked{
 			lvService:   lvService,
 			nodeService: k8s.NewNodeService(mgr.GetClient()),
 		},
-	}, nil
+	}
+	groupController := &groupControllerServer{
+		lockByName: lockByName,
+		server: &groupControllerServerNoLocked{
+			lvService: lvService,
+		},
+	}
+	return controller, groupController, nil
 }
 
 // This is a wrapper for controllerServerNoLocked to protect concurrent method call.
@@ -101,6 +120,13 @@ func (s *controllerServer) ControllerExpandVolume(ctx context.Context, req *csi.
 	return s.server.ControllerExpandVolume(ctx, req)
 }
 
+func (s *controllerServer) ControllerModifyVolume(ctx context.Context, req *csi.ControllerModifyVolumeRequest) (*csi.ControllerModifyVolumeResponse, error) {
+	s.lockByVolumeID.LockByID(req.GetVolumeId())
+	defer s.lockByVolumeID.UnlockByID(req.GetVolumeId())
+
+	return s.server.ControllerModifyVolume(ctx, req)
+}
+
 // controllerServerNoLocked implements csi.ControllerServer.
 // It does not take any lock, gRPC calls may be interleaved.
 // Therefore, must not use it directly.
@@ -140,6 +166,7 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 	}
 
 	// check required volume capabilities
+	var filesystem k8s.VolumeFilesystem
 	for _, capability := range capabilities {
 		if block := capability.GetBlock(); block != nil {
 			ctrlLogger.Info("CreateVolume specifies volume capability", "access_type", "block")
@@ -148,6 +175,12 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 				"access_type", "mount",
 				"fs_type", mount.GetFsType(),
 				"flags", mount.GetMountFlags())
+			if filesystem.FsType == "" {
+				filesystem.FsType = mount.GetFsType()
+				if filesystem.FsType == "" {
+					filesystem.FsType = defaultFsType
+				}
+			}
 		} else {
 			return nil, status.Error(codes.InvalidArgument, "unknown or empty access_type")
 		}
@@ -164,9 +197,42 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		}
 	}
 
-	requestGb, err := convertRequestCapacity(req.GetCapacityRange().GetRequiredBytes(), req.GetCapacityRange().GetLimitBytes())
+	requestBytes, err := convertRequestCapacity(req.GetCapacityRange().GetRequiredBytes(), req.GetCapacityRange().GetLimitBytes())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, capacityRangeError(err)
+	}
+
+	if _, ok := req.GetParameters()[topolvm.GetThinZeroingKey()]; ok {
+		return nil, status.Error(codes.InvalidArgument, errThinZeroing.Error())
+	}
+
+	encrypted := false
+	if v, ok := req.GetParameters()[topolvm.GetEncryptedKey()]; ok {
+		encrypted, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value for %s: %q", topolvm.GetEncryptedKey(), v)
+		}
+	}
+	// lvBytes is the size of the LV, which has room for the LUKS header if encrypted.
+	var headerBytes int64
+	if encrypted {
+		headerBytes = luksHeaderSize
+	}
+	lvBytes := requestBytes + headerBytes
+
+	if filesystem.FsType != "" {
+		filesystem.MkfsOptions, err = makeMkfsOptions(filesystem.FsType, req.GetParameters())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if v, ok := req.GetParameters()[topolvm.GetFsckPolicyKey()]; ok {
+		switch policy := v1.FsckPolicy(v); policy {
+		case v1.FsckNever, v1.FsckCheckOnly, v1.FsckAutoRepairSafe:
+			filesystem.FsckPolicy = policy
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value for %s: %q", topolvm.GetFsckPolicyKey(), v)
+		}
 	}
 
 	// check if the create volume request has a data source
@@ -178,17 +244,30 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		}
 
 		// check if the volume is equal or bigger than the source volume.
-		sourceSizeGb := sourceVol.Spec.Size.Value() >> 30
-		if requestGb < sourceSizeGb {
+		if lvBytes < sourceVol.Spec.Size.Value() {
 			return nil, status.Error(codes.OutOfRange, "requested size is smaller than the size of the source")
 		}
-		// If a volume has a source, it has to provisioned on the same node and device class as the source volume.
+		// If a volume has a source, it has to provisioned in the same device class as the source volume.
+		// It is provisioned on the same node as the source volume if possible.
 
 		if deviceClass != sourceVol.Spec.DeviceClass {
 			return nil, status.Error(codes.InvalidArgument, "device class mismatch. Snapshots should be created with the same device class as the source.")
 		}
 		deviceClass = sourceVol.Spec.DeviceClass
 		sourceName = sourceVol.Spec.Name
+
+		// The data of the source is copied as is, so a volume cannot be converted from or to an encrypted one.
+		if encrypted != sourceVol.Spec.Encrypted {
+			return nil, status.Error(codes.InvalidArgument, "encryption mismatch. Volumes should be created with the same encryption setting as the source.")
+		}
+
+		// The filesystem is copied as well, so the recorded one of the source is inherited.
+		if filesystem.FsType != "" && sourceVol.Spec.FsType != "" {
+			if filesystem.FsType != sourceVol.Spec.FsType {
+				return nil, status.Error(codes.InvalidArgument, "filesystem type mismatch. Volumes should be created with the same filesystem type as the source.")
+			}
+			filesystem.MkfsOptions = sourceVol.Spec.MkfsOptions
+		}
 	}
 
 	// process topology
@@ -225,7 +304,26 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 				}
 			}
 			if node == "" {
-				return nil, status.Errorf(codes.InvalidArgument, "cannot find source volume's node '%s' in accessibility_requirements", sourceNode)
+				// The source volume's node is not eligible. The volume is created on another node,
+				// and topolvm-node on the node copies the contents of the source volume.
+				ctrlLogger.Info("source volume's node is not in accessibility_requirements", "source_node", sourceNode)
+				for _, topo := range requirements.Preferred {
+					if v, ok := topo.GetSegments()[topolvm.GetTopologyNodeKey()]; ok {
+						node = v
+						break
+					}
+				}
+			}
+			if node == "" {
+				for _, topo := range requirements.Requisite {
+					if v, ok := topo.GetSegments()[topolvm.GetTopologyNodeKey()]; ok {
+						node = v
+						break
+					}
+				}
+			}
+			if node == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "cannot find key '%s' in accessibility_requirements", topolvm.GetTopologyNodeKey())
 			}
 		}
 	} else {
@@ -243,7 +341,7 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 			if nodeName == "" {
 				return nil, status.Error(codes.Internal, "can not find any node")
 			}
-			if capacity < (requestGb << 30) {
+			if capacity < lvBytes {
 				return nil, status.Errorf(codes.ResourceExhausted, "can not find enough volume space %d", capacity)
 			}
 			node = nodeName
@@ -275,7 +373,23 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 
 	name = strings.ToLower(name)
 
-	volumeID, err := s.lvService.CreateVolume(ctx, node, deviceClass, lvcreateOptionClass, name, sourceName, requestGb)
+	owner := k8s.VolumeOwner{
+		PVCName:      req.GetParameters()[pvcNameKey],
+		PVCNamespace: req.GetParameters()[pvcNamespaceKey],
+		PVName:       req.GetParameters()[pvNameKey],
+	}
+	var attrs k8s.VolumeAttributes
+	if err := applyVolumeAttributes(req.GetParameters(), &attrs); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// mutable_parameters come from VolumeAttributesClass and take precedence over StorageClass parameters.
+	if err := validateMutableParameters(req.GetMutableParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := applyVolumeAttributes(req.GetMutableParameters(), &attrs); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	volumeID, currentBytes, err := s.lvService.CreateVolume(ctx, node, deviceClass, lvcreateOptionClass, name, sourceName, lvBytes, owner, attrs, encrypted, filesystem)
 	if err != nil {
 		_, ok := status.FromError(err)
 		if !ok {
@@ -283,10 +397,18 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		}
 		return nil, err
 	}
+	// lvmd rounds the size up to the extent size, which is not known until the LV is created.
+	if limitBytes := req.GetCapacityRange().GetLimitBytes(); limitBytes != 0 && currentBytes-headerBytes > limitBytes {
+		if err := s.lvService.DeleteVolume(ctx, volumeID); err != nil {
+			ctrlLogger.Error(err, "failed to delete the volume larger than the limit", "volumeID", volumeID)
+		}
+		return nil, status.Errorf(codes.OutOfRange, "the size of the volume rounded up to the extent size exceeds the limit: size=%d limit=%d",
+			currentBytes-headerBytes, limitBytes)
+	}
 
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
-			CapacityBytes: requestGb << 30,
+			CapacityBytes: currentBytes - headerBytes,
 			VolumeId:      volumeID,
 			ContentSource: source,
 			AccessibleTopology: []*csi.Topology{
@@ -355,6 +477,15 @@ func (s controllerServerNoLocked) CreateSnapshot(ctx context.Context, req *csi.C
 		return nil, status.Error(codes.InvalidArgument, "missing name")
 	}
 
+	freeze := false
+	if v, ok := req.GetParameters()[topolvm.GetFreezeFilesystemKey()]; ok {
+		var err error
+		freeze, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value for %s: %q", topolvm.GetFreezeFilesystemKey(), v)
+		}
+	}
+
 	name := strings.ToLower(req.GetName())
 	sourceVolID := req.GetSourceVolumeId()
 	sourceVol, err := s.lvService.GetVolume(ctx, sourceVolID)
@@ -373,7 +504,13 @@ func (s controllerServerNoLocked) CreateSnapshot(ctx context.Context, req *csi.C
 	deviceClass := sourceVol.Spec.DeviceClass
 	size := sourceVol.Spec.Size
 	sourceVolName := sourceVol.Spec.Name
-	snapshotID, err := s.lvService.CreateSnapshot(ctx, node, deviceClass, sourceVolName, name, accessType, size)
+	// the snapshot of an encrypted volume has the same LUKS header as the source, and
+	// the snapshot has the same filesystem as the source.
+	filesystem := k8s.VolumeFilesystem{
+		FsType:      sourceVol.Spec.FsType,
+		MkfsOptions: sourceVol.Spec.MkfsOptions,
+	}
+	snapshotID, err := s.lvService.CreateSnapshot(ctx, node, deviceClass, sourceVolName, name, accessType, size, sourceVol.Spec.Encrypted, filesystem, freeze)
 	if err != nil {
 		_, ok := status.FromError(err)
 		if !ok {
@@ -415,6 +552,20 @@ func (s controllerServerNoLocked) DeleteSnapshot(ctx context.Context, req *csi.D
 	return &csi.DeleteSnapshotResponse{}, nil
 }
 
+// errCapacityOutOfRange is returned when no volume size satisfies the capacity range.
+var errCapacityOutOfRange = errors.New("capacity range cannot be satisfied")
+
+// capacityRangeError converts the error of convertRequestCapacity to a gRPC status error.
+func capacityRangeError(err error) error {
+	if errors.Is(err, errCapacityOutOfRange) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// convertRequestCapacity returns the size of the volume in bytes for the requested capacity.
+// The size is rounded up to a multiple of topolvm.MinimumSectorSize, and lvmd rounds it up further to the extent size.
+// If the request is zero, the size is topolvm.DefaultSize or the limit rounded down to the sector if it is smaller.
 func convertRequestCapacity(requestBytes, limitBytes int64) (int64, error) {
 	if requestBytes < 0 {
 		return 0, errors.New("required capacity must not be negative")
@@ -430,9 +581,167 @@ func convertRequestCapacity(requestBytes, limitBytes int64) (int64, error) {
 	}
 
 	if requestBytes == 0 {
-		return 1, nil
+		if limitBytes == 0 || limitBytes >= topolvm.DefaultSize {
+			return topolvm.DefaultSize, nil
+		}
+		size := limitBytes / topolvm.MinimumSectorSize * topolvm.MinimumSectorSize
+		if size == 0 {
+			return 0, fmt.Errorf("%w: limit=%d is smaller than the sector size %d", errCapacityOutOfRange, limitBytes, topolvm.MinimumSectorSize)
+		}
+		return size, nil
 	}
-	return (requestBytes-1)>>30 + 1, nil
+	size := (requestBytes-1)/topolvm.MinimumSectorSize*topolvm.MinimumSectorSize + topolvm.MinimumSectorSize
+	if limitBytes != 0 && size > limitBytes {
+		return 0, fmt.Errorf("%w: request=%d limit=%d rounded up to the sector size %d is %d",
+			errCapacityOutOfRange, requestBytes, limitBytes, topolvm.MinimumSectorSize, size)
+	}
+	return size, nil
+}
+
+// lvmTagPattern matches the LVM tags that can be specified by users.
+// See "Tags" in lvm(8) for the characters LVM accepts.
+var lvmTagPattern = regexp.MustCompile(`^[A-Za-z0-9_+.=!:&#/][A-Za-z0-9_+.=!:&#/-]*$`)
+
+// maxLVMTagLength is the maximum length of an LVM tag.
+const maxLVMTagLength = 1024
+
+const (
+	// defaultFSTrimInterval is the interval of fstrim used if fstrim is enabled without the interval.
+	defaultFSTrimInterval = 24 * time.Hour
+	// minFSTrimInterval prevents fstrim from running so often that it affects the I/O of the volume.
+	minFSTrimInterval = time.Hour
+)
+
+// mutableParameterKeys returns the keys of the parameters that can be changed by ControllerModifyVolume.
+// The changed I/O limits are applied to the pods already using the volume by NodeGetVolumeStats.
+func mutableParameterKeys() []string {
+	return []string{
+		topolvm.GetReadIOPSKey(),
+		topolvm.GetWriteIOPSKey(),
+		topolvm.GetReadBytesPerSecondKey(),
+		topolvm.GetWriteBytesPerSecondKey(),
+		topolvm.GetReadAheadKey(),
+		topolvm.GetTagsKey(),
+		topolvm.GetFSTrimKey(),
+		topolvm.GetFSTrimIntervalKey(),
+	}
+}
+
+// errThinZeroing is returned when thin zeroing is requested for a volume.
+// `lvchange -Z` changes the thin pool, and so the zeroing of all the volumes in the pool.
+var errThinZeroing = fmt.Errorf("parameter %s is not supported: zeroing is a setting of the thin pool, not of each volume", topolvm.GetThinZeroingKey())
+
+// validateMutableParameters returns an error if params has a key that cannot be changed after creation.
+func validateMutableParameters(params map[string]string) error {
+	if _, ok := params[topolvm.GetThinZeroingKey()]; ok {
+		return errThinZeroing
+	}
+	mutable := make(map[string]struct{})
+	for _, key := range mutableParameterKeys() {
+		mutable[key] = struct{}{}
+	}
+	for key := range params {
+		if _, ok := mutable[key]; !ok {
+			return fmt.Errorf("parameter %s is not modifiable", key)
+		}
+	}
+	return nil
+}
+
+// applyVolumeAttributes overwrites attrs with the attributes specified in params.
+// The parameters other than the volume attributes are ignored.
+func applyVolumeAttributes(params map[string]string, attrs *k8s.VolumeAttributes) error {
+	limits := &v1.IOLimits{}
+	if attrs.IOLimits != nil {
+		*limits = *attrs.IOLimits
+	}
+	found := false
+	for key, field := range map[string]*int64{
+		topolvm.GetReadIOPSKey():            &limits.ReadIOPS,
+		topolvm.GetWriteIOPSKey():           &limits.WriteIOPS,
+		topolvm.GetReadBytesPerSecondKey():  &limits.ReadBytesPerSecond,
+		topolvm.GetWriteBytesPerSecondKey(): &limits.WriteBytesPerSecond,
+	} {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q: %v", key, value, err)
+		}
+		if q.Sign() < 0 {
+			return fmt.Errorf("%s must not be negative: %q", key, value)
+		}
+		*field = q.Value()
+		found = true
+	}
+	if found {
+		attrs.IOLimits = limits
+	}
+
+	if value, ok := params[topolvm.GetReadAheadKey()]; ok {
+		if value != "auto" && value != "none" {
+			if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+				return fmt.Errorf("invalid value for %s: %q: must be auto, none or the number of sectors", topolvm.GetReadAheadKey(), value)
+			}
+		}
+		attrs.ReadAhead = value
+	}
+
+	if value, ok := params[topolvm.GetTagsKey()]; ok {
+		var tags []string
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || containsString(tags, tag) {
+				continue
+			}
+			if len(tag) > maxLVMTagLength || !lvmTagPattern.MatchString(tag) {
+				return fmt.Errorf("invalid LVM tag in %s: %q", topolvm.GetTagsKey(), tag)
+			}
+			if strings.HasPrefix(tag, topolvm.LVReservedTagPrefix) {
+				return fmt.Errorf("LVM tag in %s must not start with %s: %q", topolvm.GetTagsKey(), topolvm.LVReservedTagPrefix, tag)
+			}
+			tags = append(tags, tag)
+		}
+		attrs.Tags = tags
+	}
+
+	if value, ok := params[topolvm.GetFSTrimKey()]; ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q", topolvm.GetFSTrimKey(), value)
+		}
+		if !enabled {
+			attrs.FSTrimInterval = nil
+		} else if attrs.FSTrimInterval == nil {
+			attrs.FSTrimInterval = &metav1.Duration{Duration: defaultFSTrimInterval}
+		}
+	}
+
+	if value, ok := params[topolvm.GetFSTrimIntervalKey()]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q: %v", topolvm.GetFSTrimIntervalKey(), value, err)
+		}
+		if interval < minFSTrimInterval {
+			return fmt.Errorf("%s must be at least %s: %q", topolvm.GetFSTrimIntervalKey(), minFSTrimInterval, value)
+		}
+		// The interval is ignored unless fstrim is enabled.
+		if attrs.FSTrimInterval != nil {
+			attrs.FSTrimInterval = &metav1.Duration{Duration: interval}
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
 
 func (s controllerServerNoLocked) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
@@ -545,6 +854,7 @@ func (s controllerServerNoLocked) ControllerGetCapabilities(context.Context, *cs
 		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_MODIFY_VOLUME,
 	}
 
 	csiCaps := make([]*csi.ControllerServiceCapability, len(capabilities))
@@ -583,9 +893,9 @@ func (s controllerServerNoLocked) ControllerExpandVolume(ctx context.Context, re
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	requestGb, err := convertRequestCapacity(req.GetCapacityRange().GetRequiredBytes(), req.GetCapacityRange().GetLimitBytes())
+	requestBytes, err := convertRequestCapacity(req.GetCapacityRange().GetRequiredBytes(), req.GetCapacityRange().GetLimitBytes())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, capacityRangeError(err)
 	}
 
 	currentSize := lv.Status.CurrentSize
@@ -594,12 +904,17 @@ func (s controllerServerNoLocked) ControllerExpandVolume(ctx context.Context, re
 		currentSize = &lv.Spec.Size
 	}
 
-	currentGb := currentSize.Value() >> 30
-	if requestGb <= currentGb {
+	// The sizes of encrypted volumes include the LUKS header.
+	var headerBytes int64
+	if lv.Spec.Encrypted {
+		headerBytes = luksHeaderSize
+	}
+	currentBytes := currentSize.Value()
+	if requestBytes+headerBytes <= currentBytes {
 		// "NodeExpansionRequired" is still true because it is unknown
 		// whether node expansion is completed or not.
 		return &csi.ControllerExpandVolumeResponse{
-			CapacityBytes:         currentGb << 30,
+			CapacityBytes:         currentBytes - headerBytes,
 			NodeExpansionRequired: true,
 		}, nil
 	}
@@ -608,11 +923,11 @@ func (s controllerServerNoLocked) ControllerExpandVolume(ctx context.Context, re
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	if capacity < (requestGb<<30 - currentGb<<30) {
+	if capacity < requestBytes+headerBytes-currentBytes {
 		return nil, status.Error(codes.Internal, "not enough space")
 	}
 
-	err = s.lvService.ExpandVolume(ctx, volumeID, requestGb)
+	expandedBytes, err := s.lvService.ExpandVolume(ctx, volumeID, requestBytes+headerBytes)
 	if err != nil {
 		_, ok := status.FromError(err)
 		if !ok {
@@ -621,7 +936,50 @@ func (s controllerServerNoLocked) ControllerExpandVolume(ctx context.Context, re
 		return nil, err
 	}
 	return &csi.ControllerExpandVolumeResponse{
-		CapacityBytes:         requestGb << 30,
+		CapacityBytes:         expandedBytes - headerBytes,
 		NodeExpansionRequired: true,
 	}, nil
 }
+
+func (s controllerServerNoLocked) ControllerModifyVolume(ctx context.Context, req *csi.ControllerModifyVolumeRequest) (*csi.ControllerModifyVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	ctrlLogger.Info("ControllerModifyVolume called",
+		"volumeID", volumeID,
+		"mutable_parameters", req.GetMutableParameters(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "volume id is nil")
+	}
+	if err := validateMutableParameters(req.GetMutableParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	lv, err := s.lvService.GetVolume(ctx, volumeID)
+	if err != nil {
+		if err == k8s.ErrVolumeNotFound {
+			return nil, status.Errorf(codes.NotFound, "LogicalVolume for volume id %s is not found", volumeID)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	attrs := k8s.VolumeAttributes{
+		IOLimits:       lv.Spec.IOLimits,
+		ReadAhead:      lv.Spec.ReadAhead,
+		Tags:           lv.Spec.Tags,
+		FSTrimInterval: lv.Spec.FSTrimInterval,
+	}
+	if err := applyVolumeAttributes(req.GetMutableParameters(), &attrs); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.lvService.ModifyVolume(ctx, volumeID, attrs)
+	if err != nil {
+		_, ok := status.FromError(err)
+		if !ok {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, err
+	}
+	return &csi.ControllerModifyVolumeResponse{}, nil
+}
@@ -2,53 +2,188 @@ package driver
 
 import (
 	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/driver/internal/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestController(t *testing.T) {
-	_, err := convertRequestCapacity(-1, 10)
-	if err == nil {
-		t.Error("should be error")
+	testCases := []struct {
+		name       string
+		request    int64
+		limit      int64
+		expected   int64
+		outOfRange bool
+		invalid    bool
+	}{
+		{name: "negative request", request: -1, limit: 10, invalid: true},
+		{name: "negative limit", request: 10, limit: -1, invalid: true},
+		{name: "request larger than limit", request: 20, limit: 10, invalid: true},
+		{name: "no request", request: 0, limit: 0, expected: topolvm.DefaultSize},
+		{name: "no request with large limit", request: 0, limit: 2 << 30, expected: topolvm.DefaultSize},
+		{name: "no request with small limit", request: 0, limit: 500 << 20, expected: 500 << 20},
+		{name: "no request with unaligned limit", request: 0, limit: 500<<20 + 1000, expected: 500 << 20},
+		{name: "no request with limit smaller than sector", request: 0, limit: 1000, outOfRange: true},
+		{name: "small request", request: 1, limit: 0, expected: 4096},
+		{name: "aligned request and limit", request: 1 << 30, limit: 1 << 30, expected: 1 << 30},
+		{name: "unaligned request", request: 1<<30 + 1, limit: 0, expected: 1<<30 + 4096},
+		{name: "unaligned request under aligned limit", request: 1<<30 + 1, limit: 1<<30 + 4096, expected: 1<<30 + 4096},
+		{name: "request and limit in the same sector", request: 1000, limit: 1000, outOfRange: true},
+		{name: "unaligned limit", request: 1<<30 + 1, limit: 1<<30 + 1, outOfRange: true},
 	}
-
-	_, err = convertRequestCapacity(10, -1)
-	if err == nil {
-		t.Error("should be error")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := convertRequestCapacity(tc.request, tc.limit)
+			switch {
+			case tc.outOfRange:
+				if status.Code(capacityRangeError(err)) != codes.OutOfRange {
+					t.Errorf("should be out of range: %v", err)
+				}
+			case tc.invalid:
+				if status.Code(capacityRangeError(err)) != codes.InvalidArgument {
+					t.Errorf("should be invalid argument: %v", err)
+				}
+			case err != nil:
+				t.Errorf("should not be error: %v", err)
+			case v != tc.expected:
+				t.Errorf("should be %d: %d", tc.expected, v)
+			}
+		})
 	}
+}
 
-	_, err = convertRequestCapacity(20, 10)
-	if err == nil {
-		t.Error("should be error")
+func TestApplyVolumeAttributes(t *testing.T) {
+	var attrs k8s.VolumeAttributes
+	err := applyVolumeAttributes(map[string]string{"foo": "bar"}, &attrs)
+	if err != nil {
+		t.Error("should not be error")
+	}
+	if attrs.IOLimits != nil || attrs.ReadAhead != "" || attrs.Tags != nil {
+		t.Errorf("should be empty: %v", attrs)
 	}
 
-	v, err := convertRequestCapacity(0, 10)
+	err = applyVolumeAttributes(map[string]string{
+		topolvm.GetReadIOPSKey():            "1000",
+		topolvm.GetWriteBytesPerSecondKey(): "100Mi",
+		topolvm.GetReadAheadKey():           "256",
+		topolvm.GetTagsKey():                "foo, bar,,foo",
+	}, &attrs)
 	if err != nil {
 		t.Error("should not be error")
 	}
-	if v != 1 {
-		t.Errorf("should be 1: %d", v)
+	limits := attrs.IOLimits
+	if limits == nil || limits.ReadIOPS != 1000 || limits.WriteBytesPerSecond != 100<<20 || limits.WriteIOPS != 0 {
+		t.Errorf("unexpected limits: %v", limits)
+	}
+	if attrs.ReadAhead != "256" {
+		t.Errorf("unexpected read-ahead: %s", attrs.ReadAhead)
+	}
+	if len(attrs.Tags) != 2 || attrs.Tags[0] != "foo" || attrs.Tags[1] != "bar" {
+		t.Errorf("unexpected tags: %v", attrs.Tags)
 	}
 
-	v, err = convertRequestCapacity(1, 0)
+	// attributes not in the parameters are kept
+	err = applyVolumeAttributes(map[string]string{
+		topolvm.GetWriteIOPSKey(): "10",
+		topolvm.GetTagsKey():      "",
+	}, &attrs)
 	if err != nil {
 		t.Error("should not be error")
 	}
-	if v != 1 {
-		t.Errorf("should be 1: %d", v)
+	limits = attrs.IOLimits
+	if limits.ReadIOPS != 1000 || limits.WriteIOPS != 10 {
+		t.Errorf("unexpected limits: %v", limits)
+	}
+	if attrs.ReadAhead != "256" {
+		t.Errorf("unexpected read-ahead: %s", attrs.ReadAhead)
+	}
+	if len(attrs.Tags) != 0 {
+		t.Errorf("unexpected tags: %v", attrs.Tags)
 	}
 
-	v, err = convertRequestCapacity(1<<30, 1<<30)
+	for _, params := range []map[string]string{
+		{topolvm.GetWriteIOPSKey(): "-1"},
+		{topolvm.GetWriteIOPSKey(): "many"},
+		{topolvm.GetReadAheadKey(): "-1"},
+		{topolvm.GetTagsKey(): "-foo"},
+		{topolvm.GetTagsKey(): "foo bar"},
+		{topolvm.GetTagsKey(): topolvm.GetLVOwnerTag("foo")},
+		{topolvm.GetFSTrimKey(): "sometimes"},
+		{topolvm.GetFSTrimKey(): "true", topolvm.GetFSTrimIntervalKey(): "1d"},
+		{topolvm.GetFSTrimKey(): "true", topolvm.GetFSTrimIntervalKey(): "1m"},
+	} {
+		if err := applyVolumeAttributes(params, &k8s.VolumeAttributes{}); err == nil {
+			t.Errorf("should be error: %v", params)
+		}
+	}
+}
+
+func TestApplyFSTrimAttributes(t *testing.T) {
+	var attrs k8s.VolumeAttributes
+
+	// the interval is ignored unless fstrim is enabled
+	err := applyVolumeAttributes(map[string]string{topolvm.GetFSTrimIntervalKey(): "12h"}, &attrs)
 	if err != nil {
-		t.Error("should not be error")
+		t.Fatal(err)
 	}
-	if v != 1 {
-		t.Errorf("should be 1: %d", v)
+	if attrs.FSTrimInterval != nil {
+		t.Errorf("should be disabled: %v", attrs.FSTrimInterval)
 	}
 
-	v, err = convertRequestCapacity(1<<30+1, 1<<30+1)
+	err = applyVolumeAttributes(map[string]string{topolvm.GetFSTrimKey(): "true"}, &attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.FSTrimInterval == nil || attrs.FSTrimInterval.Duration != defaultFSTrimInterval {
+		t.Errorf("unexpected interval: %v", attrs.FSTrimInterval)
+	}
+
+	err = applyVolumeAttributes(map[string]string{topolvm.GetFSTrimIntervalKey(): "12h"}, &attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.FSTrimInterval == nil || attrs.FSTrimInterval.Duration != 12*time.Hour {
+		t.Errorf("unexpected interval: %v", attrs.FSTrimInterval)
+	}
+
+	// enabling fstrim again keeps the interval
+	err = applyVolumeAttributes(map[string]string{topolvm.GetFSTrimKey(): "true"}, &attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.FSTrimInterval == nil || attrs.FSTrimInterval.Duration != 12*time.Hour {
+		t.Errorf("unexpected interval: %v", attrs.FSTrimInterval)
+	}
+
+	err = applyVolumeAttributes(map[string]string{topolvm.GetFSTrimKey(): "false"}, &attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.FSTrimInterval != nil {
+		t.Errorf("should be disabled: %v", attrs.FSTrimInterval)
+	}
+}
+
+func TestValidateMutableParameters(t *testing.T) {
+	err := validateMutableParameters(map[string]string{
+		topolvm.GetReadIOPSKey():  "1000",
+		topolvm.GetReadAheadKey(): "auto",
+		topolvm.GetTagsKey():      "foo",
+	})
 	if err != nil {
 		t.Error("should not be error")
 	}
-	if v != 2 {
-		t.Errorf("should be 2: %d", v)
+
+	err = validateMutableParameters(map[string]string{topolvm.GetDeviceClassKey(): "ssd"})
+	if err == nil {
+		t.Error("should be error")
+	}
+
+	err = validateMutableParameters(map[string]string{topolvm.GetThinZeroingKey(): "true"})
+	if err != errThinZeroing {
+		t.Error("thin zeroing should be rejected:", err)
 	}
 }
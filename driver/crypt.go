@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	utilexec "k8s.io/utils/exec"
+)
+
+const (
+	cryptsetupCmd = "/sbin/cryptsetup"
+
+	// encryptionPassphraseKey is the key of the CSI secrets that holds the passphrase of encrypted volumes.
+	encryptionPassphraseKey = "passphrase"
+
+	// luksFilesystemType is the type that blkid reports for LUKS devices.
+	luksFilesystemType = "crypto_LUKS"
+
+	// sysBlockDirectory lists the block devices of the host.
+	sysBlockDirectory = "/sys/class/block"
+
+	// luksHeaderSize is the offset of the data in LUKS devices, which is the default of LUKS2.
+	// Encrypted volumes are created larger by this size so that the requested capacity is usable.
+	luksHeaderSize = 16 << 20
+)
+
+// cryptMapperName returns the device-mapper name of the opened encrypted volume.
+func cryptMapperName(volumeID string) string {
+	return "topolvm-" + volumeID
+}
+
+// cryptBackingDevice returns the path of the device file of the LV underlying the encrypted volume.
+// The device file at filepath.Join(DeviceDirectory, volumeID) refers to the mapped device instead.
+func cryptBackingDevice(volumeID string) string {
+	return filepath.Join(DeviceDirectory, "luks", volumeID)
+}
+
+// findDMDevice returns the device numbers of the device-mapper device named name.
+// It returns an error wrapping os.ErrNotExist if the device does not exist.
+func findDMDevice(sysBlock, name string) (uint32, uint32, error) {
+	entries, err := os.ReadDir(sysBlock)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "dm-") {
+			continue
+		}
+		dmName, err := os.ReadFile(filepath.Join(sysBlock, e.Name(), "dm", "name"))
+		if os.IsNotExist(err) {
+			// the device has been removed after listing.
+			continue
+		} else if err != nil {
+			return 0, 0, err
+		}
+		if strings.TrimSpace(string(dmName)) != name {
+			continue
+		}
+
+		dev, err := os.ReadFile(filepath.Join(sysBlock, e.Name(), "dev"))
+		if err != nil {
+			return 0, 0, err
+		}
+		var major, minor uint32
+		if _, err := fmt.Sscanf(strings.TrimSpace(string(dev)), "%d:%d", &major, &minor); err != nil {
+			return 0, 0, fmt.Errorf("invalid device numbers of %s: %q: %v", e.Name(), dev, err)
+		}
+		return major, minor, nil
+	}
+	return 0, 0, fmt.Errorf("device-mapper device %s is not found: %w", name, os.ErrNotExist)
+}
+
+// runCryptsetup runs cryptsetup with args.
+// The passphrase is given through stdin so that it does not appear in the process list.
+func runCryptsetup(exec utilexec.Interface, passphrase []byte, args ...string) error {
+	cmd := exec.Command(cryptsetupCmd, args...)
+	if passphrase != nil {
+		cmd.SetStdin(bytes.NewReader(passphrase))
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cryptsetup %s failed: output=%s, error=%v", args[0], string(out), err)
+	}
+	return nil
+}
+
+// luksFormat formats device as a LUKS2 device whose data starts at luksHeaderSize.
+func luksFormat(exec utilexec.Interface, device string, passphrase []byte) error {
+	return runCryptsetup(exec, passphrase, "luksFormat", "--batch-mode", "--type", "luks2",
+		"--offset", strconv.Itoa(luksHeaderSize/512), "--key-file", "-", device)
+}
+
+func luksOpen(exec utilexec.Interface, device, name string, passphrase []byte) error {
+	return runCryptsetup(exec, passphrase, "open", "--type", "luks", "--key-file", "-", device, name)
+}
+
+func luksClose(exec utilexec.Interface, name string) error {
+	return runCryptsetup(exec, nil, "close", name)
+}
+
+// luksResize resizes the opened LUKS device to the size of the underlying LV.
+// The passphrase may be nil if the volume key is kept in the kernel keyring.
+func luksResize(exec utilexec.Interface, name string, passphrase []byte) error {
+	if passphrase == nil {
+		return runCryptsetup(exec, nil, "resize", name)
+	}
+	return runCryptsetup(exec, passphrase, "resize", "--key-file", "-", name)
+}
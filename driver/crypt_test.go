@@ -0,0 +1,93 @@
+package driver
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	utilexec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+func TestFindDMDevice(t *testing.T) {
+	root := t.TempDir()
+	for dev, content := range map[string][2]string{
+		"dm-0": {"vg-pool", "253:0"},
+		"dm-3": {"topolvm-1234", "253:3\n"},
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dev, "dm"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dev, "dm", "name"), []byte(content[0]+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dev, "dev"), []byte(content[1]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "sda"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	major, minor, err := findDMDevice(root, "topolvm-1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if major != 253 || minor != 3 {
+		t.Errorf("unexpected device numbers: %d:%d", major, minor)
+	}
+
+	_, _, err = findDMDevice(root, "topolvm-5678")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("should be ErrNotExist: %v", err)
+	}
+}
+
+func TestCryptsetup(t *testing.T) {
+	var argv [][]string
+	var fakeCmds []*testingexec.FakeCmd
+	fakeExec := &testingexec.FakeExec{}
+	for i := 0; i < 3; i++ {
+		fakeCmd := &testingexec.FakeCmd{
+			CombinedOutputScript: []testingexec.FakeAction{
+				func() ([]byte, []byte, error) { return nil, nil, nil },
+			},
+		}
+		fakeCmds = append(fakeCmds, fakeCmd)
+		fakeExec.CommandScript = append(fakeExec.CommandScript, func(cmd string, args ...string) utilexec.Cmd {
+			argv = append(argv, append([]string{cmd}, args...))
+			return testingexec.InitFakeCmd(fakeCmd, cmd, args...)
+		})
+	}
+
+	if err := luksFormat(fakeExec, "/dev/topolvm/luks/1234", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := luksOpen(fakeExec, "/dev/topolvm/luks/1234", "topolvm-1234", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := luksClose(fakeExec, "topolvm-1234"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{cryptsetupCmd, "luksFormat", "--batch-mode", "--type", "luks2", "--offset", "32768", "--key-file", "-", "/dev/topolvm/luks/1234"},
+		{cryptsetupCmd, "open", "--type", "luks", "--key-file", "-", "/dev/topolvm/luks/1234", "topolvm-1234"},
+		{cryptsetupCmd, "close", "topolvm-1234"},
+	}
+	if !reflect.DeepEqual(argv, expected) {
+		t.Errorf("unexpected commands: %v", argv)
+	}
+	for i, expected := range []string{"secret", "secret", ""} {
+		var stdin []byte
+		if fakeCmds[i].Stdin != nil {
+			stdin, _ = io.ReadAll(fakeCmds[i].Stdin)
+		}
+		if string(stdin) != expected {
+			t.Errorf("unexpected stdin of %s: %q", argv[i][1], stdin)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package driver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilexec "k8s.io/utils/exec"
+)
+
+// Event reasons recorded on LogicalVolume by topolvm-node when it checks the filesystem.
+const (
+	EventReasonFilesystemChecked     = "FilesystemChecked"
+	EventReasonFilesystemRepaired    = "FilesystemRepaired"
+	EventReasonFilesystemCheckFailed = "FilesystemCheckFailed"
+)
+
+const (
+	e2fsckCmd    = "/sbin/e2fsck"
+	xfsRepairCmd = "/sbin/xfs_repair"
+	btrfsCmd     = "/bin/btrfs"
+
+	// fsckOutputLimit is the maximum length of the output of fsck included in an Event.
+	fsckOutputLimit = 512
+)
+
+// procStatPath is the path of /proc/stat, which is replaced in tests.
+var procStatPath = "/proc/stat"
+
+var errFsckUnsupported = errors.New("unsupported filesystem")
+
+type fsckResult int
+
+const (
+	fsckClean fsckResult = iota
+	fsckRepaired
+	fsckErrorsFound
+	// fsckLogReplayNeeded means that the metadata log of xfs needs to be replayed by mounting the filesystem.
+	fsckLogReplayNeeded
+)
+
+func (r fsckResult) String() string {
+	switch r {
+	case fsckClean:
+		return "clean"
+	case fsckRepaired:
+		return "repaired"
+	case fsckErrorsFound:
+		return "errors-found"
+	case fsckLogReplayNeeded:
+		return "log-replay-needed"
+	}
+	return strconv.Itoa(int(r))
+}
+
+// checkFilesystem checks the filesystem on device according to the fsck policy of the LogicalVolume
+// unless it has been checked since the node booted.
+// It returns an error if the filesystem should not be mounted.
+func (s *nodeServerNoLocked) checkFilesystem(ctx context.Context, volumeID, device, fsType string, readOnly bool, lvr *v1.LogicalVolume) error {
+	policy := lvr.Spec.FsckPolicy
+	if policy == "" || policy == v1.FsckNever {
+		return nil
+	}
+
+	boot, err := bootTime()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get boot time: %v", err)
+	}
+	if last := lvr.Status.LastFsckTime; last != nil && last.Time.After(boot) {
+		return nil
+	}
+	// A filesystem published as read-only must not be modified.
+	if readOnly && policy == v1.FsckAutoRepairSafe {
+		policy = v1.FsckCheckOnly
+	}
+
+	result, out, err := runFsck(s.mounter.Exec, fsType, device, policy)
+	if errors.Is(err, errFsckUnsupported) {
+		nodeLogger.Info("skipped filesystem check", "volume_id", volumeID, "fstype", fsType)
+		return nil
+	} else if err != nil {
+		s.recordEvent(lvr, corev1.EventTypeWarning, EventReasonFilesystemCheckFailed,
+			"failed to check %s filesystem: %v", fsType, err)
+		return status.Errorf(codes.Internal, "failed to check filesystem: volume=%s, error=%v", volumeID, err)
+	}
+
+	nodeLogger.Info("checked filesystem", "volume_id", volumeID, "fstype", fsType, "policy", policy, "result", result.String(), "output", out)
+	switch result {
+	case fsckClean:
+		s.recordEvent(lvr, corev1.EventTypeNormal, EventReasonFilesystemChecked,
+			"no errors were found on %s filesystem", fsType)
+	case fsckRepaired:
+		s.recordEvent(lvr, corev1.EventTypeWarning, EventReasonFilesystemRepaired,
+			"errors on %s filesystem were repaired: %s", fsType, truncateFsckOutput(out))
+	case fsckLogReplayNeeded:
+		s.recordEvent(lvr, corev1.EventTypeNormal, EventReasonFilesystemChecked,
+			"the log of %s filesystem is replayed by mounting it", fsType)
+	case fsckErrorsFound:
+		if policy != v1.FsckCheckOnly {
+			s.recordEvent(lvr, corev1.EventTypeWarning, EventReasonFilesystemCheckFailed,
+				"errors that cannot be repaired safely were found on %s filesystem: %s", fsType, truncateFsckOutput(out))
+			// The check time is not recorded so that the filesystem is checked again on the next attempt.
+			return status.Errorf(codes.FailedPrecondition, "filesystem has errors that cannot be repaired safely: volume=%s", volumeID)
+		}
+		s.recordEvent(lvr, corev1.EventTypeWarning, EventReasonFilesystemCheckFailed,
+			"errors were found on %s filesystem, but it is mounted because the policy is %s: %s", fsType, policy, truncateFsckOutput(out))
+	}
+
+	return s.k8sLVService.UpdateLastFsckTime(ctx, volumeID, time.Now())
+}
+
+func (s *nodeServerNoLocked) recordEvent(lv *v1.LogicalVolume, eventType, reason, messageFmt string, args ...interface{}) {
+	var obj runtime.Object = lv
+	if topolvm.UseLegacy() {
+		// Events must refer to the API group actually served for the LogicalVolume.
+		obj = &topolvmlegacyv1.LogicalVolume{ObjectMeta: lv.ObjectMeta}
+	}
+	s.recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// runFsck runs the checker for fsType on device according to policy.
+// It returns errFsckUnsupported if fsType has no checker.
+func runFsck(exec utilexec.Interface, fsType, device string, policy v1.FsckPolicy) (fsckResult, string, error) {
+	repair := policy == v1.FsckAutoRepairSafe
+
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+		// -p repairs only the problems that can be fixed safely without human intervention.
+		mode := "-n"
+		if repair {
+			mode = "-p"
+		}
+		out, code, err := runChecker(exec, e2fsckCmd, mode, device)
+		if err != nil {
+			return 0, out, err
+		}
+		switch {
+		case code == 0:
+			return fsckClean, out, nil
+		case code == 1 || code == 2:
+			return fsckRepaired, out, nil
+		case code == 4:
+			return fsckErrorsFound, out, nil
+		default:
+			return 0, out, fmt.Errorf("e2fsck exited with %d: %s", code, out)
+		}
+
+	case "xfs":
+		out, code, err := runChecker(exec, xfsRepairCmd, "-n", device)
+		if err != nil {
+			return 0, out, err
+		}
+		switch {
+		case code == 0:
+			return fsckClean, out, nil
+		case code != 1:
+			return 0, out, fmt.Errorf("xfs_repair exited with %d: %s", code, out)
+		case !repair:
+			return fsckErrorsFound, out, nil
+		}
+
+		// xfs_repair is never run with -L because zeroing the log may lose metadata.
+		out, code, err = runChecker(exec, xfsRepairCmd, device)
+		if err != nil {
+			return 0, out, err
+		}
+		switch code {
+		case 0:
+			return fsckRepaired, out, nil
+		case 2:
+			return fsckLogReplayNeeded, out, nil
+		default:
+			return fsckErrorsFound, out, nil
+		}
+
+	case "btrfs":
+		// btrfs check --repair is not safe, so btrfs is only checked.
+		out, code, err := runChecker(exec, btrfsCmd, "check", "--readonly", device)
+		if err != nil {
+			return 0, out, err
+		}
+		if code != 0 {
+			return fsckErrorsFound, out, nil
+		}
+		return fsckClean, out, nil
+	}
+
+	return 0, "", errFsckUnsupported
+}
+
+// runChecker runs a filesystem checker and returns its output and exit status.
+// An error is returned only if the command could not be run.
+func runChecker(exec utilexec.Interface, cmd string, args ...string) (string, int, error) {
+	out, err := exec.Command(cmd, args...).CombinedOutput()
+	if err == nil {
+		return string(out), 0, nil
+	}
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(out), exitErr.ExitStatus(), nil
+	}
+	return string(out), 0, fmt.Errorf("failed to run %s: %w", cmd, err)
+}
+
+// truncateFsckOutput returns the tail of out so that it fits in an Event.
+func truncateFsckOutput(out string) string {
+	out = strings.TrimSpace(out)
+	if len(out) > fsckOutputLimit {
+		out = "..." + out[len(out)-fsckOutputLimit:]
+	}
+	return out
+}
+
+// bootTime returns the time when the node booted.
+func bootTime() (time.Time, error) {
+	f, err := os.Open(procStatPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			sec, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(sec, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("btime not found in %s", procStatPath)
+}
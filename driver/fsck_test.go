@@ -0,0 +1,165 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/topolvm/topolvm/api/v1"
+	utilexec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+func TestRunFsck(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsType   string
+		policy   v1.FsckPolicy
+		statuses []int
+		commands [][]string
+		result   fsckResult
+		err      bool
+	}{
+		{
+			name:     "ext4 check-only clean",
+			fsType:   "ext4",
+			policy:   v1.FsckCheckOnly,
+			statuses: []int{0},
+			commands: [][]string{{e2fsckCmd, "-n", "/dev/test"}},
+			result:   fsckClean,
+		},
+		{
+			name:     "ext4 check-only errors",
+			fsType:   "ext4",
+			policy:   v1.FsckCheckOnly,
+			statuses: []int{4},
+			commands: [][]string{{e2fsckCmd, "-n", "/dev/test"}},
+			result:   fsckErrorsFound,
+		},
+		{
+			name:     "ext4 auto-repair-safe repaired",
+			fsType:   "ext4",
+			policy:   v1.FsckAutoRepairSafe,
+			statuses: []int{1},
+			commands: [][]string{{e2fsckCmd, "-p", "/dev/test"}},
+			result:   fsckRepaired,
+		},
+		{
+			name:     "ext4 operational error",
+			fsType:   "ext4",
+			policy:   v1.FsckAutoRepairSafe,
+			statuses: []int{8},
+			commands: [][]string{{e2fsckCmd, "-p", "/dev/test"}},
+			err:      true,
+		},
+		{
+			name:     "xfs check-only errors",
+			fsType:   "xfs",
+			policy:   v1.FsckCheckOnly,
+			statuses: []int{1},
+			commands: [][]string{{xfsRepairCmd, "-n", "/dev/test"}},
+			result:   fsckErrorsFound,
+		},
+		{
+			name:     "xfs auto-repair-safe repaired",
+			fsType:   "xfs",
+			policy:   v1.FsckAutoRepairSafe,
+			statuses: []int{1, 0},
+			commands: [][]string{{xfsRepairCmd, "-n", "/dev/test"}, {xfsRepairCmd, "/dev/test"}},
+			result:   fsckRepaired,
+		},
+		{
+			name:     "xfs auto-repair-safe dirty log",
+			fsType:   "xfs",
+			policy:   v1.FsckAutoRepairSafe,
+			statuses: []int{1, 2},
+			commands: [][]string{{xfsRepairCmd, "-n", "/dev/test"}, {xfsRepairCmd, "/dev/test"}},
+			result:   fsckLogReplayNeeded,
+		},
+		{
+			name:     "btrfs auto-repair-safe errors",
+			fsType:   "btrfs",
+			policy:   v1.FsckAutoRepairSafe,
+			statuses: []int{1},
+			commands: [][]string{{btrfsCmd, "check", "--readonly", "/dev/test"}},
+			result:   fsckErrorsFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		var commands [][]string
+		fakeExec := &testingexec.FakeExec{}
+		for _, code := range tc.statuses {
+			code := code
+			fakeCmd := &testingexec.FakeCmd{
+				CombinedOutputScript: []testingexec.FakeAction{
+					func() ([]byte, []byte, error) {
+						if code == 0 {
+							return []byte("ok"), nil, nil
+						}
+						return []byte("error"), nil, &testingexec.FakeExitError{Status: code}
+					},
+				},
+			}
+			fakeExec.CommandScript = append(fakeExec.CommandScript, func(cmd string, args ...string) utilexec.Cmd {
+				commands = append(commands, append([]string{cmd}, args...))
+				return testingexec.InitFakeCmd(fakeCmd, cmd, args...)
+			})
+		}
+
+		result, _, err := runFsck(fakeExec, tc.fsType, "/dev/test", tc.policy)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: should fail", tc.name)
+			}
+		} else if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		} else if result != tc.result {
+			t.Errorf("%s: expected %s, but got %s", tc.name, tc.result, result)
+		}
+		if !reflect.DeepEqual(commands, tc.commands) {
+			t.Errorf("%s: unexpected commands: %v", tc.name, commands)
+		}
+	}
+
+	_, _, err := runFsck(&testingexec.FakeExec{}, "vfat", "/dev/test", v1.FsckCheckOnly)
+	if err != errFsckUnsupported {
+		t.Errorf("unexpected error for unsupported filesystem: %v", err)
+	}
+}
+
+func TestTruncateFsckOutput(t *testing.T) {
+	out := truncateFsckOutput("\n" + strings.Repeat("a", fsckOutputLimit) + "b\n")
+	if len(out) != fsckOutputLimit+3 || !strings.HasPrefix(out, "...") || !strings.HasSuffix(out, "b") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestBootTime(t *testing.T) {
+	orig := procStatPath
+	defer func() { procStatPath = orig }()
+
+	procStatPath = filepath.Join(t.TempDir(), "stat")
+	err := os.WriteFile(procStatPath, []byte("cpu  1 2 3 4\nintr 1\nbtime 1700000000\nprocesses 10\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boot, err := bootTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !boot.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected boot time: %v", boot)
+	}
+
+	err = os.WriteFile(procStatPath, []byte("cpu  1 2 3 4\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bootTime(); err == nil {
+		t.Error("should fail without btime")
+	}
+}
@@ -0,0 +1,258 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/util/wait"
+	mountutil "k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	fstrimCmd = "/sbin/fstrim"
+
+	// fstrimJitterFactor is the maximum fraction of the interval added to it,
+	// so that volumes trimmed together drift apart over time.
+	fstrimJitterFactor = 0.1
+)
+
+var fstrimTrimmedBytesPattern = regexp.MustCompile(`\((\d+) bytes\)|(\d+) bytes were trimmed`)
+
+var (
+	fstrimTrimmedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "topolvm",
+		Subsystem: "fstrim",
+		Name:      "trimmed_bytes_total",
+		Help:      "The total number of bytes discarded by fstrim and returned to the thin pool",
+	}, []string{"node", "device_class"})
+
+	fstrimRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "topolvm",
+		Subsystem: "fstrim",
+		Name:      "runs_total",
+		Help:      "The total number of fstrim runs",
+	}, []string{"node", "device_class", "result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(fstrimTrimmedBytes)
+	metrics.Registry.MustRegister(fstrimRuns)
+}
+
+var fstrimLogger = ctrl.Log.WithName("driver").WithName("fstrim")
+
+type fstrimSchedule struct {
+	interval time.Duration
+	next     time.Time
+}
+
+type fsTrimmer struct {
+	client        client.Client
+	nodeName      string
+	vgService     proto.VGServiceClient
+	mounter       mountutil.Interface
+	exec          utilexec.Interface
+	checkInterval time.Duration
+	schedules     map[string]fstrimSchedule
+}
+
+var _ manager.LeaderElectionRunnable = &fsTrimmer{}
+
+// NewFSTrimmer returns a runnable that runs fstrim on the mounted filesystems of
+// thin logical volumes on the node according to spec.fstrimInterval of LogicalVolumes.
+// Whether any volume is due is checked every checkInterval.
+func NewFSTrimmer(client client.Client, nodeName string, conn *grpc.ClientConn, checkInterval time.Duration) manager.Runnable {
+	return &fsTrimmer{
+		client:        client,
+		nodeName:      nodeName,
+		vgService:     proto.NewVGServiceClient(conn),
+		mounter:       mountutil.New(""),
+		exec:          utilexec.New(),
+		checkInterval: checkInterval,
+		schedules:     make(map[string]fstrimSchedule),
+	}
+}
+
+// Start implements controller-runtime's manager.Runnable.
+func (t *fsTrimmer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(t.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := t.trim(ctx, time.Now()); err != nil {
+				fstrimLogger.Error(err, "failed to trim filesystems")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
+func (t *fsTrimmer) NeedLeaderElection() bool {
+	return false
+}
+
+func (t *fsTrimmer) trim(ctx context.Context, now time.Time) error {
+	thinVolumes, err := t.thinVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := t.client.List(ctx, lvList); err != nil {
+		return err
+	}
+	mounts, err := t.mounter.List()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{})
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		volumeID := lv.Status.VolumeID
+		if lv.Spec.NodeName != t.nodeName || volumeID == "" || lv.DeletionTimestamp != nil || lv.Spec.FSTrimInterval == nil {
+			continue
+		}
+		// Thick volumes do not benefit from fstrim.
+		deviceClass, ok := thinVolumes[lv.LVName()]
+		if !ok {
+			continue
+		}
+		seen[volumeID] = struct{}{}
+
+		interval := lv.Spec.FSTrimInterval.Duration
+		schedule, ok := t.schedules[volumeID]
+		if !ok || schedule.interval != interval {
+			// The first run is spread over the interval so that volumes are not trimmed at once,
+			// e.g. when topolvm-node restarts.
+			schedule = fstrimSchedule{
+				interval: interval,
+				next:     now.Add(time.Duration(rand.Int63n(int64(interval)))),
+			}
+			t.schedules[volumeID] = schedule
+		}
+		if now.Before(schedule.next) {
+			continue
+		}
+
+		// Volumes not mounted now, including block volumes, are trimmed once they are mounted.
+		mountPoint := findTrimMountPoint(mounts, filepath.Join(DeviceDirectory, volumeID))
+		if mountPoint == "" {
+			continue
+		}
+
+		trimmed, err := runFSTrim(t.exec, mountPoint)
+		if err != nil {
+			fstrimLogger.Error(err, "failed to run fstrim", "volume_id", volumeID, "mount_point", mountPoint)
+			fstrimRuns.WithLabelValues(t.nodeName, deviceClass, "failure").Inc()
+		} else {
+			fstrimLogger.Info("trimmed filesystem", "volume_id", volumeID, "mount_point", mountPoint, "trimmed_bytes", trimmed)
+			fstrimRuns.WithLabelValues(t.nodeName, deviceClass, "success").Inc()
+			fstrimTrimmedBytes.WithLabelValues(t.nodeName, deviceClass).Add(float64(trimmed))
+		}
+		schedule.next = now.Add(wait.Jitter(interval, fstrimJitterFactor))
+		t.schedules[volumeID] = schedule
+	}
+
+	for volumeID := range t.schedules {
+		if _, ok := seen[volumeID]; !ok {
+			delete(t.schedules, volumeID)
+		}
+	}
+	return nil
+}
+
+// thinVolumes returns the names of the thin logical volumes mapped to their device classes.
+func (t *fsTrimmer) thinVolumes(ctx context.Context) (map[string]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Watch sends the current status of the device classes at first.
+	wc, err := t.vgService.Watch(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	res, err := wc.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]string)
+	for _, item := range res.Items {
+		if item.ThinPool == nil {
+			continue
+		}
+		resp, err := t.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: item.DeviceClass})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Volumes {
+			volumes[v.Name] = item.DeviceClass
+		}
+	}
+	return volumes, nil
+}
+
+// findTrimMountPoint returns a writable mount point of device, or an empty string if not found.
+// A filesystem mounted at multiple paths needs to be trimmed only once.
+func findTrimMountPoint(mounts []mountutil.MountPoint, device string) string {
+	for _, m := range mounts {
+		if m.Device != device {
+			continue
+		}
+		readOnly := false
+		for _, opt := range m.Opts {
+			if opt == "ro" {
+				readOnly = true
+				break
+			}
+		}
+		if !readOnly {
+			return m.Path
+		}
+	}
+	return ""
+}
+
+// runFSTrim runs fstrim on the filesystem mounted at mountPoint and returns the number of trimmed bytes.
+func runFSTrim(exec utilexec.Interface, mountPoint string) (uint64, error) {
+	out, err := exec.Command(fstrimCmd, "-v", mountPoint).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("fstrim failed: output=%s, error=%v", string(out), err)
+	}
+	return parseFSTrimOutput(string(out))
+}
+
+// parseFSTrimOutput parses the output of fstrim -v. The format differs between versions of util-linux:
+//
+//	/mnt: 1 GiB (1073741824 bytes) trimmed
+//	/mnt: 1073741824 bytes were trimmed
+func parseFSTrimOutput(out string) (uint64, error) {
+	m := fstrimTrimmedBytesPattern.FindStringSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("unexpected output of fstrim: %q", out)
+	}
+	bytes := m[1]
+	if bytes == "" {
+		bytes = m[2]
+	}
+	return strconv.ParseUint(bytes, 10, 64)
+}
@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	mountutil "k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+func TestParseFSTrimOutput(t *testing.T) {
+	for out, expected := range map[string]uint64{
+		"/mnt: 1 GiB (1073741824 bytes) trimmed\n": 1 << 30,
+		"/mnt: 0 B (0 bytes) trimmed\n":            0,
+		"/mnt: 4096 bytes were trimmed\n":          4096,
+	} {
+		trimmed, err := parseFSTrimOutput(out)
+		if err != nil {
+			t.Errorf("%q: %v", out, err)
+			continue
+		}
+		if trimmed != expected {
+			t.Errorf("%q: unexpected bytes: %d", out, trimmed)
+		}
+	}
+
+	if _, err := parseFSTrimOutput("fstrim: /mnt: the discard operation is not supported\n"); err == nil {
+		t.Error("should be error")
+	}
+}
+
+func TestFindTrimMountPoint(t *testing.T) {
+	mounts := []mountutil.MountPoint{
+		{Device: "/dev/topolvm/vol1", Path: "/var/lib/kubelet/pods/a/volumes/kubernetes.io~csi/pv1/mount", Opts: []string{"ro", "relatime"}},
+		{Device: "/dev/topolvm/vol1", Path: "/var/lib/kubelet/pods/b/volumes/kubernetes.io~csi/pv1/mount", Opts: []string{"rw", "relatime"}},
+		{Device: "/dev/topolvm/vol2", Path: "/var/lib/kubelet/pods/c/volumes/kubernetes.io~csi/pv2/mount", Opts: []string{"ro"}},
+	}
+
+	if p := findTrimMountPoint(mounts, "/dev/topolvm/vol1"); p != mounts[1].Path {
+		t.Errorf("unexpected mount point: %s", p)
+	}
+	if p := findTrimMountPoint(mounts, "/dev/topolvm/vol2"); p != "" {
+		t.Errorf("read-only mount point should not be found: %s", p)
+	}
+	if p := findTrimMountPoint(mounts, "/dev/topolvm/vol3"); p != "" {
+		t.Errorf("unexpected mount point: %s", p)
+	}
+}
+
+func TestRunFSTrim(t *testing.T) {
+	var argv []string
+	fakeCmd := &testingexec.FakeCmd{
+		CombinedOutputScript: []testingexec.FakeAction{
+			func() ([]byte, []byte, error) { return []byte("/mnt: 2 MiB (2097152 bytes) trimmed\n"), nil, nil },
+		},
+	}
+	fakeExec := &testingexec.FakeExec{
+		CommandScript: []testingexec.FakeCommandAction{
+			func(cmd string, args ...string) utilexec.Cmd {
+				argv = append([]string{cmd}, args...)
+				return testingexec.InitFakeCmd(fakeCmd, cmd, args...)
+			},
+		},
+	}
+
+	trimmed, err := runFSTrim(fakeExec, "/mnt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trimmed != 2<<20 {
+		t.Errorf("unexpected bytes: %d", trimmed)
+	}
+	if !reflect.DeepEqual(argv, []string{fstrimCmd, "-v", "/mnt"}) {
+		t.Errorf("unexpected command: %v", argv)
+	}
+}
@@ -0,0 +1,259 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/driver/internal/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var groupCtrlLogger = ctrl.Log.WithName("driver").WithName("group-controller")
+
+// This is a wrapper for groupControllerServerNoLocked to protect concurrent method call.
+type groupControllerServer struct {
+	csi.UnimplementedGroupControllerServer
+
+	// This protects server methods using a group snapshot name or id.
+	// It is shared with controllerServer because the ids of group snapshots are their names.
+	lockByName *LockByID
+	server     *groupControllerServerNoLocked
+}
+
+func (s *groupControllerServer) GroupControllerGetCapabilities(ctx context.Context, req *csi.GroupControllerGetCapabilitiesRequest) (*csi.GroupControllerGetCapabilitiesResponse, error) {
+	// This returns constants only, it is unnecessary to take lock.
+	return s.server.GroupControllerGetCapabilities(ctx, req)
+}
+
+func (s *groupControllerServer) CreateVolumeGroupSnapshot(ctx context.Context, req *csi.CreateVolumeGroupSnapshotRequest) (*csi.CreateVolumeGroupSnapshotResponse, error) {
+	name := strings.ToLower(req.GetName())
+	s.lockByName.LockByID(name)
+	defer s.lockByName.UnlockByID(name)
+
+	return s.server.CreateVolumeGroupSnapshot(ctx, req)
+}
+
+func (s *groupControllerServer) DeleteVolumeGroupSnapshot(ctx context.Context, req *csi.DeleteVolumeGroupSnapshotRequest) (*csi.DeleteVolumeGroupSnapshotResponse, error) {
+	s.lockByName.LockByID(req.GetGroupSnapshotId())
+	defer s.lockByName.UnlockByID(req.GetGroupSnapshotId())
+
+	return s.server.DeleteVolumeGroupSnapshot(ctx, req)
+}
+
+func (s *groupControllerServer) GetVolumeGroupSnapshot(ctx context.Context, req *csi.GetVolumeGroupSnapshotRequest) (*csi.GetVolumeGroupSnapshotResponse, error) {
+	s.lockByName.LockByID(req.GetGroupSnapshotId())
+	defer s.lockByName.UnlockByID(req.GetGroupSnapshotId())
+
+	return s.server.GetVolumeGroupSnapshot(ctx, req)
+}
+
+// groupControllerServerNoLocked implements csi.GroupControllerServer.
+// It does not take any lock, gRPC calls may be interleaved.
+// Therefore, must not use it directly.
+type groupControllerServerNoLocked struct {
+	csi.UnimplementedGroupControllerServer
+
+	lvService *k8s.LogicalVolumeService
+}
+
+func (s groupControllerServerNoLocked) GroupControllerGetCapabilities(context.Context, *csi.GroupControllerGetCapabilitiesRequest) (*csi.GroupControllerGetCapabilitiesResponse, error) {
+	return &csi.GroupControllerGetCapabilitiesResponse{
+		Capabilities: []*csi.GroupControllerServiceCapability{
+			{
+				Type: &csi.GroupControllerServiceCapability_Rpc{
+					Rpc: &csi.GroupControllerServiceCapability_RPC{
+						Type: csi.GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// CreateVolumeGroupSnapshot creates snapshots of volumes on the same node and device class at the same point in time.
+// The id of the group snapshot is its name, and the snapshots are named after the group snapshot and their source volumes.
+func (s groupControllerServerNoLocked) CreateVolumeGroupSnapshot(ctx context.Context, req *csi.CreateVolumeGroupSnapshotRequest) (*csi.CreateVolumeGroupSnapshotResponse, error) {
+	groupCtrlLogger.Info("CreateVolumeGroupSnapshot called",
+		"name", req.GetName(),
+		"source_volume_ids", req.GetSourceVolumeIds(),
+		"parameters", req.GetParameters(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing name")
+	}
+	if len(req.GetSourceVolumeIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing source volume ids")
+	}
+
+	name := strings.ToLower(req.GetName())
+	sourceVols := make([]*v1.LogicalVolume, 0, len(req.GetSourceVolumeIds()))
+	names := make([]string, 0, len(req.GetSourceVolumeIds()))
+	seen := make(map[string]struct{})
+	for _, sourceVolID := range req.GetSourceVolumeIds() {
+		if _, ok := seen[sourceVolID]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "source volume %s is specified more than once", sourceVolID)
+		}
+		seen[sourceVolID] = struct{}{}
+
+		sourceVol, err := s.lvService.GetVolume(ctx, sourceVolID)
+		if err != nil {
+			if errors.Is(err, k8s.ErrVolumeNotFound) {
+				return nil, status.Errorf(codes.NotFound, "failed to find source volume %s", sourceVolID)
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		// the snapshots are taken together by lvmd, so the source volumes must be on the same node and device class.
+		if len(sourceVols) > 0 && (sourceVol.Spec.NodeName != sourceVols[0].Spec.NodeName || sourceVol.Spec.DeviceClass != sourceVols[0].Spec.DeviceClass) {
+			return nil, status.Error(codes.InvalidArgument, "source volumes must be on the same node and device class")
+		}
+		sourceVols = append(sourceVols, sourceVol)
+		names = append(names, groupSnapshotMemberName(name, sourceVolID))
+	}
+
+	if _, err := s.lvService.CreateGroupSnapshot(ctx, name, sourceVols, names); err != nil {
+		_, ok := status.FromError(err)
+		if !ok {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, err
+	}
+
+	groupSnapshot, err := s.getVolumeGroupSnapshot(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &csi.CreateVolumeGroupSnapshotResponse{
+		GroupSnapshot: groupSnapshot,
+	}, nil
+}
+
+// DeleteVolumeGroupSnapshot deletes all the snapshots in the group snapshot.
+func (s groupControllerServerNoLocked) DeleteVolumeGroupSnapshot(ctx context.Context, req *csi.DeleteVolumeGroupSnapshotRequest) (*csi.DeleteVolumeGroupSnapshotResponse, error) {
+	groupCtrlLogger.Info("DeleteVolumeGroupSnapshot called",
+		"group_snapshot_id", req.GetGroupSnapshotId(),
+		"snapshot_ids", req.GetSnapshotIds(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetGroupSnapshotId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing group snapshot id")
+	}
+
+	snapshots, err := s.lvService.ListGroupSnapshot(ctx, req.GetGroupSnapshotId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for _, snapshotID := range req.GetSnapshotIds() {
+		if !containsGroupSnapshotMember(snapshots, snapshotID) {
+			return nil, status.Errorf(codes.InvalidArgument, "snapshot %s does not belong to group snapshot %s", snapshotID, req.GetGroupSnapshotId())
+		}
+	}
+
+	for _, snapshot := range snapshots {
+		// The snapshots are not left without volume IDs because CreateVolumeGroupSnapshot deletes all of them on failure.
+		if snapshot.Status.VolumeID == "" {
+			continue
+		}
+		if err := s.lvService.DeleteVolume(ctx, snapshot.Status.VolumeID); err != nil {
+			groupCtrlLogger.Error(err, "DeleteVolumeGroupSnapshot failed", "snapshot_id", snapshot.Status.VolumeID)
+			_, ok := status.FromError(err)
+			if !ok {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			return nil, err
+		}
+	}
+
+	return &csi.DeleteVolumeGroupSnapshotResponse{}, nil
+}
+
+// GetVolumeGroupSnapshot returns the group snapshot.
+func (s groupControllerServerNoLocked) GetVolumeGroupSnapshot(ctx context.Context, req *csi.GetVolumeGroupSnapshotRequest) (*csi.GetVolumeGroupSnapshotResponse, error) {
+	groupCtrlLogger.Info("GetVolumeGroupSnapshot called",
+		"group_snapshot_id", req.GetGroupSnapshotId(),
+		"snapshot_ids", req.GetSnapshotIds(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetGroupSnapshotId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing group snapshot id")
+	}
+
+	groupSnapshot, err := s.getVolumeGroupSnapshot(ctx, req.GetGroupSnapshotId())
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshotID := range req.GetSnapshotIds() {
+		found := false
+		for _, snapshot := range groupSnapshot.Snapshots {
+			if snapshot.SnapshotId == snapshotID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "snapshot %s does not belong to group snapshot %s", snapshotID, req.GetGroupSnapshotId())
+		}
+	}
+
+	return &csi.GetVolumeGroupSnapshotResponse{
+		GroupSnapshot: groupSnapshot,
+	}, nil
+}
+
+func (s groupControllerServerNoLocked) getVolumeGroupSnapshot(ctx context.Context, groupSnapshotID string) (*csi.VolumeGroupSnapshot, error) {
+	snapshots, err := s.lvService.ListGroupSnapshot(ctx, groupSnapshotID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(snapshots) == 0 {
+		return nil, status.Errorf(codes.NotFound, "group snapshot %s is not found", groupSnapshotID)
+	}
+
+	groupSnapshot := &csi.VolumeGroupSnapshot{
+		GroupSnapshotId: groupSnapshotID,
+		ReadyToUse:      true,
+	}
+	for _, snapshot := range snapshots {
+		creationTime := &timestamp.Timestamp{
+			Seconds: snapshot.CreationTimestamp.Unix(),
+		}
+		if groupSnapshot.CreationTime == nil || creationTime.Seconds < groupSnapshot.CreationTime.Seconds {
+			groupSnapshot.CreationTime = creationTime
+		}
+		ready := snapshot.Status.VolumeID != ""
+		if !ready {
+			groupSnapshot.ReadyToUse = false
+		}
+		groupSnapshot.Snapshots = append(groupSnapshot.Snapshots, &csi.Snapshot{
+			SnapshotId:      snapshot.Status.VolumeID,
+			SourceVolumeId:  strings.TrimPrefix(snapshot.Name, groupSnapshotMemberName(groupSnapshotID, "")),
+			SizeBytes:       snapshot.Spec.Size.Value(),
+			CreationTime:    creationTime,
+			ReadyToUse:      ready,
+			GroupSnapshotId: groupSnapshotID,
+		})
+	}
+	return groupSnapshot, nil
+}
+
+// groupSnapshotMemberName returns the name of the LogicalVolume of the snapshot of sourceVolID in the group snapshot.
+// The source volume ID of a snapshot is retrieved from the name.
+func groupSnapshotMemberName(groupSnapshotID, sourceVolID string) string {
+	return fmt.Sprintf("%s-%s", groupSnapshotID, sourceVolID)
+}
+
+func containsGroupSnapshotMember(snapshots []*v1.LogicalVolume, snapshotID string) bool {
+	for _, snapshot := range snapshots {
+		if snapshot.Status.VolumeID == snapshotID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGroupControllerValidation(t *testing.T) {
+	s := groupControllerServerNoLocked{}
+	ctx := context.Background()
+
+	_, err := s.CreateVolumeGroupSnapshot(ctx, &csi.CreateVolumeGroupSnapshotRequest{SourceVolumeIds: []string{"vol1"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing name should be rejected: %v", err)
+	}
+	_, err = s.CreateVolumeGroupSnapshot(ctx, &csi.CreateVolumeGroupSnapshotRequest{Name: "group"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing source volumes should be rejected: %v", err)
+	}
+	_, err = s.DeleteVolumeGroupSnapshot(ctx, &csi.DeleteVolumeGroupSnapshotRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing group snapshot id should be rejected: %v", err)
+	}
+	_, err = s.GetVolumeGroupSnapshot(ctx, &csi.GetVolumeGroupSnapshotRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing group snapshot id should be rejected: %v", err)
+	}
+}
+
+func TestGroupSnapshotMemberName(t *testing.T) {
+	name := groupSnapshotMemberName("groupsnapshot-1", "7a6f4f5e-0d1c-4b8e-9f3a-2c1d0e9b8a7f")
+	if name != "groupsnapshot-1-7a6f4f5e-0d1c-4b8e-9f3a-2c1d0e9b8a7f" {
+		t.Errorf("unexpected name: %s", name)
+	}
+	if sourceVolID := strings.TrimPrefix(name, groupSnapshotMemberName("groupsnapshot-1", "")); sourceVolID != "7a6f4f5e-0d1c-4b8e-9f3a-2c1d0e9b8a7f" {
+		t.Errorf("unexpected source volume id: %s", sourceVolID)
+	}
+}
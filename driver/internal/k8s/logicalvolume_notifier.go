@@ -0,0 +1,164 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// resyncInterval is the fallback interval to re-check a LogicalVolume while waiting.
+// Waiters are normally woken up by informer events, so this only matters when an event is missed.
+const resyncInterval = 10 * time.Second
+
+// observedStatus is the part of LogicalVolume that waiters are interested in.
+type observedStatus struct {
+	volumeID       string
+	code           codes.Code
+	currentSize    string
+	createAttempts int32
+	retryPending   bool
+	readAhead      string
+	tags           string
+	// failedGeneration is the generation at which the last operation failed.
+	failedGeneration int64
+}
+
+// logicalVolumeNotifier wakes up goroutines waiting for the status of a LogicalVolume to change.
+// It is fed by a shared informer, so waiting for many volumes does not put load on the cache or kube-apiserver.
+type logicalVolumeNotifier struct {
+	mu      sync.Mutex
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+func newLogicalVolumeNotifier() *logicalVolumeNotifier {
+	return &logicalVolumeNotifier{
+		waiters: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// subscribe registers a waiter for the LogicalVolume named name.
+// The returned function must be called to unregister the waiter.
+func (n *logicalVolumeNotifier) subscribe(name string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.waiters[name] == nil {
+		n.waiters[name] = make(map[chan struct{}]struct{})
+	}
+	n.waiters[name][ch] = struct{}{}
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.waiters[name], ch)
+		if len(n.waiters[name]) == 0 {
+			delete(n.waiters, name)
+		}
+	}
+}
+
+// notify wakes up all waiters for the LogicalVolume named name.
+func (n *logicalVolumeNotifier) notify(name string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.waiters[name] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// the waiter has a pending notification already.
+		}
+	}
+}
+
+// wait blocks until ch is notified, ctx is done or resyncInterval elapses.
+// It returns DeadlineExceeded if the deadline of ctx has passed.
+func (n *logicalVolumeNotifier) wait(ctx context.Context, ch <-chan struct{}) error {
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return status.Error(codes.DeadlineExceeded, "timed out waiting for topolvm-node to update LogicalVolume")
+		}
+		return ctx.Err()
+	case <-ch:
+	case <-time.After(resyncInterval):
+	}
+	return nil
+}
+
+// eventHandler returns the handler to be registered to the LogicalVolume informer.
+func (n *logicalVolumeNotifier) eventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if name, _, ok := observe(obj); ok {
+				n.notify(name)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			name, newStatus, ok := observe(newObj)
+			if !ok {
+				return
+			}
+			if _, oldStatus, ok := observe(oldObj); ok && oldStatus == newStatus {
+				return
+			}
+			n.notify(name)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if d, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = d.Obj
+			}
+			if name, _, ok := observe(obj); ok {
+				n.notify(name)
+			}
+		},
+	}
+}
+
+func observe(obj interface{}) (string, observedStatus, bool) {
+	switch lv := obj.(type) {
+	case *topolvmv1.LogicalVolume:
+		st := observedStatus{
+			volumeID:       lv.Status.VolumeID,
+			code:           lv.Status.Code,
+			createAttempts: lv.Status.CreateAttempts,
+			retryPending:   lv.Status.NextRetryTime != nil,
+			readAhead:      lv.Status.ReadAhead,
+			tags:           strings.Join(lv.Status.Tags, ","),
+		}
+		if lv.Status.CurrentSize != nil {
+			st.currentSize = lv.Status.CurrentSize.String()
+		}
+		if cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmv1.LogicalVolumeFailed); cond != nil && cond.Status == metav1.ConditionTrue {
+			st.failedGeneration = cond.ObservedGeneration
+		}
+		return lv.Name, st, true
+	case *topolvmlegacyv1.LogicalVolume:
+		st := observedStatus{
+			volumeID:       lv.Status.VolumeID,
+			code:           lv.Status.Code,
+			createAttempts: lv.Status.CreateAttempts,
+			retryPending:   lv.Status.NextRetryTime != nil,
+			readAhead:      lv.Status.ReadAhead,
+			tags:           strings.Join(lv.Status.Tags, ","),
+		}
+		if lv.Status.CurrentSize != nil {
+			st.currentSize = lv.Status.CurrentSize.String()
+		}
+		if cond := meta.FindStatusCondition(lv.Status.Conditions, topolvmlegacyv1.LogicalVolumeFailed); cond != nil && cond.Status == metav1.ConditionTrue {
+			st.failedGeneration = cond.ObservedGeneration
+		}
+		return lv.Name, st, true
+	}
+	return "", observedStatus{}, false
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+func isNotified(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	case <-time.After(100 * time.Millisecond):
+		return false
+	}
+}
+
+func TestNotifyWakesOnlyWaitersOfTheVolume(t *testing.T) {
+	n := newLogicalVolumeNotifier()
+
+	chA, unsubscribeA := n.subscribe("a")
+	defer unsubscribeA()
+	chB, unsubscribeB := n.subscribe("b")
+	defer unsubscribeB()
+
+	n.notify("a")
+	if !isNotified(chA) {
+		t.Error("waiter for a was not notified")
+	}
+	if isNotified(chB) {
+		t.Error("waiter for b was notified")
+	}
+
+	// notify must not block even if the waiter does not consume notifications.
+	n.notify("b")
+	n.notify("b")
+	if !isNotified(chB) {
+		t.Error("waiter for b was not notified")
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	n := newLogicalVolumeNotifier()
+
+	ch, unsubscribe := n.subscribe("a")
+	unsubscribe()
+	if len(n.waiters) != 0 {
+		t.Errorf("waiters are left after unsubscribe: %v", n.waiters)
+	}
+
+	n.notify("a")
+	if isNotified(ch) {
+		t.Error("unsubscribed waiter was notified")
+	}
+}
+
+func TestEventHandlerFiltersUpdates(t *testing.T) {
+	n := newLogicalVolumeNotifier()
+	h := n.eventHandler()
+
+	ch, unsubscribe := n.subscribe("a")
+	defer unsubscribe()
+
+	lv := &topolvmv1.LogicalVolume{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+
+	changed := lv.DeepCopy()
+	changed.Annotations = map[string]string{"foo": "bar"}
+	h.OnUpdate(lv, changed)
+	if isNotified(ch) {
+		t.Error("notified for an update not related to status")
+	}
+
+	for _, mutate := range []func(*topolvmv1.LogicalVolume){
+		func(lv *topolvmv1.LogicalVolume) { lv.Status.VolumeID = "volume" },
+		func(lv *topolvmv1.LogicalVolume) { lv.Status.Code = codes.Internal },
+		func(lv *topolvmv1.LogicalVolume) { lv.Status.CurrentSize = resource.NewQuantity(1<<30, resource.BinarySI) },
+		func(lv *topolvmv1.LogicalVolume) { lv.Status.CreateAttempts = 1 },
+		func(lv *topolvmv1.LogicalVolume) { lv.Status.NextRetryTime = &metav1.Time{Time: time.Now()} },
+	} {
+		changed := lv.DeepCopy()
+		mutate(changed)
+		h.OnUpdate(lv, changed)
+		if !isNotified(ch) {
+			t.Errorf("not notified for status update: %v", changed.Status)
+		}
+	}
+
+	h.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "a", Obj: lv})
+	if !isNotified(ch) {
+		t.Error("not notified for deletion")
+	}
+}
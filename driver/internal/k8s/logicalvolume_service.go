@@ -14,6 +14,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,10 +34,47 @@ type LogicalVolumeService struct {
 	}
 	getter       getter.Interface
 	volumeGetter *volumeGetter
+	notifier     *logicalVolumeNotifier
+	// maxWait is the upper limit of each wait for topolvm-node.
+	maxWait time.Duration
+}
+
+// VolumeOwner represents the Kubernetes objects that a volume is provisioned for.
+// The fields are empty unless external-provisioner runs with --extra-create-metadata.
+type VolumeOwner struct {
+	PVCName      string
+	PVCNamespace string
+	PVName       string
+}
+
+// VolumeAttributes represents the attributes of a volume that can be modified after creation.
+type VolumeAttributes struct {
+	IOLimits       *topolvmv1.IOLimits
+	ReadAhead      string
+	Tags           []string
+	FSTrimInterval *metav1.Duration
+}
+
+// VolumeFilesystem represents the filesystem created on a volume. It cannot be modified after creation.
+type VolumeFilesystem struct {
+	FsType      string
+	MkfsOptions []string
+	FsckPolicy  topolvmv1.FsckPolicy
 }
 
 const (
 	indexFieldVolumeID = "status.volumeID"
+
+	// filesystemUsageResyncPeriod is the period to update the filesystem usage even if it has not changed much.
+	filesystemUsageResyncPeriod = 10 * time.Minute
+
+	// defaultMaxWait is the upper limit of each wait for topolvm-node to update a LogicalVolume.
+	// It applies when the request has no deadline or a later one.
+	defaultMaxWait = 10 * time.Minute
+
+	// waitDeadlineMargin is left before the deadline of the request,
+	// so that the timeout of a wait is returned to the caller as DeadlineExceeded.
+	waitDeadlineMargin = time.Second
 )
 
 var (
@@ -118,12 +156,31 @@ func (v *volumeGetter) Get(ctx context.Context, volumeID string) (*topolvmv1.Log
 	return foundLv, nil
 }
 
+// ListGroupSnapshot returns LogicalVolumes of the snapshots in the group snapshot.
+// This reads the API server directly so that the snapshots just created are not missed.
+func (v *volumeGetter) ListGroupSnapshot(ctx context.Context, groupName string) ([]*topolvmv1.LogicalVolume, error) {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := v.apiReader.List(ctx, lvList); err != nil {
+		return nil, err
+	}
+
+	var snapshots []*topolvmv1.LogicalVolume
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		if lv.Spec.GroupSnapshot != nil && lv.Spec.GroupSnapshot.Name == groupName {
+			snapshots = append(snapshots, lv)
+		}
+	}
+	return snapshots, nil
+}
+
 //+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // NewLogicalVolumeService returns LogicalVolumeService.
 func NewLogicalVolumeService(mgr manager.Manager) (*LogicalVolumeService, error) {
 	ctx := context.Background()
+	var obj client.Object
 	if topolvm.UseLegacy() {
 		err := mgr.GetFieldIndexer().IndexField(ctx, &topolvmlegacyv1.LogicalVolume{}, indexFieldVolumeID, func(o client.Object) []string {
 			return []string{o.(*topolvmlegacyv1.LogicalVolume).Status.VolumeID}
@@ -131,6 +188,7 @@ func NewLogicalVolumeService(mgr manager.Manager) (*LogicalVolumeService, error)
 		if err != nil {
 			return nil, err
 		}
+		obj = &topolvmlegacyv1.LogicalVolume{}
 	} else {
 		err := mgr.GetFieldIndexer().IndexField(ctx, &topolvmv1.LogicalVolume{}, indexFieldVolumeID, func(o client.Object) []string {
 			return []string{o.(*topolvmv1.LogicalVolume).Status.VolumeID}
@@ -138,6 +196,18 @@ func NewLogicalVolumeService(mgr manager.Manager) (*LogicalVolumeService, error)
 		if err != nil {
 			return nil, err
 		}
+		obj = &topolvmv1.LogicalVolume{}
+	}
+
+	// Waiters for LogicalVolume status are woken up by the shared informer
+	// instead of polling the cache.
+	notifier := newLogicalVolumeNotifier()
+	informer, err := mgr.GetCache().GetInformer(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := informer.AddEventHandler(notifier.eventHandler()); err != nil {
+		return nil, err
 	}
 
 	client := clientwrapper.NewWrappedClient(mgr.GetClient())
@@ -146,12 +216,15 @@ func NewLogicalVolumeService(mgr manager.Manager) (*LogicalVolumeService, error)
 		writer:       client,
 		getter:       newRetryMissingGetter(client, apiReader),
 		volumeGetter: &volumeGetter{cacheReader: client, apiReader: apiReader},
+		notifier:     notifier,
+		maxWait:      defaultMaxWait,
 	}, nil
 }
 
-// CreateVolume creates volume
-func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, name, sourceName string, requestGb int64) (string, error) {
-	logger.Info("k8s.CreateVolume called", "name", name, "node", node, "size_gb", requestGb, "sourceName", sourceName)
+// CreateVolume creates volume, and returns its ID and its actual size in bytes.
+func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, name, sourceName string, requestBytes int64, owner VolumeOwner, attrs VolumeAttributes, encrypted bool, filesystem VolumeFilesystem) (string, int64, error) {
+	logger.Info("k8s.CreateVolume called", "name", name, "node", node, "size", requestBytes, "sourceName", sourceName,
+		"pvc_name", owner.PVCName, "pvc_namespace", owner.PVCNamespace, "pv_name", owner.PVName, "encrypted", encrypted, "fs_type", filesystem.FsType, "mkfs_options", filesystem.MkfsOptions, "fsck_policy", filesystem.FsckPolicy)
 	var lv *topolvmv1.LogicalVolume
 	// if the create volume request has no source, proceed with regular lv creation.
 	if sourceName == "" {
@@ -164,7 +237,18 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 				NodeName:            node,
 				DeviceClass:         dc,
 				LvcreateOptionClass: oc,
-				Size:                *resource.NewQuantity(requestGb<<30, resource.BinarySI),
+				Size:                *resource.NewQuantity(requestBytes, resource.BinarySI),
+				PVCName:             owner.PVCName,
+				PVCNamespace:        owner.PVCNamespace,
+				PVName:              owner.PVName,
+				IOLimits:            attrs.IOLimits,
+				ReadAhead:           attrs.ReadAhead,
+				Tags:                attrs.Tags,
+				Encrypted:           encrypted,
+				FSTrimInterval:      attrs.FSTrimInterval,
+				FsType:              filesystem.FsType,
+				MkfsOptions:         filesystem.MkfsOptions,
+				FsckPolicy:          filesystem.FsckPolicy,
 			},
 		}
 
@@ -179,9 +263,20 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 				NodeName:            node,
 				DeviceClass:         dc,
 				LvcreateOptionClass: oc,
-				Size:                *resource.NewQuantity(requestGb<<30, resource.BinarySI),
+				Size:                *resource.NewQuantity(requestBytes, resource.BinarySI),
 				Source:              sourceName,
 				AccessType:          "rw",
+				PVCName:             owner.PVCName,
+				PVCNamespace:        owner.PVCNamespace,
+				PVName:              owner.PVName,
+				IOLimits:            attrs.IOLimits,
+				ReadAhead:           attrs.ReadAhead,
+				Tags:                attrs.Tags,
+				Encrypted:           encrypted,
+				FSTrimInterval:      attrs.FSTrimInterval,
+				FsType:              filesystem.FsType,
+				MkfsOptions:         filesystem.MkfsOptions,
+				FsckPolicy:          filesystem.FsckPolicy,
 			},
 		}
 	}
@@ -190,12 +285,12 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 	err := s.getter.Get(ctx, client.ObjectKey{Name: name}, existingLV)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
-			return "", err
+			return "", 0, err
 		}
 
 		err := s.writer.Create(ctx, lv)
 		if err != nil {
-			return "", err
+			return "", 0, err
 		}
 		logger.Info("created LogicalVolume CR", "name", name, "sourceID", lv.Spec.Source)
 	} else {
@@ -203,16 +298,20 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 		// skip check of capabilities because (1) we allow both of two access types, and (2) we allow only one access mode
 		// for ease of comparison, sizes are compared strictly, not by compatibility of ranges
 		if !existingLV.IsCompatibleWith(lv) {
-			return "", status.Error(codes.AlreadyExists, "Incompatible LogicalVolume already exists")
+			return "", 0, status.Error(codes.AlreadyExists, "Incompatible LogicalVolume already exists")
+		}
+		// A released LogicalVolume is retained for its former claim and must not be reused by another one.
+		if meta.IsStatusConditionTrue(existingLV.Status.Conditions, topolvmv1.LogicalVolumeReleased) {
+			return "", 0, status.Errorf(codes.AlreadyExists, "LogicalVolume %s is retained by released PersistentVolume %s", name, existingLV.Spec.PVName)
 		}
 		// compatible LV was found
 	}
-	volumeID, err := s.waitForStatusUpdate(ctx, name)
+	newLV, err := s.waitForStatusUpdate(ctx, name, operationCreate)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 
-	return volumeID, nil
+	return newLV.Status.VolumeID, currentBytes(newLV), nil
 }
 
 // DeleteVolume deletes volume
@@ -228,6 +327,9 @@ func (s *LogicalVolumeService) DeleteVolume(ctx context.Context, volumeID string
 		return err
 	}
 
+	ch, unsubscribe := s.notifier.subscribe(lv.Name)
+	defer unsubscribe()
+
 	err = s.writer.Delete(ctx, lv)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -237,39 +339,48 @@ func (s *LogicalVolumeService) DeleteVolume(ctx context.Context, volumeID string
 	}
 
 	// wait until delete the target volume
-	for {
-		logger.Info("waiting for delete LogicalVolume", "name", lv.Name)
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(100 * time.Millisecond):
-		}
+	ctx, cancel := s.waitContext(ctx)
+	defer cancel()
+	start := time.Now()
+	err = func() error {
+		for {
+			err := s.getter.Get(ctx, client.ObjectKey{Name: lv.Name}, new(topolvmv1.LogicalVolume))
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				logger.Error(err, "failed to get LogicalVolume", "name", lv.Name)
+				return err
+			}
 
-		err := s.getter.Get(ctx, client.ObjectKey{Name: lv.Name}, new(topolvmv1.LogicalVolume))
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return nil
+			logger.Info("waiting for delete LogicalVolume", "name", lv.Name)
+			if err := s.notifier.wait(ctx, ch); err != nil {
+				return err
 			}
-			logger.Error(err, "failed to get LogicalVolume", "name", lv.Name)
-			return err
 		}
-	}
+	}()
+	observeWaitDuration(operationDelete, start, err)
+	return err
 }
 
 // CreateSnapshot creates a snapshot of existing volume.
-func (s *LogicalVolumeService) CreateSnapshot(ctx context.Context, node, dc, sourceVol, sname, accessType string, snapSize resource.Quantity) (string, error) {
-	logger.Info("CreateSnapshot called", "name", sname)
+func (s *LogicalVolumeService) CreateSnapshot(ctx context.Context, node, dc, sourceVol, sname, accessType string, snapSize resource.Quantity, encrypted bool, filesystem VolumeFilesystem, freeze bool) (string, error) {
+	logger.Info("CreateSnapshot called", "name", sname, "freeze_filesystem", freeze)
 	snapshotLV := &topolvmv1.LogicalVolume{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: sname,
 		},
 		Spec: topolvmv1.LogicalVolumeSpec{
-			Name:        sname,
-			NodeName:    node,
-			DeviceClass: dc,
-			Size:        snapSize,
-			Source:      sourceVol,
-			AccessType:  accessType,
+			Name:             sname,
+			NodeName:         node,
+			DeviceClass:      dc,
+			Size:             snapSize,
+			Source:           sourceVol,
+			AccessType:       accessType,
+			Encrypted:        encrypted,
+			FsType:           filesystem.FsType,
+			MkfsOptions:      filesystem.MkfsOptions,
+			FreezeFilesystem: freeze,
 		},
 	}
 
@@ -290,58 +401,184 @@ func (s *LogicalVolumeService) CreateSnapshot(ctx context.Context, node, dc, sou
 		}
 	}
 
-	volumeID, err := s.waitForStatusUpdate(ctx, sname)
+	newLV, err := s.waitForStatusUpdate(ctx, sname, operationSnapshot)
 	if err != nil {
 		return "", err
 	}
 
-	return volumeID, nil
+	return newLV.Status.VolumeID, nil
 }
 
-// ExpandVolume expands volume
-func (s *LogicalVolumeService) ExpandVolume(ctx context.Context, volumeID string, requestGb int64) error {
-	logger.Info("k8s.ExpandVolume called", "volumeID", volumeID, "requestGb", requestGb)
+// CreateGroupSnapshot creates snapshots of the source volumes at the same point in time.
+// The source volumes must be on the same node and device class.
+// names are the names of the snapshots in the order of sourceVols, and
+// the volume IDs of the snapshots are returned in the same order.
+func (s *LogicalVolumeService) CreateGroupSnapshot(ctx context.Context, groupName string, sourceVols []*topolvmv1.LogicalVolume, names []string) ([]string, error) {
+	logger.Info("CreateGroupSnapshot called", "name", groupName, "members", names)
+
+	for i, sourceVol := range sourceVols {
+		// Since the kubernetes snapshots are Read-Only, the snapshots are activated as read-only volumes.
+		snapshotLV := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: names[i],
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:        names[i],
+				NodeName:    sourceVol.Spec.NodeName,
+				DeviceClass: sourceVol.Spec.DeviceClass,
+				Size:        sourceVol.Spec.Size,
+				Source:      sourceVol.Spec.Name,
+				AccessType:  "ro",
+				Encrypted:   sourceVol.Spec.Encrypted,
+				FsType:      sourceVol.Spec.FsType,
+				MkfsOptions: sourceVol.Spec.MkfsOptions,
+				GroupSnapshot: &topolvmv1.GroupSnapshot{
+					Name:    groupName,
+					Members: names,
+				},
+			},
+		}
+
+		existingSnapshot := new(topolvmv1.LogicalVolume)
+		err := s.getter.Get(ctx, client.ObjectKey{Name: names[i]}, existingSnapshot)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			if err := s.writer.Create(ctx, snapshotLV); err != nil {
+				return nil, err
+			}
+			logger.Info("created LogicalVolume CR", "name", names[i], "source", snapshotLV.Spec.Source, "group", groupName)
+		} else if !existingSnapshot.IsCompatibleWith(snapshotLV) || existingSnapshot.Spec.GroupSnapshot == nil ||
+			existingSnapshot.Spec.GroupSnapshot.Name != groupName {
+			return nil, status.Error(codes.AlreadyExists, "Incompatible LogicalVolume already exists")
+		}
+	}
+
+	volumeIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		newLV, err := s.waitForStatusUpdate(ctx, name, operationGroupSnapshot)
+		if err != nil {
+			// The snapshots are taken together, so delete all of them to start over.
+			for _, name := range names {
+				lv := &topolvmv1.LogicalVolume{ObjectMeta: metav1.ObjectMeta{Name: name}}
+				if err := s.writer.Delete(ctx, lv); err != nil && !apierrors.IsNotFound(err) {
+					logger.Error(err, "failed to delete LogicalVolume", "name", name)
+				}
+			}
+			return nil, err
+		}
+		volumeIDs = append(volumeIDs, newLV.Status.VolumeID)
+	}
+	return volumeIDs, nil
+}
+
+// ListGroupSnapshot returns the snapshots in the group snapshot.
+func (s *LogicalVolumeService) ListGroupSnapshot(ctx context.Context, groupName string) ([]*topolvmv1.LogicalVolume, error) {
+	return s.volumeGetter.ListGroupSnapshot(ctx, groupName)
+}
+
+// ExpandVolume expands volume, and returns its actual size in bytes.
+func (s *LogicalVolumeService) ExpandVolume(ctx context.Context, volumeID string, requestBytes int64) (int64, error) {
+	logger.Info("k8s.ExpandVolume called", "volumeID", volumeID, "requestBytes", requestBytes)
 
 	lv, err := s.GetVolume(ctx, volumeID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	err = s.updateSpecSize(ctx, volumeID, resource.NewQuantity(requestGb<<30, resource.BinarySI))
+	ch, unsubscribe := s.notifier.subscribe(lv.Name)
+	defer unsubscribe()
+
+	err = s.updateSpecSize(ctx, volumeID, resource.NewQuantity(requestBytes, resource.BinarySI))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// wait until topolvm-node expands the target volume
-	for {
-		logger.Info("waiting for update of 'status.currentSize'", "name", lv.Name)
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(1 * time.Second):
-		}
+	ctx, cancel := s.waitContext(ctx)
+	defer cancel()
+	start := time.Now()
+	var currentSize int64
+	err = func() error {
+		for {
+			var changedLV topolvmv1.LogicalVolume
+			err := s.getter.Get(ctx, client.ObjectKey{Name: lv.Name}, &changedLV)
+			if err != nil {
+				logger.Error(err, "failed to get LogicalVolume", "name", lv.Name)
+				return err
+			}
+			if changedLV.Status.Code != codes.OK {
+				return status.Error(changedLV.Status.Code, changedLV.Status.Message)
+			}
+			switch {
+			case changedLV.Status.CurrentSize == nil:
+				// WA: since Status.CurrentSize is added in v0.4.0. it may be missing.
+				// if the expansion is completed, it is filled, so wait for that.
+			case changedLV.Status.CurrentSize.Value() < changedLV.Spec.Size.Value():
+				// The current size can be larger than the requested size because LVM rounds it up to the extent size.
+				logger.Info("current size is smaller than requested size", "current", changedLV.Status.CurrentSize.Value(), "requested", changedLV.Spec.Size.Value())
+			default:
+				currentSize = changedLV.Status.CurrentSize.Value()
+				return nil
+			}
 
-		var changedLV topolvmv1.LogicalVolume
-		err := s.getter.Get(ctx, client.ObjectKey{Name: lv.Name}, &changedLV)
-		if err != nil {
-			logger.Error(err, "failed to get LogicalVolume", "name", lv.Name)
-			return err
-		}
-		if changedLV.Status.Code != codes.OK {
-			return status.Error(changedLV.Status.Code, changedLV.Status.Message)
-		}
-		if changedLV.Status.CurrentSize == nil {
-			// WA: since Status.CurrentSize is added in v0.4.0. it may be missing.
-			// if the expansion is completed, it is filled, so wait for that.
-			continue
-		}
-		if changedLV.Status.CurrentSize.Value() != changedLV.Spec.Size.Value() {
-			logger.Info("failed to match current size and requested size", "current", changedLV.Status.CurrentSize.Value(), "requested", changedLV.Spec.Size.Value())
-			continue
+			logger.Info("waiting for update of 'status.currentSize'", "name", lv.Name)
+			if err := s.notifier.wait(ctx, ch); err != nil {
+				return err
+			}
 		}
+	}()
+	observeWaitDuration(operationExpand, start, err)
+	return currentSize, err
+}
 
-		return nil
+// ModifyVolume modifies the attributes of volume
+func (s *LogicalVolumeService) ModifyVolume(ctx context.Context, volumeID string, attrs VolumeAttributes) error {
+	logger.Info("k8s.ModifyVolume called", "volumeID", volumeID, "readAhead", attrs.ReadAhead, "tags", attrs.Tags)
+
+	lv, err := s.GetVolume(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+
+	ch, unsubscribe := s.notifier.subscribe(lv.Name)
+	defer unsubscribe()
+
+	generation, err := s.updateSpecAttributes(ctx, volumeID, attrs)
+	if err != nil {
+		return err
 	}
+
+	// wait until topolvm-node modifies the target volume
+	ctx, cancel := s.waitContext(ctx)
+	defer cancel()
+	start := time.Now()
+	err = func() error {
+		for {
+			var changedLV topolvmv1.LogicalVolume
+			err := s.getter.Get(ctx, client.ObjectKey{Name: lv.Name}, &changedLV)
+			if err != nil {
+				logger.Error(err, "failed to get LogicalVolume", "name", lv.Name)
+				return err
+			}
+			// A failure before the update of the spec is not the result of this request.
+			cond := meta.FindStatusCondition(changedLV.Status.Conditions, topolvmv1.LogicalVolumeFailed)
+			if changedLV.Status.Code != codes.OK && cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration >= generation {
+				return status.Error(changedLV.Status.Code, changedLV.Status.Message)
+			}
+			if changedLV.Status.ReadAhead == changedLV.Spec.ReadAhead && equalStrings(changedLV.Status.Tags, changedLV.Spec.Tags) {
+				return nil
+			}
+
+			logger.Info("waiting for update of 'status.readAhead' and 'status.tags'", "name", lv.Name)
+			if err := s.notifier.wait(ctx, ch); err != nil {
+				return err
+			}
+		}
+	}()
+	observeWaitDuration(operationModify, start, err)
+	return err
 }
 
 // GetVolume returns LogicalVolume by volume ID.
@@ -349,6 +586,46 @@ func (s *LogicalVolumeService) GetVolume(ctx context.Context, volumeID string) (
 	return s.volumeGetter.Get(ctx, volumeID)
 }
 
+// UpdateFilesystemUsage records the usage of the filesystem on the volume in .Status.Filesystem.
+// The update is skipped if the usage has not changed much since the last update
+// to reduce the load of the API server.
+func (s *LogicalVolumeService) UpdateFilesystemUsage(ctx context.Context, volumeID string, capacityBytes, usedBytes int64) error {
+	lv, err := s.GetVolume(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+
+	if fs := lv.Status.Filesystem; fs != nil && fs.CapacityBytes == capacityBytes &&
+		time.Since(fs.ObservedTime.Time) < filesystemUsageResyncPeriod {
+		diff := fs.UsedBytes - usedBytes
+		if diff < 0 {
+			diff = -diff
+		}
+		if capacityBytes > 0 && diff*100 < capacityBytes {
+			return nil
+		}
+	}
+
+	lv.Status.Filesystem = &topolvmv1.FilesystemUsage{
+		CapacityBytes: capacityBytes,
+		UsedBytes:     usedBytes,
+		ObservedTime:  metav1.Now(),
+	}
+	return s.writer.Status().Update(ctx, lv)
+}
+
+// UpdateLastFsckTime records the time when the filesystem on the volume was checked in .Status.LastFsckTime.
+func (s *LogicalVolumeService) UpdateLastFsckTime(ctx context.Context, volumeID string, checkedAt time.Time) error {
+	lv, err := s.GetVolume(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+
+	t := metav1.NewTime(checkedAt)
+	lv.Status.LastFsckTime = &t
+	return s.writer.Status().Update(ctx, lv)
+}
+
 // updateSpecSize updates .Spec.Size of LogicalVolume.
 func (s *LogicalVolumeService) updateSpecSize(ctx context.Context, volumeID string, size *resource.Quantity) error {
 	for {
@@ -382,33 +659,114 @@ func (s *LogicalVolumeService) updateSpecSize(ctx context.Context, volumeID stri
 	}
 }
 
-// waitForStatusUpdate waits for logical volume creation/failure/timeout, whichever comes first.
-func (s *LogicalVolumeService) waitForStatusUpdate(ctx context.Context, name string) (string, error) {
+// updateSpecAttributes updates the modifiable attributes in .Spec of LogicalVolume.
+// It returns the generation of the updated LogicalVolume.
+func (s *LogicalVolumeService) updateSpecAttributes(ctx context.Context, volumeID string, attrs VolumeAttributes) (int64, error) {
 	for {
-		logger.Info("waiting for setting 'status.volumeID'", "name", name)
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(100 * time.Millisecond):
+		lv, err := s.GetVolume(ctx, volumeID)
+		if err != nil {
+			return 0, err
+		}
+
+		lv.Spec.IOLimits = attrs.IOLimits
+		lv.Spec.ReadAhead = attrs.ReadAhead
+		lv.Spec.Tags = attrs.Tags
+		lv.Spec.FSTrimInterval = attrs.FSTrimInterval
+
+		if err := s.writer.Update(ctx, lv); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Info("detect conflict when LogicalVolume spec update", "name", lv.Name)
+				select {
+				case <-ctx.Done():
+					return 0, ctx.Err()
+				case <-time.After(1 * time.Second):
+				}
+				continue
+			}
+			logger.Error(err, "failed to update LogicalVolume spec", "name", lv.Name)
+			return 0, err
 		}
 
+		return lv.Generation, nil
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// currentBytes returns the actual size of the volume in bytes.
+// Status.CurrentSize is not set for the volumes found already existing when topolvm-node created them.
+func currentBytes(lv *topolvmv1.LogicalVolume) int64 {
+	if lv.Status.CurrentSize == nil {
+		return lv.Spec.Size.Value()
+	}
+	return lv.Status.CurrentSize.Value()
+}
+
+// waitContext returns the context bounding a wait for topolvm-node to update a LogicalVolume.
+// The wait ends waitDeadlineMargin before the deadline of ctx, and never lasts longer than s.maxWait.
+func (s *LogicalVolumeService) waitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := s.maxWait
+	if deadline, ok := ctx.Deadline(); ok {
+		if d := time.Until(deadline) - waitDeadlineMargin; d < timeout {
+			timeout = d
+		}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// waitForStatusUpdate waits for logical volume creation/failure/timeout, whichever comes first,
+// and returns the created LogicalVolume.
+func (s *LogicalVolumeService) waitForStatusUpdate(ctx context.Context, name, operation string) (lv *topolvmv1.LogicalVolume, err error) {
+	ch, unsubscribe := s.notifier.subscribe(name)
+	defer unsubscribe()
+
+	ctx, cancel := s.waitContext(ctx)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		observeWaitDuration(operation, start, err)
+	}()
+
+	for {
 		var newLV topolvmv1.LogicalVolume
 		err := s.getter.Get(ctx, client.ObjectKey{Name: name}, &newLV)
 		if err != nil {
 			logger.Error(err, "failed to get LogicalVolume", "name", name)
-			return "", err
+			return nil, err
 		}
 		if newLV.Status.VolumeID != "" {
 			logger.Info("end k8s.LogicalVolume", "volume_id", newLV.Status.VolumeID)
-			return newLV.Status.VolumeID, nil
+			return &newLV, nil
 		}
-		if newLV.Status.Code != codes.OK {
+		switch {
+		case newLV.Status.Code == codes.OK:
+			logger.Info("waiting for setting 'status.volumeID'", "name", name)
+		case newLV.Status.NextRetryTime != nil:
+			// topolvm-node will retry creating the volume, so keep waiting.
+			logger.Info("waiting for retry of creating the volume", "name", name,
+				"attempts", newLV.Status.CreateAttempts, "next_retry_time", newLV.Status.NextRetryTime.Time, "message", newLV.Status.Message)
+		default:
 			err := s.writer.Delete(ctx, &newLV)
 			if err != nil {
 				// log this error but do not return this error, because newLV.Status.Message is more important
 				logger.Error(err, "failed to delete LogicalVolume")
 			}
-			return "", status.Error(newLV.Status.Code, newLV.Status.Message)
+			return nil, status.Error(newLV.Status.Code, newLV.Status.Message)
+		}
+
+		if err := s.notifier.wait(ctx, ch); err != nil {
+			return nil, err
 		}
 	}
 }
@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type getterFunc func(ctx context.Context, key client.ObjectKey, obj client.Object) error
+
+func (f getterFunc) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return f(ctx, key, obj)
+}
+
+func TestWaitTimeout(t *testing.T) {
+	// The LogicalVolume never gets its volume ID.
+	s := &LogicalVolumeService{
+		getter: getterFunc(func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			return nil
+		}),
+		notifier: newLogicalVolumeNotifier(),
+		maxWait:  100 * time.Millisecond,
+	}
+
+	// The wait is bounded by maxWait without the deadline of the request.
+	start := time.Now()
+	_, err := s.waitForStatusUpdate(context.Background(), "a", operationCreate)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("should be DeadlineExceeded: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= resyncInterval {
+		t.Errorf("should time out after maxWait: %s", elapsed)
+	}
+
+	// The wait ends before the deadline of the request.
+	s.maxWait = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), waitDeadlineMargin+100*time.Millisecond)
+	defer cancel()
+	_, err = s.waitForStatusUpdate(ctx, "a", operationCreate)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("should be DeadlineExceeded: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("should time out before the deadline of the request")
+	}
+
+	// The cancellation of the request is not a timeout.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = s.waitForStatusUpdate(ctx, "a", operationCreate)
+	if err != context.Canceled {
+		t.Errorf("should be canceled: %v", err)
+	}
+}
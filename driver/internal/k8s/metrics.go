@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	operationCreate        = "create"
+	operationSnapshot      = "snapshot"
+	operationGroupSnapshot = "group_snapshot"
+	operationExpand        = "expand"
+	operationModify        = "modify"
+	operationDelete        = "delete"
+)
+
+var waitDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "topolvm",
+	Subsystem: "logicalvolume",
+	Name:      "wait_duration_seconds",
+	Help:      "Time taken until an operation on LogicalVolume is reflected in its status",
+	Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+}, []string{"operation", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(waitDurationSeconds)
+}
+
+func observeWaitDuration(operation string, start time.Time, err error) {
+	result := "succeeded"
+	if err != nil {
+		result = "failed"
+	}
+	waitDurationSeconds.WithLabelValues(operation, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,161 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DefaultCgroupRoot is the default mount point of the cgroup v2 hierarchy.
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+
+	// podUIDKey is the key of volume context added by kubelet when CSIDriver has podInfoOnMount.
+	podUIDKey = "csi.storage.k8s.io/pod.uid"
+
+	ioMaxFile = "io.max"
+)
+
+// Types of I/O limits used as the label of the metrics.
+const (
+	ioLimitReadIOPS            = "read_iops"
+	ioLimitWriteIOPS           = "write_iops"
+	ioLimitReadBytesPerSecond  = "read_bytes_per_second"
+	ioLimitWriteBytesPerSecond = "write_bytes_per_second"
+)
+
+var volumeIOLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "topolvm",
+	Subsystem: "volume",
+	Name:      "io_limit",
+	Help:      "The I/O limit applied to the published volume. 0 means unlimited",
+}, []string{"node", "volume_id", "type"})
+
+func init() {
+	metrics.Registry.MustRegister(volumeIOLimit)
+}
+
+// formatIOMax returns the io.max entry of the device for the limits.
+func formatIOMax(major, minor uint32, limits *v1.IOLimits) string {
+	value := func(v int64) string {
+		if v <= 0 {
+			return "max"
+		}
+		return strconv.FormatInt(v, 10)
+	}
+	return fmt.Sprintf("%d:%d rbps=%s wbps=%s riops=%s wiops=%s", major, minor,
+		value(limits.ReadBytesPerSecond), value(limits.WriteBytesPerSecond),
+		value(limits.ReadIOPS), value(limits.WriteIOPS))
+}
+
+// podUIDFromTargetPath returns the UID of the pod from the target path of NodePublishVolume, e.g.
+// /var/lib/kubelet/pods/<uid>/volumes/kubernetes.io~csi/<pv>/mount for filesystem volumes and
+// /var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/<pv>/<uid> for block volumes.
+func podUIDFromTargetPath(targetPath string) string {
+	elems := strings.Split(filepath.Clean(targetPath), string(filepath.Separator))
+	for i := 0; i+2 < len(elems); i++ {
+		if elems[i] == "pods" && elems[i+2] == "volumes" {
+			return elems[i+1]
+		}
+		if elems[i] == "volumeDevices" && elems[i+1] == "publish" && i+3 == len(elems)-1 {
+			return elems[i+3]
+		}
+	}
+	return ""
+}
+
+// findPodCgroup returns the cgroup directory of the pod under root.
+// Both the cgroupfs and systemd cgroup drivers of kubelet are supported.
+func findPodCgroup(root, podUID string) (string, error) {
+	names := map[string]struct{}{
+		// cgroupfs driver: kubepods/<qos>/pod<uid>
+		"pod" + podUID: {},
+	}
+	// systemd driver: kubepods.slice/kubepods-<qos>.slice/kubepods-<qos>-pod<uid>.slice
+	systemdSuffix := "-pod" + strings.ReplaceAll(podUID, "-", "_") + ".slice"
+
+	var found string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if _, ok := names[d.Name()]; ok || strings.HasSuffix(d.Name(), systemdSuffix) {
+			found = path
+			return filepath.SkipAll
+		}
+		// Pod cgroups are at most three levels below the root in the kubepods hierarchy.
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		depth := strings.Count(rel, string(filepath.Separator)) + 1
+		if (depth == 1 && !strings.HasPrefix(d.Name(), "kubepods")) || depth >= 3 {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("cgroup of pod %s is not found in %s: %w", podUID, root, os.ErrNotExist)
+	}
+	return found, nil
+}
+
+// applyIOLimits writes the io.max entry of the device to the cgroup of the pod.
+func applyIOLimits(cgroupRoot, podUID string, major, minor uint32, limits *v1.IOLimits) error {
+	cgroup, err := findPodCgroup(cgroupRoot, podUID)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cgroup, ioMaxFile), []byte(formatIOMax(major, minor, limits)), 0644)
+}
+
+// clearIOLimits removes the io.max entry of the device from the cgroup of the pod if it exists.
+// It does nothing if the cgroup has already been removed.
+func clearIOLimits(cgroupRoot, podUID string, major, minor uint32) error {
+	cgroup, err := findPodCgroup(cgroupRoot, podUID)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ioMax := filepath.Join(cgroup, ioMaxFile)
+	content, err := os.ReadFile(ioMax)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	prefix := fmt.Sprintf("%d:%d ", major, minor)
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return os.WriteFile(ioMax, []byte(formatIOMax(major, minor, &v1.IOLimits{})), 0644)
+		}
+	}
+	return nil
+}
+
+func setIOLimitMetrics(nodeName, volumeID string, limits *v1.IOLimits) {
+	volumeIOLimit.WithLabelValues(nodeName, volumeID, ioLimitReadIOPS).Set(float64(limits.ReadIOPS))
+	volumeIOLimit.WithLabelValues(nodeName, volumeID, ioLimitWriteIOPS).Set(float64(limits.WriteIOPS))
+	volumeIOLimit.WithLabelValues(nodeName, volumeID, ioLimitReadBytesPerSecond).Set(float64(limits.ReadBytesPerSecond))
+	volumeIOLimit.WithLabelValues(nodeName, volumeID, ioLimitWriteBytesPerSecond).Set(float64(limits.WriteBytesPerSecond))
+}
+
+func deleteIOLimitMetrics(volumeID string) {
+	volumeIOLimit.DeletePartialMatch(prometheus.Labels{"volume_id": volumeID})
+}
@@ -0,0 +1,87 @@
+package driver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/topolvm/topolvm/api/v1"
+)
+
+func TestFormatIOMax(t *testing.T) {
+	line := formatIOMax(253, 3, &v1.IOLimits{ReadIOPS: 1000, WriteBytesPerSecond: 1 << 20})
+	expected := "253:3 rbps=max wbps=1048576 riops=1000 wiops=max"
+	if line != expected {
+		t.Errorf("expected %q, but got %q", expected, line)
+	}
+}
+
+func TestPodUIDFromTargetPath(t *testing.T) {
+	testCases := map[string]string{
+		"/var/lib/kubelet/pods/1234-5678/volumes/kubernetes.io~csi/pvc-1/mount":                "1234-5678",
+		"/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/pvc-1/1234-5678":     "1234-5678",
+		"/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/pvc-1/1234-5678/dev": "",
+		"/mnt/target": "",
+	}
+	for path, expected := range testCases {
+		if uid := podUIDFromTargetPath(path); uid != expected {
+			t.Errorf("%s: expected %q, but got %q", path, expected, uid)
+		}
+	}
+}
+
+func TestIOLimits(t *testing.T) {
+	testCases := map[string]string{
+		"cgroupfs":   "kubepods/burstable/pod1234-5678",
+		"guaranteed": "kubepods/pod1234-5678",
+		"systemd":    "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1234_5678.slice",
+	}
+	for name, podCgroup := range testCases {
+		root := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(root, "system.slice", "pod1234-5678"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(root, podCgroup), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		cgroup, err := findPodCgroup(root, "1234-5678")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cgroup != filepath.Join(root, podCgroup) {
+			t.Errorf("%s: unexpected cgroup: %s", name, cgroup)
+		}
+
+		if err := applyIOLimits(root, "1234-5678", 253, 3, &v1.IOLimits{WriteIOPS: 100}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		content, err := os.ReadFile(filepath.Join(cgroup, ioMaxFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "253:3 rbps=max wbps=max riops=max wiops=100" {
+			t.Errorf("%s: unexpected io.max: %q", name, content)
+		}
+
+		if err := clearIOLimits(root, "1234-5678", 253, 3); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		content, err = os.ReadFile(filepath.Join(cgroup, ioMaxFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "253:3 rbps=max wbps=max riops=max wiops=max" {
+			t.Errorf("%s: unexpected io.max: %q", name, content)
+		}
+	}
+
+	_, err := findPodCgroup(t.TempDir(), "1234-5678")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("should be ErrNotExist: %v", err)
+	}
+	if err := clearIOLimits(t.TempDir(), "1234-5678", 253, 3); err != nil {
+		t.Errorf("should not be error for removed cgroup: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+)
+
+// defaultFsType is the filesystem type used when no type is specified.
+const defaultFsType = "ext4"
+
+// mkfsJournalOptionsPattern restricts the journal options to a comma-separated list of key=value pairs
+// so that no other argument can be passed to mkfs.
+var mkfsJournalOptionsPattern = regexp.MustCompile(`^[a-z][a-z_-]*(=[0-9A-Za-z_./-]+)?(,[a-z][a-z_-]*(=[0-9A-Za-z_./-]+)?)*$`)
+
+// makeMkfsOptions returns the arguments of mkfs for fsType converted from the parameters of a CSI volume create request.
+func makeMkfsOptions(fsType string, params map[string]string) ([]string, error) {
+	var options []string
+	unsupported := func(key string) error {
+		return fmt.Errorf("%s is not supported for %s", key, fsType)
+	}
+
+	switch fsType {
+	case "ext4", "xfs", "btrfs":
+	default:
+		for _, key := range []string{topolvm.GetMkfsBlockSizeKey(), topolvm.GetMkfsInodeRatioKey(), topolvm.GetMkfsReflinkKey(), topolvm.GetMkfsJournalOptionsKey()} {
+			if _, ok := params[key]; ok {
+				return nil, unsupported(key)
+			}
+		}
+		return nil, nil
+	}
+
+	if v, ok := params[topolvm.GetMkfsBlockSizeKey()]; ok {
+		size, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || size < 512 || size > 65536 || size&(size-1) != 0 {
+			return nil, fmt.Errorf("%s must be a power of 2 between 512 and 65536: %q", topolvm.GetMkfsBlockSizeKey(), v)
+		}
+		switch fsType {
+		case "ext4":
+			options = append(options, "-b", v)
+		case "xfs":
+			options = append(options, "-b", "size="+v)
+		case "btrfs":
+			options = append(options, "--sectorsize", v)
+		}
+	}
+
+	if v, ok := params[topolvm.GetMkfsInodeRatioKey()]; ok {
+		if fsType != "ext4" {
+			return nil, unsupported(topolvm.GetMkfsInodeRatioKey())
+		}
+		ratio, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || ratio < 1024 || ratio > 67108864 {
+			return nil, fmt.Errorf("%s must be between 1024 and 67108864: %q", topolvm.GetMkfsInodeRatioKey(), v)
+		}
+		options = append(options, "-i", v)
+	}
+
+	if v, ok := params[topolvm.GetMkfsReflinkKey()]; ok {
+		if fsType != "xfs" {
+			return nil, unsupported(topolvm.GetMkfsReflinkKey())
+		}
+		reflink, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q", topolvm.GetMkfsReflinkKey(), v)
+		}
+		if reflink {
+			options = append(options, "-m", "reflink=1")
+		} else {
+			options = append(options, "-m", "reflink=0")
+		}
+	}
+
+	if v, ok := params[topolvm.GetMkfsJournalOptionsKey()]; ok {
+		if !mkfsJournalOptionsPattern.MatchString(v) {
+			return nil, fmt.Errorf("invalid value for %s: %q", topolvm.GetMkfsJournalOptionsKey(), v)
+		}
+		switch fsType {
+		case "ext4":
+			options = append(options, "-J", v)
+		case "xfs":
+			options = append(options, "-l", v)
+		default:
+			return nil, unsupported(topolvm.GetMkfsJournalOptionsKey())
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+)
+
+func TestMakeMkfsOptions(t *testing.T) {
+	testCases := []struct {
+		fsType   string
+		params   map[string]string
+		expected []string
+		err      bool
+	}{
+		{
+			fsType: "ext4",
+			params: map[string]string{
+				topolvm.GetMkfsBlockSizeKey():      "4096",
+				topolvm.GetMkfsInodeRatioKey():     "65536",
+				topolvm.GetMkfsJournalOptionsKey(): "size=128",
+			},
+			expected: []string{"-b", "4096", "-i", "65536", "-J", "size=128"},
+		},
+		{
+			fsType: "xfs",
+			params: map[string]string{
+				topolvm.GetMkfsBlockSizeKey():      "4096",
+				topolvm.GetMkfsReflinkKey():        "false",
+				topolvm.GetMkfsJournalOptionsKey(): "size=64m,lazy-count=1",
+			},
+			expected: []string{"-b", "size=4096", "-m", "reflink=0", "-l", "size=64m,lazy-count=1"},
+		},
+		{
+			fsType: "xfs",
+			params: map[string]string{
+				topolvm.GetMkfsReflinkKey():        "true",
+				topolvm.GetMkfsJournalOptionsKey(): "internal",
+			},
+			expected: []string{"-m", "reflink=1", "-l", "internal"},
+		},
+		{
+			fsType:   "btrfs",
+			params:   map[string]string{topolvm.GetMkfsBlockSizeKey(): "4096"},
+			expected: []string{"--sectorsize", "4096"},
+		},
+		{
+			fsType:   "ext4",
+			params:   map[string]string{topolvm.GetDeviceClassKey(): "ssd"},
+			expected: nil,
+		},
+		{
+			fsType:   "ext3",
+			params:   map[string]string{},
+			expected: nil,
+		},
+		{
+			fsType: "ext4",
+			params: map[string]string{topolvm.GetMkfsBlockSizeKey(): "3000"},
+			err:    true,
+		},
+		{
+			fsType: "ext4",
+			params: map[string]string{topolvm.GetMkfsInodeRatioKey(): "512"},
+			err:    true,
+		},
+		{
+			fsType: "ext4",
+			params: map[string]string{topolvm.GetMkfsReflinkKey(): "true"},
+			err:    true,
+		},
+		{
+			fsType: "ext4",
+			params: map[string]string{topolvm.GetMkfsJournalOptionsKey(): "size=128 -O ^has_journal"},
+			err:    true,
+		},
+		{
+			fsType: "xfs",
+			params: map[string]string{topolvm.GetMkfsInodeRatioKey(): "65536"},
+			err:    true,
+		},
+		{
+			fsType: "btrfs",
+			params: map[string]string{topolvm.GetMkfsJournalOptionsKey(): "size=128"},
+			err:    true,
+		},
+		{
+			fsType: "ext3",
+			params: map[string]string{topolvm.GetMkfsBlockSizeKey(): "4096"},
+			err:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		options, err := makeMkfsOptions(tc.fsType, tc.params)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s %v: should fail", tc.fsType, tc.params)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %v", tc.fsType, tc.params, err)
+			continue
+		}
+		if !reflect.DeepEqual(options, tc.expected) {
+			t.Errorf("%s %v: expected %v, but got %v", tc.fsType, tc.params, tc.expected, options)
+		}
+	}
+}
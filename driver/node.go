@@ -2,6 +2,8 @@ package driver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/topolvm/topolvm"
+	v1 "github.com/topolvm/topolvm/api/v1"
 	"github.com/topolvm/topolvm/driver/internal/k8s"
 	"github.com/topolvm/topolvm/filesystem"
 	"github.com/topolvm/topolvm/lvmd/proto"
@@ -18,6 +21,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/client-go/tools/record"
 	mountutil "k8s.io/mount-utils"
 	utilexec "k8s.io/utils/exec"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -26,7 +30,7 @@ import (
 
 const (
 	// DeviceDirectory is a directory where TopoLVM Node service creates device files.
-	DeviceDirectory = "/dev/topolvm"
+	DeviceDirectory = topolvm.DeviceDirectory
 
 	findmntCmd = "/bin/findmnt"
 
@@ -35,25 +39,36 @@ const (
 
 var nodeLogger = ctrl.Log.WithName("driver").WithName("node")
 
-// NewNodeServer returns a new NodeServer.
-func NewNodeServer(nodeName string, conn *grpc.ClientConn, mgr manager.Manager) (csi.NodeServer, error) {
+// NewNodeServer returns a new NodeServer and SnapshotMetadataServer.
+// cgroupRoot is the mount point of the cgroup v2 hierarchy of the host, used to apply I/O limits.
+func NewNodeServer(nodeName string, conn *grpc.ClientConn, mgr manager.Manager, cgroupRoot string) (csi.NodeServer, csi.SnapshotMetadataServer, error) {
 	lvService, err := k8s.NewLogicalVolumeService(mgr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &nodeServer{
+	node := &nodeServer{
 		server: &nodeServerNoLocked{
 			nodeName:     nodeName,
 			client:       proto.NewVGServiceClient(conn),
 			lvService:    proto.NewLVServiceClient(conn),
 			k8sLVService: lvService,
+			cgroupRoot:   cgroupRoot,
+			ioLimits:     make(map[string]v1.IOLimits),
+			recorder:     mgr.GetEventRecorderFor("topolvm-node"),
 			mounter: mountutil.SafeFormatAndMount{
 				Interface: mountutil.New(""),
 				Exec:      utilexec.New(),
 			},
 		},
-	}, nil
+	}
+	// The snapshot metadata server does not modify anything, so it does not share the lock with nodeServer.
+	snapshotMetadata := &snapshotMetadataServer{
+		nodeName:     nodeName,
+		lvService:    proto.NewLVServiceClient(conn),
+		k8sLVService: lvService,
+	}
+	return node, snapshotMetadata, nil
 }
 
 // This is a wrapper for nodeServerNoLocked to protect concurrent method calls.
@@ -115,7 +130,12 @@ type nodeServerNoLocked struct {
 	client       proto.VGServiceClient
 	lvService    proto.LVServiceClient
 	k8sLVService *k8s.LogicalVolumeService
+	cgroupRoot   string
+	recorder     record.EventRecorder
 	mounter      mountutil.SafeFormatAndMount
+
+	// ioLimits holds the I/O limits applied to the pods using each volume.
+	ioLimits map[string]v1.IOLimits
 }
 
 func (s *nodeServerNoLocked) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
@@ -161,25 +181,175 @@ func (s *nodeServerNoLocked) NodePublishVolume(ctx context.Context, req *csi.Nod
 	if err != nil {
 		return nil, err
 	}
-	lv, err = s.getLvFromContext(ctx, lvr.Spec.DeviceClass, volumeID)
+	lv, err = s.getLvFromContext(ctx, lvr.Spec.DeviceClass, lvr.LVName())
 	if err != nil {
 		return nil, err
 	}
 	if lv == nil {
 		return nil, status.Errorf(codes.NotFound, "failed to find LV: %s", volumeID)
 	}
+	if lvr.Spec.Encrypted {
+		// publish the mapped device instead of the LV.
+		lv, err = s.openEncryptedVolume(volumeID, lv, req.GetSecrets())
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if isBlockVol {
 		err = s.nodePublishBlockVolume(req, lv)
 	} else if isFsVol {
-		err = s.nodePublishFilesystemVolume(req, lv)
+		err = s.nodePublishFilesystemVolume(ctx, req, lv, lvr)
 	}
 	if err != nil {
 		return nil, err
 	}
+	if lvr.Spec.IOLimits != nil {
+		if err := s.nodePublishIOLimits(req, lv, lvr.Spec.IOLimits); err != nil {
+			return nil, err
+		}
+	}
+	if err := addPublishRecord(publishDirectory, volumeID, req.GetTargetPath()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record publish: volume=%s, target=%s, error=%v", volumeID, req.GetTargetPath(), err)
+	}
 	return &csi.NodePublishVolumeResponse{}, nil
 }
 
+func (s *nodeServerNoLocked) nodePublishIOLimits(req *csi.NodePublishVolumeRequest, lv *proto.LogicalVolume, limits *v1.IOLimits) error {
+	podUID := req.GetVolumeContext()[podUIDKey]
+	if podUID == "" {
+		podUID = podUIDFromTargetPath(req.GetTargetPath())
+	}
+	if podUID == "" {
+		return status.Errorf(codes.Internal, "failed to find the pod to apply I/O limits: volume=%s, target=%s", req.GetVolumeId(), req.GetTargetPath())
+	}
+
+	if err := applyIOLimits(s.cgroupRoot, podUID, lv.DevMajor, lv.DevMinor, limits); err != nil {
+		return status.Errorf(codes.Internal, "failed to apply I/O limits: volume=%s, pod=%s, error=%v", req.GetVolumeId(), podUID, err)
+	}
+	setIOLimitMetrics(s.nodeName, req.GetVolumeId(), limits)
+	s.ioLimits[req.GetVolumeId()] = *limits
+
+	nodeLogger.Info("applied I/O limits",
+		"volume_id", req.GetVolumeId(),
+		"pod_uid", podUID,
+		"limits", limits)
+	return nil
+}
+
+// openEncryptedVolume opens the LUKS device on the LV, formatting it if the LV is empty.
+// It returns the LogicalVolume whose device numbers are replaced with the ones of the mapped device.
+func (s *nodeServerNoLocked) openEncryptedVolume(volumeID string, lv *proto.LogicalVolume, secrets map[string]string) (*proto.LogicalVolume, error) {
+	passphrase := secrets[encryptionPassphraseKey]
+	if passphrase == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no %s is provided in secrets for encrypted volume %s", encryptionPassphraseKey, volumeID)
+	}
+
+	name := cryptMapperName(volumeID)
+	major, minor, err := findDMDevice(sysBlockDirectory, name)
+	if errors.Is(err, os.ErrNotExist) {
+		backing := cryptBackingDevice(volumeID)
+		if err := s.createDeviceIfNeeded(backing, lv); err != nil {
+			return nil, err
+		}
+
+		fsType, err := filesystem.DetectFilesystem(backing)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "filesystem check failed: volume=%s, error=%v", volumeID, err)
+		}
+		switch fsType {
+		case luksFilesystemType:
+		case "":
+			if err := luksFormat(s.mounter.Exec, backing, []byte(passphrase)); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to format LUKS device: volume=%s, error=%v", volumeID, err)
+			}
+			nodeLogger.Info("formatted LUKS device", "volume_id", volumeID)
+		default:
+			return nil, status.Errorf(codes.FailedPrecondition, "encrypted volume is not a LUKS device: volume=%s, current=%s", volumeID, fsType)
+		}
+
+		if err := luksOpen(s.mounter.Exec, backing, name, []byte(passphrase)); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to open LUKS device: volume=%s, error=%v", volumeID, err)
+		}
+		major, minor, err = findDMDevice(sysBlockDirectory, name)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to find opened LUKS device: volume=%s, error=%v", volumeID, err)
+		}
+		nodeLogger.Info("opened LUKS device", "volume_id", volumeID, "name", name)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find LUKS device: volume=%s, error=%v", volumeID, err)
+	}
+
+	return mappedVolume(lv, major, minor), nil
+}
+
+// closeEncryptedVolume closes the LUKS device of the volume if it is open.
+func (s *nodeServerNoLocked) closeEncryptedVolume(volumeID string) error {
+	name := cryptMapperName(volumeID)
+	_, _, err := findDMDevice(sysBlockDirectory, name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "failed to find LUKS device: volume=%s, error=%v", volumeID, err)
+	}
+
+	if err := luksClose(s.mounter.Exec, name); err != nil {
+		return status.Errorf(codes.Internal, "failed to close LUKS device: volume=%s, error=%v", volumeID, err)
+	}
+	backing := cryptBackingDevice(volumeID)
+	if err := os.Remove(backing); err != nil && !os.IsNotExist(err) {
+		return status.Errorf(codes.Internal, "remove device failed for %s: error=%v", backing, err)
+	}
+
+	nodeLogger.Info("closed LUKS device", "volume_id", volumeID, "name", name)
+	return nil
+}
+
+// resizeEncryptedVolume resizes the LUKS device of the volume to the size of the LV if it is open.
+func (s *nodeServerNoLocked) resizeEncryptedVolume(volumeID string, secrets map[string]string) error {
+	name := cryptMapperName(volumeID)
+	_, _, err := findDMDevice(sysBlockDirectory, name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "failed to find LUKS device: volume=%s, error=%v", volumeID, err)
+	}
+
+	var passphrase []byte
+	if v := secrets[encryptionPassphraseKey]; v != "" {
+		passphrase = []byte(v)
+	}
+	if err := luksResize(s.mounter.Exec, name, passphrase); err != nil {
+		return status.Errorf(codes.Internal, "failed to resize LUKS device: volume=%s, error=%v", volumeID, err)
+	}
+
+	nodeLogger.Info("resized LUKS device", "volume_id", volumeID, "name", name)
+	return nil
+}
+
+// encryptedVolume returns the LogicalVolume of the opened LUKS device on the LV.
+func (s *nodeServerNoLocked) encryptedVolume(volumeID string, lv *proto.LogicalVolume) (*proto.LogicalVolume, error) {
+	major, minor, err := findDMDevice(sysBlockDirectory, cryptMapperName(volumeID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, status.Errorf(codes.FailedPrecondition, "LUKS device is not open: volume=%s", volumeID)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find LUKS device: volume=%s, error=%v", volumeID, err)
+	}
+	return mappedVolume(lv, major, minor), nil
+}
+
+// mappedVolume returns a copy of lv whose device numbers are replaced with the ones of the mapped device.
+func mappedVolume(lv *proto.LogicalVolume, major, minor uint32) *proto.LogicalVolume {
+	return &proto.LogicalVolume{
+		Name:      lv.Name,
+		SizeGb:    lv.SizeGb,
+		DevMajor:  major,
+		DevMinor:  minor,
+		Tags:      lv.Tags,
+		SizeBytes: lv.SizeBytes,
+	}
+}
+
 func makeMountOptions(readOnly bool, mountOption *csi.VolumeCapability_MountVolume) ([]string, error) {
 	var mountOptions []string
 	if readOnly {
@@ -201,11 +371,17 @@ func makeMountOptions(readOnly bool, mountOption *csi.VolumeCapability_MountVolu
 	return mountOptions, nil
 }
 
-func (s *nodeServerNoLocked) nodePublishFilesystemVolume(req *csi.NodePublishVolumeRequest, lv *proto.LogicalVolume) error {
+func (s *nodeServerNoLocked) nodePublishFilesystemVolume(ctx context.Context, req *csi.NodePublishVolumeRequest, lv *proto.LogicalVolume, lvr *v1.LogicalVolume) error {
 	// Check request
 	mountOption := req.GetVolumeCapability().GetMount()
 	if mountOption.FsType == "" {
-		mountOption.FsType = "ext4"
+		mountOption.FsType = lvr.Spec.FsType
+	}
+	if mountOption.FsType == "" {
+		mountOption.FsType = defaultFsType
+	}
+	if lvr.Spec.FsType != "" && lvr.Spec.FsType != mountOption.FsType {
+		return status.Errorf(codes.FailedPrecondition, "filesystem type differs from the one recorded on the LogicalVolume: volume=%s, recorded=%s, requested=%s", req.GetVolumeId(), lvr.Spec.FsType, mountOption.FsType)
 	}
 
 	// Find lv and create a block device with it
@@ -240,8 +416,19 @@ func (s *nodeServerNoLocked) nodePublishFilesystemVolume(req *csi.NodePublishVol
 	}
 
 	if !mounted {
-		if err := s.mounter.FormatAndMount(device, req.GetTargetPath(), mountOption.FsType, mountOptions); err != nil {
-			return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
+		if fsType != "" && lvr.Spec.FsckPolicy != "" {
+			// The filesystem is checked according to the policy instead of 'fsck -a' run by FormatAndMount.
+			if err := s.checkFilesystem(ctx, req.GetVolumeId(), device, fsType, req.GetReadonly(), lvr); err != nil {
+				return err
+			}
+			if err := s.mounter.Mount(device, req.GetTargetPath(), mountOption.FsType, append(mountOptions, "defaults")); err != nil {
+				return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
+			}
+		} else {
+			// mkfs options recorded on the LogicalVolume are used only when the device is not formatted yet.
+			if err := s.mounter.FormatAndMountSensitiveWithFormatOptions(device, req.GetTargetPath(), mountOption.FsType, mountOptions, nil, lvr.Spec.MkfsOptions); err != nil {
+				return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
+			}
 		}
 		if err := os.Chmod(req.GetTargetPath(), 0777|os.ModeSetgid); err != nil {
 			return status.Errorf(codes.Internal, "chmod 2777 failed: target=%s, error=%v", req.GetTargetPath(), err)
@@ -319,12 +506,13 @@ func (s *nodeServerNoLocked) findVolumeByID(listResp *proto.GetLVListResponse, n
 	return nil
 }
 
-func (s *nodeServerNoLocked) getLvFromContext(ctx context.Context, deviceClass, volumeID string) (*proto.LogicalVolume, error) {
+// getLvFromContext returns the LV named lvName, which is LogicalVolume.LVName().
+func (s *nodeServerNoLocked) getLvFromContext(ctx context.Context, deviceClass, lvName string) (*proto.LogicalVolume, error) {
 	listResp, err := s.client.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list LV: %v", err)
 	}
-	return s.findVolumeByID(listResp, volumeID), nil
+	return s.findVolumeByID(listResp, lvName), nil
 }
 
 func (s *nodeServerNoLocked) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
@@ -347,11 +535,27 @@ func (s *nodeServerNoLocked) NodeUnpublishVolume(ctx context.Context, req *csi.N
 	if os.IsNotExist(err) {
 		// target_path does not exist, but device for mount-type PV may still exist.
 		_ = os.Remove(device)
+		remaining, err := removePublishRecord(publishDirectory, volumeID, targetPath)
+		if err != nil {
+			nodeLogger.Error(err, "failed to remove publish record", "volume_id", volumeID, "target_path", targetPath)
+		} else if remaining == 0 {
+			deleteIOLimitMetrics(volumeID)
+			delete(s.ioLimits, volumeID)
+			if err := s.closeEncryptedVolume(volumeID); err != nil {
+				nodeLogger.Error(err, "failed to close LUKS device", "volume_id", volumeID)
+			}
+		}
 		return &csi.NodeUnpublishVolumeResponse{}, nil
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "stat failed for %s: %v", targetPath, err)
 	}
 
+	ioDevice := device
+	if !info.IsDir() {
+		ioDevice = targetPath
+	}
+	s.nodeUnpublishIOLimits(volumeID, targetPath, ioDevice)
+
 	// remove device file if target_path is device, unmount target_path otherwise
 	if info.IsDir() {
 		err = s.nodeUnpublishFilesystemVolume(req, device)
@@ -361,9 +565,93 @@ func (s *nodeServerNoLocked) NodeUnpublishVolume(ctx context.Context, req *csi.N
 	if err != nil {
 		return nil, err
 	}
+	remaining, err := removePublishRecord(publishDirectory, volumeID, targetPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove publish record: volume=%s, target=%s, error=%v", volumeID, targetPath, err)
+	}
+	// The metrics are deleted and the LUKS device is closed only after the volume is unpublished from all the targets.
+	if remaining == 0 {
+		deleteIOLimitMetrics(volumeID)
+		delete(s.ioLimits, volumeID)
+		if err := s.closeEncryptedVolume(volumeID); err != nil {
+			return nil, err
+		}
+	}
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
+// nodeUnpublishIOLimits removes the I/O limits applied to the pod.
+// Failures are only logged because the pod cgroup is usually removed soon after.
+func (s *nodeServerNoLocked) nodeUnpublishIOLimits(volumeID, targetPath, device string) {
+	podUID := podUIDFromTargetPath(targetPath)
+	if podUID == "" {
+		return
+	}
+	var stat unix.Stat_t
+	if err := filesystem.Stat(device, &stat); err != nil {
+		return
+	}
+	if err := clearIOLimits(s.cgroupRoot, podUID, unix.Major(stat.Rdev), unix.Minor(stat.Rdev)); err != nil {
+		nodeLogger.Error(err, "failed to clear I/O limits", "volume_id", volumeID, "pod_uid", podUID)
+	}
+}
+
+// syncIOLimits applies the I/O limits of the LogicalVolume to the pods already using the volume
+// when they have been changed by ControllerModifyVolume since the volume was published.
+func (s *nodeServerNoLocked) syncIOLimits(ctx context.Context, volumeID string) error {
+	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	if lvr.Spec.IOLimits == nil {
+		return nil
+	}
+	limits := *lvr.Spec.IOLimits
+	if applied, ok := s.ioLimits[volumeID]; ok && applied == limits {
+		return nil
+	}
+
+	targets, err := publishedTargets(publishDirectory, volumeID)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	for _, target := range targets {
+		podUID := podUIDFromTargetPath(target)
+		if podUID == "" {
+			continue
+		}
+		// The device of a block volume is published at the target path.
+		device := filepath.Join(DeviceDirectory, volumeID)
+		if info, err := os.Stat(target); err == nil && !info.IsDir() {
+			device = target
+		}
+		var stat unix.Stat_t
+		if err := filesystem.Stat(device, &stat); err != nil {
+			if err == unix.ENOENT {
+				continue
+			}
+			return fmt.Errorf("stat failed for %s: %w", device, err)
+		}
+		err := applyIOLimits(s.cgroupRoot, podUID, unix.Major(stat.Rdev), unix.Minor(stat.Rdev), &limits)
+		if errors.Is(err, os.ErrNotExist) {
+			// the pod has already been removed.
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to apply I/O limits to pod %s: %w", podUID, err)
+		}
+	}
+	setIOLimitMetrics(s.nodeName, volumeID, &limits)
+	s.ioLimits[volumeID] = limits
+
+	nodeLogger.Info("applied changed I/O limits",
+		"volume_id", volumeID,
+		"limits", limits)
+	return nil
+}
+
 func (s *nodeServerNoLocked) nodeUnpublishFilesystemVolume(req *csi.NodeUnpublishVolumeRequest, device string) error {
 	targetPath := req.GetTargetPath()
 
@@ -422,6 +710,12 @@ func (s *nodeServerNoLocked) NodeGetVolumeStats(ctx context.Context, req *csi.No
 		return nil, status.Errorf(codes.Internal, "stat on %s was failed: %v", volumePath, err)
 	}
 
+	// kubelet calls NodeGetVolumeStats periodically, so the I/O limits changed after publishing are applied here.
+	// The failure is not fatal because the limits are applied again on the next call.
+	if err := s.syncIOLimits(ctx, volumeID); err != nil && err != k8s.ErrVolumeNotFound {
+		nodeLogger.Error(err, "failed to apply I/O limits", "volume_id", volumeID)
+	}
+
 	if (st.Mode & unix.S_IFMT) == unix.S_IFBLK {
 		f, err := os.Open(volumePath)
 		if err != nil {
@@ -454,6 +748,12 @@ func (s *nodeServerNoLocked) NodeGetVolumeStats(ctx context.Context, req *csi.No
 			Used:      int64(sfs.Blocks-sfs.Bfree) * int64(sfs.Frsize),
 			Available: int64(sfs.Bavail) * int64(sfs.Frsize),
 		})
+		// The usage is recorded for the automatic expansion of PVCs by topolvm-controller.
+		// The failure is not fatal because kubelet only collects the stats.
+		err := s.k8sLVService.UpdateFilesystemUsage(ctx, volumeID, usage[0].Total, usage[0].Used)
+		if err != nil && err != k8s.ErrVolumeNotFound {
+			nodeLogger.Error(err, "failed to update filesystem usage", "volume_id", volumeID)
+		}
 	}
 	if sfs.Files > 0 {
 		usage = append(usage, &csi.VolumeUsage{
@@ -488,7 +788,7 @@ func (s *nodeServerNoLocked) NodeExpandVolume(ctx context.Context, req *csi.Node
 	// because the filesystem can be resized without the requested size.
 	_, err := convertRequestCapacity(req.GetCapacityRange().GetRequiredBytes(), req.GetCapacityRange().GetLimitBytes())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, capacityRangeError(err)
 	}
 
 	// Device type (block or fs, fs type detection) checking will be removed after CSI v1.2.0
@@ -501,6 +801,11 @@ func (s *nodeServerNoLocked) NodeExpandVolume(ctx context.Context, req *csi.Node
 		return nil, status.Errorf(codes.Internal, "stat failed for %s: %v", volumePath, err)
 	}
 
+	// The LUKS device is resized even for block volumes because it does not follow the LV.
+	if err := s.resizeEncryptedVolume(volumeID, req.GetSecrets()); err != nil {
+		return nil, err
+	}
+
 	isBlock := !info.IsDir()
 	if isBlock {
 		nodeLogger.Info("NodeExpandVolume(block) is skipped",
@@ -513,18 +818,26 @@ func (s *nodeServerNoLocked) NodeExpandVolume(ctx context.Context, req *csi.Node
 	device := filepath.Join(DeviceDirectory, volumeID)
 	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
 	deviceClass := topolvm.DefaultDeviceClassName
+	lvName := volumeID
 	if err == nil {
 		deviceClass = lvr.Spec.DeviceClass
+		lvName = lvr.LVName()
 	} else if err != k8s.ErrVolumeNotFound {
 		return nil, err
 	}
-	lv, err := s.getLvFromContext(ctx, deviceClass, volumeID)
+	lv, err := s.getLvFromContext(ctx, deviceClass, lvName)
 	if err != nil {
 		return nil, err
 	}
 	if lv == nil {
 		return nil, status.Errorf(codes.NotFound, "failed to find LV: %s", volumeID)
 	}
+	if lvr != nil && lvr.Spec.Encrypted {
+		lv, err = s.encryptedVolume(volumeID, lv)
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = s.createDeviceIfNeeded(device, lv)
 	if err != nil {
 		return nil, err
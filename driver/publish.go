@@ -0,0 +1,69 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// publishDirectory records the target paths where the volumes are published.
+// A volume can be published to several targets on the same node, e.g. for pods sharing a PVC,
+// and the records tell whether the volume is still published when one of them is unpublished.
+const publishDirectory = DeviceDirectory + "/published"
+
+// publishRecord returns the path of the file recording that the volume is published at targetPath.
+func publishRecord(dir, volumeID, targetPath string) string {
+	sum := sha256.Sum256([]byte(targetPath))
+	return filepath.Join(dir, volumeID, hex.EncodeToString(sum[:]))
+}
+
+// addPublishRecord records that the volume is published at targetPath.
+func addPublishRecord(dir, volumeID, targetPath string) error {
+	record := publishRecord(dir, volumeID, targetPath)
+	if err := os.MkdirAll(filepath.Dir(record), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(record, []byte(targetPath), 0644)
+}
+
+// removePublishRecord removes the record of targetPath, and returns the number of the other targets
+// where the volume is still published. The volumes published before the records were introduced have no records.
+func removePublishRecord(dir, volumeID, targetPath string) (int, error) {
+	if err := os.Remove(publishRecord(dir, volumeID, targetPath)); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, volumeID))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		if err := os.Remove(filepath.Join(dir, volumeID)); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+// publishedTargets returns the target paths where the volume is published.
+func publishedTargets(dir, volumeID string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, volumeID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	targets := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, volumeID, entry.Name()))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		targets = append(targets, string(content))
+	}
+	return targets, nil
+}
@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestPublishRecord(t *testing.T) {
+	dir := t.TempDir()
+	target1 := "/var/lib/kubelet/pods/pod1/volumes/kubernetes.io~csi/pvc/mount"
+	target2 := "/var/lib/kubelet/pods/pod2/volumes/kubernetes.io~csi/pvc/mount"
+
+	n, err := removePublishRecord(dir, "vol1", target1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("volume without records should not be published: %d", n)
+	}
+
+	for _, target := range []string{target1, target2, target2} {
+		if err := addPublishRecord(dir, "vol1", target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := addPublishRecord(dir, "vol2", target1); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := publishedTargets(dir, "vol1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(targets)
+	if len(targets) != 2 || targets[0] != target1 || targets[1] != target2 {
+		t.Errorf("unexpected targets: %v", targets)
+	}
+
+	n, err = removePublishRecord(dir, "vol1", target1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("volume should be still published to 1 target: %d", n)
+	}
+	// unpublishing the same target again is idempotent.
+	n, err = removePublishRecord(dir, "vol1", target1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("volume should be still published to 1 target: %d", n)
+	}
+
+	n, err = removePublishRecord(dir, "vol1", target2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("volume should not be published: %d", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "vol1")); !os.IsNotExist(err) {
+		t.Errorf("directory of the volume should be removed: %v", err)
+	}
+	targets, err = publishedTargets(dir, "vol1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 0 {
+		t.Errorf("volume should not be published: %v", targets)
+	}
+	if _, err := os.Stat(publishRecord(dir, "vol2", target1)); err != nil {
+		t.Errorf("record of another volume should be kept: %v", err)
+	}
+}
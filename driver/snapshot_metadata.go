@@ -0,0 +1,152 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/driver/internal/k8s"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var snapshotMetadataLogger = ctrl.Log.WithName("driver").WithName("snapshot-metadata")
+
+// snapshotMetadataServer implements csi.SnapshotMetadataServer.
+// The block metadata is computed by lvmd from the thin pool where the snapshots are,
+// so it serves only the snapshots on the node.
+type snapshotMetadataServer struct {
+	csi.UnimplementedSnapshotMetadataServer
+
+	nodeName     string
+	lvService    proto.LVServiceClient
+	k8sLVService *k8s.LogicalVolumeService
+}
+
+// GetMetadataAllocated streams the ranges allocated in the snapshot.
+func (s *snapshotMetadataServer) GetMetadataAllocated(req *csi.GetMetadataAllocatedRequest, server csi.SnapshotMetadata_GetMetadataAllocatedServer) error {
+	snapshotMetadataLogger.Info("GetMetadataAllocated called",
+		"snapshot_id", req.GetSnapshotId(),
+		"starting_offset", req.GetStartingOffset(),
+		"max_results", req.GetMaxResults(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetSnapshotId() == "" {
+		return status.Error(codes.InvalidArgument, "missing snapshot id")
+	}
+	if req.GetStartingOffset() < 0 || req.GetMaxResults() < 0 {
+		return status.Error(codes.InvalidArgument, "starting offset and max results must not be negative")
+	}
+
+	ctx := server.Context()
+	snapshot, err := s.getSnapshot(ctx, req.GetSnapshotId())
+	if err != nil {
+		return err
+	}
+
+	return s.streamBlockMetadata(ctx, &proto.GetLVBlockMetadataRequest{
+		DeviceClass:    snapshot.Spec.DeviceClass,
+		TargetVolume:   snapshot.LVName(),
+		StartingOffset: uint64(req.GetStartingOffset()),
+		MaxResults:     uint32(req.GetMaxResults()),
+	}, func(volumeSize int64, blocks []*csi.BlockMetadata) error {
+		return server.Send(&csi.GetMetadataAllocatedResponse{
+			BlockMetadataType:   csi.BlockMetadataType_VARIABLE_LENGTH,
+			VolumeCapacityBytes: volumeSize,
+			BlockMetadata:       blocks,
+		})
+	})
+}
+
+// GetMetadataDelta streams the ranges changed between the base snapshot and the target snapshot.
+func (s *snapshotMetadataServer) GetMetadataDelta(req *csi.GetMetadataDeltaRequest, server csi.SnapshotMetadata_GetMetadataDeltaServer) error {
+	snapshotMetadataLogger.Info("GetMetadataDelta called",
+		"base_snapshot_id", req.GetBaseSnapshotId(),
+		"target_snapshot_id", req.GetTargetSnapshotId(),
+		"starting_offset", req.GetStartingOffset(),
+		"max_results", req.GetMaxResults(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetBaseSnapshotId() == "" {
+		return status.Error(codes.InvalidArgument, "missing base snapshot id")
+	}
+	if req.GetTargetSnapshotId() == "" {
+		return status.Error(codes.InvalidArgument, "missing target snapshot id")
+	}
+	if req.GetStartingOffset() < 0 || req.GetMaxResults() < 0 {
+		return status.Error(codes.InvalidArgument, "starting offset and max results must not be negative")
+	}
+
+	ctx := server.Context()
+	base, err := s.getSnapshot(ctx, req.GetBaseSnapshotId())
+	if err != nil {
+		return err
+	}
+	target, err := s.getSnapshot(ctx, req.GetTargetSnapshotId())
+	if err != nil {
+		return err
+	}
+	if base.Spec.DeviceClass != target.Spec.DeviceClass {
+		return status.Error(codes.InvalidArgument, "base and target snapshots must be in the same device class")
+	}
+
+	return s.streamBlockMetadata(ctx, &proto.GetLVBlockMetadataRequest{
+		DeviceClass:    target.Spec.DeviceClass,
+		TargetVolume:   target.LVName(),
+		BaseVolume:     base.LVName(),
+		StartingOffset: uint64(req.GetStartingOffset()),
+		MaxResults:     uint32(req.GetMaxResults()),
+	}, func(volumeSize int64, blocks []*csi.BlockMetadata) error {
+		return server.Send(&csi.GetMetadataDeltaResponse{
+			BlockMetadataType:   csi.BlockMetadataType_VARIABLE_LENGTH,
+			VolumeCapacityBytes: volumeSize,
+			BlockMetadata:       blocks,
+		})
+	})
+}
+
+func (s *snapshotMetadataServer) getSnapshot(ctx context.Context, snapshotID string) (*v1.LogicalVolume, error) {
+	snapshot, err := s.k8sLVService.GetVolume(ctx, snapshotID)
+	if err != nil {
+		if errors.Is(err, k8s.ErrVolumeNotFound) {
+			return nil, status.Errorf(codes.NotFound, "snapshot %s is not found", snapshotID)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if snapshot.Spec.NodeName != s.nodeName {
+		return nil, status.Errorf(codes.FailedPrecondition, "snapshot %s is not on node %s", snapshotID, s.nodeName)
+	}
+	return snapshot, nil
+}
+
+// streamBlockMetadata relays the responses of lvmd to send.
+func (s *snapshotMetadataServer) streamBlockMetadata(ctx context.Context, req *proto.GetLVBlockMetadataRequest, send func(int64, []*csi.BlockMetadata) error) error {
+	stream, err := s.lvService.GetLVBlockMetadata(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		blocks := make([]*csi.BlockMetadata, 0, len(resp.GetRanges()))
+		for _, r := range resp.GetRanges() {
+			blocks = append(blocks, &csi.BlockMetadata{
+				ByteOffset: int64(r.GetOffset()),
+				SizeBytes:  int64(r.GetLength()),
+			})
+		}
+		if err := send(int64(resp.GetVolumeSizeBytes()), blocks); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSnapshotMetadataValidation(t *testing.T) {
+	s := &snapshotMetadataServer{}
+
+	err := s.GetMetadataAllocated(&csi.GetMetadataAllocatedRequest{}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing snapshot id should be rejected: %v", err)
+	}
+	err = s.GetMetadataAllocated(&csi.GetMetadataAllocatedRequest{SnapshotId: "snap1", StartingOffset: -1}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative starting offset should be rejected: %v", err)
+	}
+	err = s.GetMetadataDelta(&csi.GetMetadataDeltaRequest{TargetSnapshotId: "snap2"}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing base snapshot id should be rejected: %v", err)
+	}
+	err = s.GetMetadataDelta(&csi.GetMetadataDeltaRequest{BaseSnapshotId: "snap1"}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing target snapshot id should be rejected: %v", err)
+	}
+	err = s.GetMetadataDelta(&csi.GetMetadataDeltaRequest{BaseSnapshotId: "snap1", TargetSnapshotId: "snap2", MaxResults: -1}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative max results should be rejected: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package driver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TransferServerName is the name that the certificates for volume transfer must have in their SANs.
+// All nodes share the name because they are connected by the addresses of the nodes.
+const TransferServerName = "topolvm-node"
+
+var transferLogger = ctrl.Log.WithName("driver").WithName("transfer")
+
+// LoadTransferCredentials loads the credentials for volume transfer from certDir.
+// certDir must have tls.crt, tls.key, and ca.crt. Both of the server and the client present the certificate in
+// tls.crt and verify the certificate of the peer with ca.crt, so that only topolvm-node can read volumes.
+// It returns the credentials for the server and the client respectively.
+func LoadTransferCredentials(certDir string) (credentials.TransportCredentials, credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key"))
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := os.ReadFile(filepath.Join(certDir, "ca.crt"))
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, nil, errors.New("no certificate is found in ca.crt")
+	}
+
+	server := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})
+	client := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   TransferServerName,
+		MinVersion:   tls.VersionTLS12,
+	})
+	return server, client, nil
+}
+
+// transferServer serves the contents of the volumes on the node to topolvm-node on other nodes.
+// It relays only ExportLV to lvmd.
+type transferServer struct {
+	proto.UnimplementedLVServiceServer
+
+	lvService proto.LVServiceClient
+}
+
+// NewTransferServer returns a new LVServiceServer to serve the contents of volumes to other nodes.
+func NewTransferServer(conn *grpc.ClientConn) proto.LVServiceServer {
+	return &transferServer{lvService: proto.NewLVServiceClient(conn)}
+}
+
+// ExportLV relays the contents of the volume exported by lvmd.
+func (s *transferServer) ExportLV(req *proto.ExportLVRequest, server proto.LVService_ExportLVServer) error {
+	transferLogger.Info("ExportLV called", "name", req.GetName(), "device_class", req.GetDeviceClass())
+
+	stream, err := s.lvService.ExportLV(server.Context(), req)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := server.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
+
+// AdvertiseTransferEndpoint records endpoint in the annotation of the node so that topolvm-node on other nodes
+// can copy volumes from the node. If endpoint is empty, the annotation is removed.
+func AdvertiseTransferEndpoint(ctx context.Context, c client.Client, nodeName, endpoint string) error {
+	var value interface{}
+	if endpoint != "" {
+		value = endpoint
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				topolvm.GetTransferEndpointKey(): value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	if err := c.Patch(ctx, node, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to annotate node %s: %w", nodeName, err)
+	}
+	return nil
+}
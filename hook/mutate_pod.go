@@ -210,8 +210,8 @@ func (m *podMutator) pvcCapacity(
 
 	var requested int64 = topolvm.DefaultSize
 	if req, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
-		if req.Value() > topolvm.DefaultSize {
-			requested = ((req.Value()-1)>>30 + 1) << 30
+		if req.Value() > 0 {
+			requested = (req.Value()-1)/topolvm.MinimumSectorSize*topolvm.MinimumSectorSize + topolvm.MinimumSectorSize
 		}
 	}
 	dc, ok := sc.Parameters[topolvm.GetDeviceClassKey()]
@@ -244,8 +244,8 @@ func (m *podMutator) ephemeralCapacity(
 
 	var requested int64 = topolvm.DefaultSize
 	if req, ok := volumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
-		if req.Value() > topolvm.DefaultSize {
-			requested = ((req.Value()-1)>>30 + 1) << 30
+		if req.Value() > 0 {
+			requested = (req.Value()-1)/topolvm.MinimumSectorSize*topolvm.MinimumSectorSize + topolvm.MinimumSectorSize
 		}
 	}
 	dc, ok := sc.Parameters[topolvm.GetDeviceClassKey()]
@@ -194,7 +194,7 @@ var _ = Describe("pod mutation webhook", func() {
 		capacity := pod.Annotations[topolvm.GetCapacityKeyPrefix()+"ssd"]
 		Expect(request.Value()).Should(BeNumerically("==", 1))
 		Expect(limit.Value()).Should(BeNumerically("==", 1))
-		Expect(capacity).Should(Equal(strconv.Itoa(1 << 30)))
+		Expect(capacity).Should(Equal(strconv.Itoa(100 << 20)))
 	})
 
 	It("should mutate pod w/ TopoLVM PVC on multiple volume groups", func() {
@@ -228,7 +228,7 @@ var _ = Describe("pod mutation webhook", func() {
 		capacity := pod.Annotations[topolvm.GetCapacityKeyPrefix()+"ssd"]
 		Expect(request.Value()).Should(BeNumerically("==", 1))
 		Expect(limit.Value()).Should(BeNumerically("==", 1))
-		Expect(capacity).Should(Equal(strconv.Itoa(1 << 30)))
+		Expect(capacity).Should(Equal(strconv.Itoa(100 << 20)))
 
 		request = pod.Spec.Containers[0].Resources.Requests[topolvm.GetCapacityResource()]
 		limit = pod.Spec.Containers[0].Resources.Limits[topolvm.GetCapacityResource()]
@@ -304,7 +304,7 @@ var _ = Describe("pod mutation webhook", func() {
 		capacity := pod.Annotations[topolvm.GetCapacityKeyPrefix()+"ssd"]
 		Expect(request.Value()).Should(BeNumerically("==", 1))
 		Expect(limit.Value()).Should(BeNumerically("==", 1))
-		Expect(capacity).Should(Equal(strconv.Itoa(1 << 30)))
+		Expect(capacity).Should(Equal(strconv.Itoa(100 << 20)))
 
 		mem := pod.Spec.Containers[0].Resources.Requests["memory"]
 		Expect(mem.Value()).Should(BeNumerically("==", 100))
@@ -366,7 +366,7 @@ var _ = Describe("pod mutation webhook", func() {
 		request := pod.Spec.Containers[0].Resources.Requests[topolvm.GetCapacityResource()]
 		capacity := pod.Annotations[topolvm.GetCapacityKeyPrefix()+"ssd"]
 		Expect(request.Value()).Should(BeNumerically("==", 1))
-		Expect(capacity).Should(Equal(strconv.Itoa(3 << 30)))
+		Expect(capacity).Should(Equal(strconv.Itoa(100<<20 + 2<<30)))
 	})
 
 	It("should handle PVC w/o storage class", func() {
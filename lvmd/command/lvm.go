@@ -2,11 +2,16 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cybozu-go/log"
@@ -16,6 +21,9 @@ const (
 	nsenter  = "/usr/bin/nsenter"
 	lvm      = "/sbin/lvm"
 	blockdev = "/sbin/blockdev"
+	blkdisc  = "/sbin/blkdiscard"
+	cmpCmd   = "/usr/bin/cmp"
+	dmsetup  = "/sbin/dmsetup"
 	cowMin   = 50
 	cowMax   = 300
 )
@@ -43,23 +51,49 @@ func callLVM(cmd string, args ...string) error {
 	return err
 }
 
+// LVMError is returned when an lvm sub-command fails.
+// The error message includes stderr of the command so that it can be
+// surfaced to users, e.g. in Kubernetes Events.
+type LVMError struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *LVMError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("lvm %s: %v", strings.Join(e.Args, " "), e.Err)
+	}
+	return fmt.Sprintf("lvm %s: %v: %s", strings.Join(e.Args, " "), e.Err, e.Stderr)
+}
+
+func (e *LVMError) Unwrap() error {
+	return e.Err
+}
+
 // callLVMWithStdout calls lvm sub-commands and returns stdout.
 // cmd is a name of sub-command.
 func callLVMWithStdout(cmd string, args ...string) ([]byte, error) {
-	var stdout bytes.Buffer
+	var stdout, stderr bytes.Buffer
 	args = append([]string{cmd}, args...)
 
 	c := wrapExecCommand(lvm, args...)
 	c.Env = os.Environ()
 	c.Env = append(c.Env, "LC_ALL=C")
 	c.Stdout = &stdout
-	c.Stderr = os.Stderr
+	c.Stderr = io.MultiWriter(os.Stderr, &stderr)
 
 	log.Info("invoking LVM command", map[string]interface{}{
 		"args": args,
 	})
-	err := c.Run()
-	return stdout.Bytes(), err
+	if err := c.Run(); err != nil {
+		return stdout.Bytes(), &LVMError{
+			Args:   args,
+			Stderr: strings.TrimSpace(stderr.String()),
+			Err:    err,
+		}
+	}
+	return stdout.Bytes(), nil
 }
 
 // LVInfo is a map of lv attributes to values.
@@ -102,6 +136,12 @@ func (g *VolumeGroup) Free() (uint64, error) {
 	return g.state.free, nil
 }
 
+// ExtentSize returns the physical extent size of the volume group in bytes.
+// The sizes of logical volumes are rounded up to a multiple of it.
+func (g *VolumeGroup) ExtentSize() uint64 {
+	return g.state.extentSize
+}
+
 // CreateVolumeGroup calls "vgcreate" to create a volume group.
 // name is for creating volume name. device is path to a PV.
 func CreateVolumeGroup(name, device string) (*VolumeGroup, error) {
@@ -215,7 +255,7 @@ func (g *VolumeGroup) ListVolumes() []*LogicalVolume {
 // lvcreateOptions are additional arguments to pass to lvcreate.
 func (g *VolumeGroup) CreateVolume(name string, size uint64, tags []string, stripe uint, stripeSize string,
 	lvcreateOptions []string) (*LogicalVolume, error) {
-	lvcreateArgs := []string{"-n", name, "-L", fmt.Sprintf("%vg", size>>30), "-W", "y", "-y"}
+	lvcreateArgs := []string{"-n", name, "-L", fmt.Sprintf("%vb", size), "-W", "y", "-y"}
 	for _, tag := range tags {
 		lvcreateArgs = append(lvcreateArgs, "--addtag")
 		lvcreateArgs = append(lvcreateArgs, tag)
@@ -353,7 +393,7 @@ func (t *ThinPool) FindVolume(name string) (*LogicalVolume, error) {
 // CreateVolume creates a thin volume from this pool.
 func (t *ThinPool) CreateVolume(name string, size uint64, tags []string, stripe uint, stripeSize string, lvcreateOptions []string) (*LogicalVolume, error) {
 
-	lvcreateArgs := []string{"-T", t.FullName(), "-n", name, "-V", fmt.Sprintf("%vg", size>>30), "-W", "y", "-y"}
+	lvcreateArgs := []string{"-T", t.FullName(), "-n", name, "-V", fmt.Sprintf("%vb", size), "-W", "y", "-y"}
 	for _, tag := range tags {
 		lvcreateArgs = append(lvcreateArgs, "--addtag")
 		lvcreateArgs = append(lvcreateArgs, tag)
@@ -438,6 +478,17 @@ func (l *LogicalVolume) Path() string {
 	return l.path
 }
 
+// OpenDevice opens the block device of the logical volume with flag such as os.O_RDONLY.
+// When lvmd runs in a container, the device is opened through the root directory of the init process
+// of the host because the device file may not exist in the container.
+func (l *LogicalVolume) OpenDevice(flag int) (*os.File, error) {
+	p := l.path
+	if Containerized {
+		p = path.Join("/proc/1/root", p)
+	}
+	return os.OpenFile(p, flag, 0)
+}
+
 // VG returns a volume group in which the volume is.
 func (l *LogicalVolume) VG() *VolumeGroup {
 	return l.vg
@@ -559,6 +610,147 @@ func (l *LogicalVolume) Snapshot(name string, cowSize uint64, tags []string, thi
 	return l.vg.FindVolume(name)
 }
 
+// Suspend suspends the device-mapper table of this volume.
+// The outstanding I/O is flushed, and the following I/O is blocked until Resume is called.
+func (l *LogicalVolume) Suspend() error {
+	return callDmsetup("suspend", "-j", strconv.FormatUint(uint64(l.devMajor), 10), "-m", strconv.FormatUint(uint64(l.devMinor), 10))
+}
+
+// Resume resumes the device-mapper table of this volume suspended by Suspend.
+func (l *LogicalVolume) Resume() error {
+	return callDmsetup("resume", "-j", strconv.FormatUint(uint64(l.devMajor), 10), "-m", strconv.FormatUint(uint64(l.devMinor), 10))
+}
+
+// The operations on the volumes used by SnapshotGroup, which are replaced in tests.
+var (
+	suspendVolume  = (*LogicalVolume).Suspend
+	resumeVolume   = (*LogicalVolume).Resume
+	removeVolume   = (*LogicalVolume).Remove
+	snapshotVolume = func(l *LogicalVolume, name string, tags []string) (*LogicalVolume, error) {
+		return l.Snapshot(name, 0, tags, true)
+	}
+)
+
+// suspendedVolumes resumes the volumes it suspended exactly once,
+// either when the snapshots are taken or when the deadline passes.
+type suspendedVolumes struct {
+	mu      sync.Mutex
+	volumes []*LogicalVolume
+	resumed bool
+}
+
+// suspend suspends l. l is resumed at once if the volumes have already been resumed.
+func (s *suspendedVolumes) suspend(l *LogicalVolume) error {
+	if err := suspendVolume(l); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resumed {
+		resume(l)
+		return errors.New("volumes were resumed while suspending them")
+	}
+	s.volumes = append(s.volumes, l)
+	return nil
+}
+
+// resumeAll resumes the suspended volumes, and returns false if they have already been resumed.
+func (s *suspendedVolumes) resumeAll() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resumed {
+		return false
+	}
+	s.resumed = true
+	for _, l := range s.volumes {
+		resume(l)
+	}
+	return true
+}
+
+func resume(l *LogicalVolume) {
+	if err := resumeVolume(l); err != nil {
+		log.Error("failed to resume volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      l.fullname,
+		})
+	}
+}
+
+// SnapshotGroup takes thin snapshots of the volumes at the same point in time.
+// names and tags are the names and the tags of the snapshots in the order of volumes.
+//
+// All the volumes are suspended before the first snapshot is taken and resumed after
+// all the snapshots are taken, so that the snapshots are crash-consistent with each other.
+// The I/O to the volumes is blocked while they are suspended, so a watchdog resumes them
+// when ctx is done, and then SnapshotGroup fails because the snapshots may not be consistent.
+// If it fails, the snapshots already taken are removed.
+func SnapshotGroup(ctx context.Context, volumes []*LogicalVolume, names []string, tags [][]string) ([]*LogicalVolume, error) {
+	for _, l := range volumes {
+		if l.pool == nil {
+			return nil, fmt.Errorf("group snapshots can be taken for only thin volumes: %s", l.fullname)
+		}
+	}
+
+	suspended := &suspendedVolumes{}
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		suspended.resumeAll()
+	}()
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if suspended.resumeAll() {
+				log.Error("resumed volumes because taking group snapshots did not finish in time", map[string]interface{}{
+					log.FnError: ctx.Err(),
+					"names":     names,
+				})
+			}
+		}
+	}()
+
+	snapshots := make([]*LogicalVolume, 0, len(volumes))
+	removeSnapshots := func() {
+		suspended.resumeAll()
+		for _, snap := range snapshots {
+			if err := removeVolume(snap); err != nil {
+				log.Error("failed to remove snapshot", map[string]interface{}{
+					log.FnError: err,
+					"name":      snap.fullname,
+				})
+			}
+		}
+	}
+
+	for _, l := range volumes {
+		if err := suspended.suspend(l); err != nil {
+			return nil, err
+		}
+	}
+	for i, l := range volumes {
+		if err := ctx.Err(); err != nil {
+			removeSnapshots()
+			return nil, err
+		}
+		// lvcreate resumes the origin after taking its snapshot, but the snapshots of
+		// the other volumes are not affected because they are still suspended.
+		snap, err := snapshotVolume(l, names[i], tags[i])
+		if err != nil {
+			removeSnapshots()
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if !suspended.resumeAll() {
+		// The watchdog resumed the volumes before all the snapshots were taken.
+		removeSnapshots()
+		return nil, fmt.Errorf("volumes were resumed before all the snapshots were taken: %w", ctx.Err())
+	}
+	return snapshots, nil
+}
+
 // Activate activates the logical volume for desired access.
 func (l *LogicalVolume) Activate(access string) error {
 	var lvchangeArgs []string
@@ -620,3 +812,109 @@ func (l *LogicalVolume) Rename(name string) error {
 	l.path = path.Join(path.Dir(l.path), l.name)
 	return nil
 }
+
+// AddTags adds tags to this volume.
+// Tags that the volume already has are ignored.
+func (l *LogicalVolume) AddTags(tags []string) error {
+	var added []string
+	var args []string
+	for _, tag := range tags {
+		if containsString(l.tags, tag) || containsString(added, tag) {
+			continue
+		}
+		added = append(added, tag)
+		args = append(args, "--addtag", tag)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := callLVM("lvchange", append(args, l.fullname)...); err != nil {
+		return err
+	}
+	l.tags = append(l.tags, added...)
+	return nil
+}
+
+// DelTags deletes tags from this volume.
+// Tags that the volume does not have are ignored.
+func (l *LogicalVolume) DelTags(tags []string) error {
+	var args []string
+	var remaining []string
+	for _, tag := range l.tags {
+		if containsString(tags, tag) {
+			args = append(args, "--deltag", tag)
+			continue
+		}
+		remaining = append(remaining, tag)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	if err := callLVM("lvchange", append(args, l.fullname)...); err != nil {
+		return err
+	}
+	l.tags = remaining
+	return nil
+}
+
+// SetReadAhead changes the read-ahead of this volume.
+// readAhead is "auto", "none" or the number of sectors.
+func (l *LogicalVolume) SetReadAhead(readAhead string) error {
+	return callLVM("lvchange", "--readahead", readAhead, l.fullname)
+}
+
+// Discard discards the blocks of this volume in the range of length bytes from offset.
+func (l *LogicalVolume) Discard(offset, length uint64) error {
+	return callBlkdiscard("-o", strconv.FormatUint(offset, 10), "-l", strconv.FormatUint(length, 10), l.path)
+}
+
+// ZeroOut overwrites this volume with zeros in the range of length bytes from offset.
+func (l *LogicalVolume) ZeroOut(offset, length uint64) error {
+	return callBlkdiscard("-z", "-o", strconv.FormatUint(offset, 10), "-l", strconv.FormatUint(length, 10), l.path)
+}
+
+// IsZero returns true if the range of length bytes from offset of this volume is read as zeros.
+func (l *LogicalVolume) IsZero(offset, length uint64) (bool, error) {
+	c := wrapExecCommand(cmpCmd, "-s", "-n", strconv.FormatUint(length, 10),
+		"-i", strconv.FormatUint(offset, 10)+":0", l.path, "/dev/zero")
+	c.Stderr = os.Stderr
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// cmp exits with 1 if the inputs differ.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func callBlkdiscard(args ...string) error {
+	c := wrapExecCommand(blkdisc, args...)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("blkdiscard failed: output=%s, error=%v", string(out), err)
+	}
+	return nil
+}
+
+func callDmsetup(args ...string) error {
+	c := wrapExecCommand(dmsetup, args...)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("dmsetup failed: output=%s, error=%v", string(out), err)
+	}
+	return nil
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -7,10 +7,11 @@ import (
 )
 
 type vg struct {
-	name string
-	uuid string
-	size uint64
-	free uint64
+	name       string
+	uuid       string
+	size       uint64
+	free       uint64
+	extentSize uint64
 }
 
 type lv struct {
@@ -41,6 +42,8 @@ func (u *vg) UnmarshalJSON(data []byte) error {
 		UUID string `json:"vg_uuid"`
 		Size string `json:"vg_size"`
 		Free string `json:"vg_free"`
+
+		ExtentSize string `json:"vg_extent_size"`
 	}
 
 	var temp vgInternal
@@ -60,6 +63,12 @@ func (u *vg) UnmarshalJSON(data []byte) error {
 	if convErr != nil {
 		return convErr
 	}
+	if len(temp.ExtentSize) > 0 {
+		u.extentSize, convErr = strconv.ParseUint(temp.ExtentSize, 10, 64)
+		if convErr != nil {
+			return convErr
+		}
+	}
 
 	return nil
 }
@@ -162,7 +171,7 @@ func getLVMState() ([]vg, []lv, error) {
 	args := []string{
 		"--reportformat", "json",
 		"--units", "b", "--nosuffix",
-		"--configreport", "vg", "-o", "vg_name,vg_uuid,vg_size,vg_free",
+		"--configreport", "vg", "-o", "vg_name,vg_uuid,vg_size,vg_free,vg_extent_size",
 		"--configreport", "lv", "-o", "lv_uuid,lv_name,lv_full_name,lv_path,lv_size," +
 			"lv_kernel_major,lv_kernel_minor,origin,origin_size,pool_lv,lv_tags," +
 			"lv_attr,vg_name,data_percent,metadata_percent,pool_lv",
@@ -17,7 +17,8 @@ func TestLvmJSON(t *testing.T) {
 				"vg_name": "myvg1",
 				"vg_uuid": "P8en82-LNUe-MERd-mOTT-XlAS-fkp8-1bleiB",
 				"vg_size": "2199014866944",
-				"vg_free": "2198482190336"
+				"vg_free": "2198482190336",
+				"vg_extent_size": "4194304"
 			  }
 			],
 			"pv": [
@@ -79,6 +80,10 @@ func TestLvmJSON(t *testing.T) {
 		t.Fatal("Incorrect number of VGs returned: ", len(vgs))
 	}
 
+	if vgs[0].extentSize != 4194304 {
+		t.Fatal("Incorrect extent size: ", vgs[0].extentSize)
+	}
+
 	lv := lvs[0]
 
 	if lv.uuid != "n3eoy5-R1B3-9S6A-rBwo-3n9f-mIxA-Dy4nnw" {
@@ -0,0 +1,140 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVolumeOps replaces the operations on the volumes used by SnapshotGroup.
+type fakeVolumeOps struct {
+	mu        sync.Mutex
+	suspended map[string]int
+	resumed   map[string]int
+	removed   []string
+	resumedCh chan struct{}
+}
+
+func setupFakeVolumeOps(t *testing.T, snapshot func(l *LogicalVolume) error) *fakeVolumeOps {
+	ops := &fakeVolumeOps{
+		suspended: make(map[string]int),
+		resumed:   make(map[string]int),
+		resumedCh: make(chan struct{}, 10),
+	}
+	origSuspend, origResume, origRemove, origSnapshot := suspendVolume, resumeVolume, removeVolume, snapshotVolume
+	t.Cleanup(func() {
+		suspendVolume, resumeVolume, removeVolume, snapshotVolume = origSuspend, origResume, origRemove, origSnapshot
+	})
+
+	suspendVolume = func(l *LogicalVolume) error {
+		ops.mu.Lock()
+		defer ops.mu.Unlock()
+		ops.suspended[l.fullname]++
+		return nil
+	}
+	resumeVolume = func(l *LogicalVolume) error {
+		ops.mu.Lock()
+		defer ops.mu.Unlock()
+		ops.resumed[l.fullname]++
+		ops.resumedCh <- struct{}{}
+		return nil
+	}
+	removeVolume = func(l *LogicalVolume) error {
+		ops.mu.Lock()
+		defer ops.mu.Unlock()
+		ops.removed = append(ops.removed, l.fullname)
+		return nil
+	}
+	snapshotVolume = func(l *LogicalVolume, name string, tags []string) (*LogicalVolume, error) {
+		if err := snapshot(l); err != nil {
+			return nil, err
+		}
+		return &LogicalVolume{fullname: "vg/" + name}, nil
+	}
+	return ops
+}
+
+func (ops *fakeVolumeOps) check(t *testing.T, volumes []*LogicalVolume, removed []string) {
+	t.Helper()
+	ops.mu.Lock()
+	defer ops.mu.Unlock()
+	for _, l := range volumes {
+		if ops.suspended[l.fullname] != 1 {
+			t.Errorf("%s should be suspended once: %d", l.fullname, ops.suspended[l.fullname])
+		}
+		if ops.resumed[l.fullname] != 1 {
+			t.Errorf("%s should be resumed once: %d", l.fullname, ops.resumed[l.fullname])
+		}
+	}
+	if len(ops.removed) != len(removed) {
+		t.Fatalf("unexpected removed snapshots: %v", ops.removed)
+	}
+	for i := range removed {
+		if ops.removed[i] != removed[i] {
+			t.Errorf("unexpected removed snapshots: %v", ops.removed)
+		}
+	}
+}
+
+func TestSnapshotGroup(t *testing.T) {
+	pool := "pool"
+	volumes := []*LogicalVolume{
+		{fullname: "vg/lv1", pool: &pool},
+		{fullname: "vg/lv2", pool: &pool},
+	}
+	names := []string{"snap1", "snap2"}
+	tags := [][]string{nil, nil}
+
+	t.Run("success", func(t *testing.T) {
+		ops := setupFakeVolumeOps(t, func(*LogicalVolume) error { return nil })
+		snapshots, err := SnapshotGroup(context.Background(), volumes, names, tags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(snapshots) != 2 {
+			t.Errorf("unexpected snapshots: %v", snapshots)
+		}
+		ops.check(t, volumes, nil)
+	})
+
+	t.Run("snapshot error", func(t *testing.T) {
+		ops := setupFakeVolumeOps(t, func(l *LogicalVolume) error {
+			if l.fullname == "vg/lv2" {
+				return errors.New("lvcreate failed")
+			}
+			return nil
+		})
+		_, err := SnapshotGroup(context.Background(), volumes, names, tags)
+		if err == nil {
+			t.Fatal("should be error")
+		}
+		ops.check(t, volumes, []string{"vg/snap1"})
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		var ops *fakeVolumeOps
+		ops = setupFakeVolumeOps(t, func(l *LogicalVolume) error {
+			if l.fullname != "vg/lv2" {
+				return nil
+			}
+			// lvcreate hangs until the watchdog resumes the volumes.
+			for i := 0; i < len(volumes); i++ {
+				select {
+				case <-ops.resumedCh:
+				case <-time.After(10 * time.Second):
+					return errors.New("volumes were not resumed by the watchdog")
+				}
+			}
+			return nil
+		})
+		_, err := SnapshotGroup(ctx, volumes, names, tags)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("should be deadline exceeded: %v", err)
+		}
+		ops.check(t, volumes, []string{"vg/snap1", "vg/snap2"})
+	})
+}
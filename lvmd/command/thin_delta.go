@@ -0,0 +1,166 @@
+package command
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cybozu-go/log"
+)
+
+const (
+	thinDelta  = "/usr/sbin/thin_delta"
+	sectorSize = 512
+)
+
+// metadataSnapMu serializes the use of the metadata snapshots of thin pools
+// because a thin pool can hold only one metadata snapshot at a time.
+var metadataSnapMu sync.Mutex
+
+// BlockRange represents a range of blocks in a logical volume.
+type BlockRange struct {
+	// Offset is the byte offset of the range.
+	Offset uint64
+	// Length is the length of the range in bytes.
+	Length uint64
+}
+
+// BlockRanges returns the ranges of blocks of the thin volume target in ascending order.
+// If base is nil, the ranges allocated in target are returned.
+// Otherwise, the ranges whose contents differ between base and target are returned.
+//
+// The ranges are computed by thin_delta from a metadata snapshot of the thin pool,
+// so that they are consistent even if the pool is being written.
+func (t *ThinPool) BlockRanges(base, target *LogicalVolume) ([]BlockRange, error) {
+	for _, l := range []*LogicalVolume{base, target} {
+		if l != nil && (l.pool == nil || *l.pool != t.state.name) {
+			return nil, fmt.Errorf("%s is not a thin volume in %s", l.fullname, t.state.fullName)
+		}
+	}
+
+	targetID, err := target.thinID()
+	if err != nil {
+		return nil, err
+	}
+	// thin_delta reports all the mapped blocks as the same when a thin device is compared with itself.
+	baseID := targetID
+	if base != nil {
+		baseID, err = base.thinID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadataSnapMu.Lock()
+	defer metadataSnapMu.Unlock()
+
+	tpool := dmName(t.vg.Name(), t.state.name) + "-tpool"
+	if err := callDmsetup("message", tpool, "0", "reserve_metadata_snap"); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := callDmsetup("message", tpool, "0", "release_metadata_snap"); err != nil {
+			log.Error("failed to release metadata snapshot", map[string]interface{}{
+				log.FnError: err,
+				"pool":      t.state.fullName,
+			})
+		}
+	}()
+
+	var stdout, stderr bytes.Buffer
+	c := wrapExecCommand(thinDelta, "--metadata-snap",
+		"--snap1", strconv.FormatUint(baseID, 10),
+		"--snap2", strconv.FormatUint(targetID, 10),
+		"/dev/mapper/"+dmName(t.vg.Name(), t.state.name+"_tmeta"))
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("thin_delta failed: output=%s, error=%v", stderr.String(), err)
+	}
+
+	return parseThinDelta(&stdout, base == nil)
+}
+
+// thinID returns the device id of the thin volume in its thin pool.
+func (l *LogicalVolume) thinID() (uint64, error) {
+	out, err := callLVMWithStdout("lvs", "--noheadings", "-o", "thin_id", l.fullname)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+}
+
+// dmName returns the device-mapper name of the logical volume.
+// Hyphens in the names are doubled by LVM so that the separator is unambiguous.
+func dmName(vgName, lvName string) string {
+	return strings.ReplaceAll(vgName, "-", "--") + "-" + strings.ReplaceAll(lvName, "-", "--")
+}
+
+// parseThinDelta parses the XML output of thin_delta.
+// If allocated is true, the ranges mapped in both devices are returned.
+// Otherwise, the ranges that differ between the devices are returned.
+// Adjacent ranges are merged.
+func parseThinDelta(r io.Reader, allocated bool) ([]BlockRange, error) {
+	var blockSize uint64
+	var ranges []BlockRange
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		elem, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch elem.Name.Local {
+		case "superblock":
+			sectors, err := xmlUintAttr(elem, "data_block_size")
+			if err != nil {
+				return nil, err
+			}
+			blockSize = sectors * sectorSize
+		case "same", "different", "left_only", "right_only":
+			if (elem.Name.Local == "same") != allocated {
+				continue
+			}
+			if blockSize == 0 {
+				return nil, fmt.Errorf("data block size is not found in thin_delta output")
+			}
+			begin, err := xmlUintAttr(elem, "begin")
+			if err != nil {
+				return nil, err
+			}
+			length, err := xmlUintAttr(elem, "length")
+			if err != nil {
+				return nil, err
+			}
+
+			br := BlockRange{Offset: begin * blockSize, Length: length * blockSize}
+			if n := len(ranges); n > 0 && ranges[n-1].Offset+ranges[n-1].Length == br.Offset {
+				ranges[n-1].Length += br.Length
+				continue
+			}
+			ranges = append(ranges, br)
+		}
+	}
+	return ranges, nil
+}
+
+func xmlUintAttr(elem xml.StartElement, name string) (uint64, error) {
+	for _, attr := range elem.Attr {
+		if attr.Name.Local == name {
+			return strconv.ParseUint(attr.Value, 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("attribute %s is not found in %s", name, elem.Name.Local)
+}
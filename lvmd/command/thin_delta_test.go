@@ -0,0 +1,57 @@
+package command
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseThinDelta(t *testing.T) {
+	// data_block_size is 128 sectors, i.e. 64 KiB.
+	output := `<superblock uuid="" time="2" transaction="4" data_block_size="128" nr_data_blocks="16384">
+  <diff left="1" right="2">
+    <same begin="0" length="16"/>
+    <different begin="16" length="4"/>
+    <right_only begin="20" length="2"/>
+    <same begin="22" length="10"/>
+    <left_only begin="40" length="1"/>
+  </diff>
+</superblock>
+`
+	const blockSize = 64 << 10
+
+	ranges, err := parseThinDelta(strings.NewReader(output), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []BlockRange{
+		{Offset: 16 * blockSize, Length: 6 * blockSize},
+		{Offset: 40 * blockSize, Length: 1 * blockSize},
+	}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("unexpected changed ranges: expected=%v, actual=%v", expected, ranges)
+	}
+
+	ranges, err = parseThinDelta(strings.NewReader(output), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []BlockRange{
+		{Offset: 0, Length: 16 * blockSize},
+		{Offset: 22 * blockSize, Length: 10 * blockSize},
+	}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("unexpected allocated ranges: expected=%v, actual=%v", expected, ranges)
+	}
+
+	_, err = parseThinDelta(strings.NewReader(`<diff left="1" right="2"><different begin="0" length="1"/></diff>`), false)
+	if err == nil {
+		t.Error("output without data block size should be rejected")
+	}
+}
+
+func TestDMName(t *testing.T) {
+	if name := dmName("my-vg", "pool-1_tmeta"); name != "my--vg-pool--1_tmeta" {
+		t.Errorf("unexpected name: %s", name)
+	}
+}
@@ -19,6 +19,21 @@ const (
 	TypeThick      = DeviceType("thick")
 )
 
+// WipePolicy is the way to wipe logical volumes before removing them.
+type WipePolicy string
+
+const (
+	// WipeNone removes logical volumes without wiping them.
+	WipeNone = WipePolicy("none")
+	// WipeBlkdiscard discards all blocks of logical volumes.
+	WipeBlkdiscard = WipePolicy("blkdiscard")
+	// WipeZeroFill overwrites logical volumes with zeros.
+	WipeZeroFill = WipePolicy("zero-fill")
+	// WipeDiscardThenVerify discards all blocks of logical volumes and then
+	// overwrites the blocks that are not read as zeros.
+	WipeDiscardThenVerify = WipePolicy("discard-then-verify")
+)
+
 // This regexp is based on the following validation:
 //
 //	https://github.com/kubernetes/apimachinery/blob/v0.18.3/pkg/util/validation/validation.go#L42
@@ -56,6 +71,11 @@ type DeviceClass struct {
 	Type DeviceType `json:"type"`
 	// ThinPoolConfig holds the configuration for thinpool in this volume group corresponding to the device-class
 	ThinPoolConfig *ThinPoolConfig `json:"thin-pool"`
+	// WipePolicy is the way to wipe logical volumes before removing them, defaults to 'none'
+	WipePolicy WipePolicy `json:"wipe-policy"`
+	// LVNameTemplate is the Go template to generate the names of logical volumes.
+	// The names are the requested ones, i.e. the UIDs of LogicalVolumes, if it is empty.
+	LVNameTemplate string `json:"lv-name-template"`
 }
 
 // GetSpare returns spare in bytes for the device-class
@@ -100,6 +120,12 @@ func ValidateDeviceClasses(deviceClasses []*DeviceClass) error {
 			return fmt.Errorf("target 'type' of device-class can be one of '%[1]s' or '%[2]s' or empty to default to '%[1]s'", TypeThick, TypeThin)
 		}
 
+		switch dc.WipePolicy {
+		case "", WipeNone, WipeBlkdiscard, WipeZeroFill, WipeDiscardThenVerify:
+		default:
+			return fmt.Errorf("wipe-policy of device-class can be one of '%s', '%s', '%s' or '%s': %s", WipeNone, WipeBlkdiscard, WipeZeroFill, WipeDiscardThenVerify, dc.Name)
+		}
+
 		name := dc.VolumeGroup
 
 		// thinpool validation, ignore any thinpoolconfig if Type is not TypeThin
@@ -116,6 +142,11 @@ func ValidateDeviceClasses(deviceClasses []*DeviceClass) error {
 			if dc.ThinPoolConfig.OverprovisionRatio < 1.0 {
 				return fmt.Errorf("overprovision ratio for thin pool %s in device class %s should be greater than 1.0", dc.ThinPoolConfig.Name, dc.Name)
 			}
+			// Writing zeros to thin volumes allocates all of their blocks in the thin pool,
+			// while discarded blocks of thin volumes are unmapped and read as zeros.
+			if dc.WipePolicy == WipeZeroFill || dc.WipePolicy == WipeDiscardThenVerify {
+				return fmt.Errorf("wipe-policy '%s' cannot be used for thin device class, use '%s' instead: %s", dc.WipePolicy, WipeBlkdiscard, dc.Name)
+			}
 			// combination of volumegroup and thinpool should be unique across device classes
 			// so the key 'name' shouldn't appear twice to verify it's uniqueness
 			name = name + "/" + dc.ThinPoolConfig.Name
@@ -130,6 +161,11 @@ func ValidateDeviceClasses(deviceClasses []*DeviceClass) error {
 		if dc.StripeSize != "" && !stripeSizeRegexp.MatchString(dc.StripeSize) {
 			return fmt.Errorf("stripe-size format is \"Size[k|UNIT]\": %s", dc.Name)
 		}
+		if dc.LVNameTemplate != "" {
+			if _, err := parseLVNameTemplate(dc.LVNameTemplate); err != nil {
+				return fmt.Errorf("invalid lv-name-template: %s: %w", dc.Name, err)
+			}
+		}
 	}
 	if countDefault != 1 {
 		return errors.New("should have only one default device-class")
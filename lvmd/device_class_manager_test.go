@@ -370,6 +370,114 @@ func TestValidateDeviceClasses(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "ssd",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					WipePolicy:  WipeDiscardThenVerify,
+				},
+				{
+					Name:        "hdd",
+					VolumeGroup: "node1-myvg2",
+					WipePolicy:  WipeZeroFill,
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "ssd",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					WipePolicy:  WipePolicy("shred"),
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "thin",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					Type:        TypeThin,
+					WipePolicy:  WipeBlkdiscard,
+					ThinPoolConfig: &ThinPoolConfig{
+						Name:               "pool0",
+						OverprovisionRatio: opRatio,
+					},
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "thin",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					Type:        TypeThin,
+					WipePolicy:  WipeZeroFill,
+					ThinPoolConfig: &ThinPoolConfig{
+						Name:               "pool0",
+						OverprovisionRatio: opRatio,
+					},
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "thin",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					Type:        TypeThin,
+					WipePolicy:  WipeDiscardThenVerify,
+					ThinPoolConfig: &ThinPoolConfig{
+						Name:               "pool0",
+						OverprovisionRatio: opRatio,
+					},
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:           "ssd",
+					VolumeGroup:    "node1-myvg1",
+					Default:        true,
+					LVNameTemplate: "{{.PVCNamespace}}-{{.PVCName}}-{{.ShortID}}",
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:           "ssd",
+					VolumeGroup:    "node1-myvg1",
+					Default:        true,
+					LVNameTemplate: "{{.PVCNamespace}}-{{.PVCName}}",
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:           "ssd",
+					VolumeGroup:    "node1-myvg1",
+					Default:        true,
+					LVNameTemplate: "{{.PVC}}-{{.ID}}",
+				},
+			},
+			valid: false,
+		},
 	}
 
 	for i, c := range cases {
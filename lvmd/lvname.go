@@ -0,0 +1,102 @@
+package lvmd
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/topolvm/topolvm"
+)
+
+const (
+	// shortIDLength is the length of ShortID in lv-name-template.
+	shortIDLength = 8
+	// maxLVNameLength is the maximum length of the names generated by lv-name-template.
+	// LVM allows 127 characters, and the room for importingSuffix is left.
+	maxLVNameLength = 127 - len(importingSuffix)
+)
+
+var (
+	// invalidLVNameChars matches the characters not allowed in LV names.
+	invalidLVNameChars = regexp.MustCompile(`[^A-Za-z0-9+_.-]+`)
+	repeatedHyphens    = regexp.MustCompile(`-{2,}`)
+)
+
+// lvNameParams is the data given to lv-name-template.
+type lvNameParams struct {
+	// ID is the requested name, i.e. the UID of the LogicalVolume.
+	ID string
+	// ShortID is the first 8 characters of ID.
+	ShortID string
+	// PVCNamespace and PVCName are the PersistentVolumeClaim the volume is provisioned for.
+	// They are taken from the tags of the request, and are empty if the request has no such tags.
+	PVCNamespace string
+	PVCName      string
+}
+
+func newLVNameParams(id string, tags []string) lvNameParams {
+	p := lvNameParams{ID: id, ShortID: id}
+	if len(id) > shortIDLength {
+		p.ShortID = id[:shortIDLength]
+	}
+	for _, tag := range tags {
+		switch {
+		case strings.HasPrefix(tag, topolvm.LVPVCNamespaceTagPrefix):
+			p.PVCNamespace = strings.TrimPrefix(tag, topolvm.LVPVCNamespaceTagPrefix)
+		case strings.HasPrefix(tag, topolvm.LVPVCNameTagPrefix):
+			p.PVCName = strings.TrimPrefix(tag, topolvm.LVPVCNameTagPrefix)
+		}
+	}
+	return p
+}
+
+// parseLVNameTemplate parses lv-name-template.
+// The template must refer to ID or ShortID so that the names are unique.
+func parseLVNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("lv-name-template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	name1, err := executeLVNameTemplate(tmpl, lvNameParams{ID: "id1", ShortID: "id1"})
+	if err != nil {
+		return nil, err
+	}
+	name2, err := executeLVNameTemplate(tmpl, lvNameParams{ID: "id2", ShortID: "id2"})
+	if err != nil {
+		return nil, err
+	}
+	if name1 == name2 {
+		return nil, errors.New("the template should refer to .ID or .ShortID")
+	}
+	return tmpl, nil
+}
+
+// executeLVNameTemplate generates the name from tmpl.
+// The characters not allowed in LV names are replaced with '-'.
+func executeLVNameTemplate(tmpl *template.Template, p lvNameParams) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, p); err != nil {
+		return "", err
+	}
+	name := invalidLVNameChars.ReplaceAllString(b.String(), "-")
+	name = repeatedHyphens.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-"), nil
+}
+
+// lvName returns the name of the volume created in the device class for the requested name and tags.
+// The requested name is returned as is if lv-name-template is not set or the generated name is not usable.
+func (c *DeviceClass) lvName(name string, tags []string) string {
+	if c.LVNameTemplate == "" {
+		return name
+	}
+	tmpl, err := parseLVNameTemplate(c.LVNameTemplate)
+	if err != nil {
+		return name
+	}
+	generated, err := executeLVNameTemplate(tmpl, newLVNameParams(name, tags))
+	if err != nil || generated == "" || generated == "." || generated == ".." || len(generated) > maxLVNameLength {
+		return name
+	}
+	return generated
+}
@@ -0,0 +1,70 @@
+package lvmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+)
+
+func TestLVName(t *testing.T) {
+	const id = "e6a46a8d-5d6b-4b0e-9c35-ccd8d2bd4a6e"
+	tags := []string{
+		topolvm.GetLVOwnerTag("lv"),
+		topolvm.LVPVCNamespaceTagPrefix + "default",
+		topolvm.LVPVCNameTagPrefix + "data-mysql-0",
+	}
+
+	cases := []struct {
+		template string
+		tags     []string
+		expected string
+	}{
+		{
+			template: "",
+			tags:     tags,
+			expected: id,
+		},
+		{
+			template: "{{.PVCNamespace}}-{{.PVCName}}-{{.ShortID}}",
+			tags:     tags,
+			expected: "default-data-mysql-0-e6a46a8d",
+		},
+		{
+			template: "pvc_{{.PVCNamespace}}_{{.PVCName}}_{{.ID}}",
+			tags:     tags,
+			expected: "pvc_default_data-mysql-0_" + id,
+		},
+		{
+			// The PVC is unknown.
+			template: "{{.PVCNamespace}}-{{.PVCName}}-{{.ShortID}}",
+			expected: "e6a46a8d",
+		},
+		{
+			// Invalid characters are replaced.
+			template: "{{.PVCNamespace}}/{{.PVCName}}@{{.ShortID}}",
+			tags:     tags,
+			expected: "default-data-mysql-0-e6a46a8d",
+		},
+		{
+			// Too long names are not used.
+			template: strings.Repeat("x", maxLVNameLength) + "{{.ShortID}}",
+			tags:     tags,
+			expected: id,
+		},
+		{
+			// Invalid templates are ignored.
+			template: "{{.PVCName}}",
+			tags:     tags,
+			expected: id,
+		},
+	}
+
+	for _, c := range cases {
+		dc := &DeviceClass{LVNameTemplate: c.template}
+		actual := dc.lvName(id, c.tags)
+		if actual != c.expected {
+			t.Errorf("template %q: expected %q, got %q", c.template, c.expected, actual)
+		}
+	}
+}
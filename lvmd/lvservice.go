@@ -2,8 +2,13 @@ package lvmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/cybozu-go/log"
 	"github.com/topolvm/topolvm/lvmd/command"
@@ -18,6 +23,7 @@ func NewLVService(dcmapper *DeviceClassManager, ocmapper *LvcreateOptionClassMan
 		dcmapper:   dcmapper,
 		ocmapper:   ocmapper,
 		notifyFunc: notifyFunc,
+		wipes:      newWipeJobs(),
 	}
 }
 
@@ -26,6 +32,7 @@ type lvService struct {
 	dcmapper   *DeviceClassManager
 	ocmapper   *LvcreateOptionClassManager
 	notifyFunc func()
+	wipes      *wipeJobs
 }
 
 func (s *lvService) notify() {
@@ -36,6 +43,66 @@ func (s *lvService) notify() {
 }
 
 func (s *lvService) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*proto.CreateLVResponse, error) {
+	lv, err := s.createLV(&proto.CreateLVRequest{
+		Name:                s.lvName(req.GetDeviceClass(), req.GetName(), req.GetTags()),
+		SizeGb:              req.GetSizeGb(),
+		SizeBytes:           req.GetSizeBytes(),
+		Tags:                req.GetTags(),
+		DeviceClass:         req.GetDeviceClass(),
+		LvcreateOptionClass: req.GetLvcreateOptionClass(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify()
+
+	log.Info("created a new LV", map[string]interface{}{
+		"name": lv.Name(),
+		"size": lv.Size(),
+	})
+
+	return &proto.CreateLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:      lv.Name(),
+			SizeGb:    lv.Size() >> 30,
+			SizeBytes: lv.Size(),
+			DevMajor:  lv.MajorNumber(),
+			DevMinor:  lv.MinorNumber(),
+		},
+	}, nil
+}
+
+// lvName returns the name of the volume created in the device class for the requested name and tags.
+func (s *lvService) lvName(deviceClass, name string, tags []string) string {
+	dc, err := s.dcmapper.DeviceClass(deviceClass)
+	if err != nil {
+		// The error is reported when the volume is created.
+		return name
+	}
+	return dc.lvName(name, tags)
+}
+
+// requestedSize returns the requested volume size in bytes.
+// sizeGb is used only for the clients that do not set sizeBytes.
+func requestedSize(sizeBytes, sizeGb uint64) uint64 {
+	if sizeBytes != 0 {
+		return sizeBytes
+	}
+	return sizeGb << 30
+}
+
+// alignSize rounds size up to a multiple of the extent size of vg, as LVM does for the volumes.
+func alignSize(vg *command.VolumeGroup, size uint64) uint64 {
+	extent := vg.ExtentSize()
+	if extent == 0 {
+		return size
+	}
+	return (size + extent - 1) / extent * extent
+}
+
+// createLV creates a logical volume in the device class as requested.
+func (s *lvService) createLV(req *proto.CreateLVRequest) (*command.LogicalVolume, error) {
 	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
@@ -45,7 +112,7 @@ func (s *lvService) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*pr
 		return nil, err
 	}
 	oc := s.ocmapper.LvcreateOptionClass(req.LvcreateOptionClass)
-	requested := req.GetSizeGb() << 30
+	requested := alignSize(vg, requestedSize(req.GetSizeBytes(), req.GetSizeGb()))
 	free := uint64(0)
 	var pool *command.ThinPool
 	switch dc.Type {
@@ -122,25 +189,10 @@ func (s *lvService) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*pr
 		})
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-
-	s.notify()
-
-	log.Info("created a new LV", map[string]interface{}{
-		"name": req.GetName(),
-		"size": requested,
-	})
-
-	return &proto.CreateLVResponse{
-		Volume: &proto.LogicalVolume{
-			Name:     lv.Name(),
-			SizeGb:   lv.Size() >> 30,
-			DevMajor: lv.MajorNumber(),
-			DevMinor: lv.MinorNumber(),
-		},
-	}, nil
+	return lv, nil
 }
 
-func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*proto.Empty, error) {
+func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*proto.RemoveLVResponse, error) {
 	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
@@ -149,6 +201,23 @@ func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*pr
 	if err != nil {
 		return nil, err
 	}
+
+	key := dc.VolumeGroup + "/" + req.GetName()
+	if job := s.wipes.get(key); job != nil {
+		if !job.finished() {
+			return &proto.RemoveLVResponse{
+				Wiping:     true,
+				WipedBytes: job.wiped.Load(),
+				TotalBytes: job.total,
+			}, nil
+		}
+		// the failed job is forgotten here so that the next request retries it.
+		s.wipes.delete(key)
+		if job.err != nil {
+			return nil, status.Error(codes.Internal, job.err.Error())
+		}
+	}
+
 	// ListVolumes on VolumeGroup or ThinPool returns ThinLogicalVolumes as well
 	// and no special handling for removal of LogicalVolume is needed
 	for _, lv := range vg.ListVolumes() {
@@ -156,22 +225,233 @@ func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*pr
 			continue
 		}
 
-		err = lv.Remove()
+		policy := dc.WipePolicy
+		if lv.IsSnapshot() {
+			// snapshots share their blocks with the source volumes and may be read-only.
+			policy = WipeNone
+		}
+		if policy == "" || policy == WipeNone {
+			if err := s.removeVolume(lv); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			break
+		}
+
+		if !req.GetAsyncWipe() {
+			if err := s.wipeAndRemoveVolume(lv, policy, func(uint64) {}); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			break
+		}
+
+		job := s.wipes.start(key, lv.Size(), func(progress func(uint64)) error {
+			return s.wipeAndRemoveVolume(lv, policy, progress)
+		})
+		return &proto.RemoveLVResponse{
+			Wiping:     true,
+			WipedBytes: job.wiped.Load(),
+			TotalBytes: job.total,
+		}, nil
+	}
+
+	return &proto.RemoveLVResponse{}, nil
+}
+
+func (s *lvService) wipeAndRemoveVolume(lv *command.LogicalVolume, policy WipePolicy, progress func(uint64)) error {
+	log.Info("wiping a LV", map[string]interface{}{
+		"name":   lv.Name(),
+		"policy": policy,
+		"size":   lv.Size(),
+	})
+	if err := wipe(lv, policy, progress); err != nil {
+		log.Error("failed to wipe volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      lv.Name(),
+			"policy":    policy,
+		})
+		return err
+	}
+	return s.removeVolume(lv)
+}
+
+func (s *lvService) removeVolume(lv *command.LogicalVolume) error {
+	if err := lv.Remove(); err != nil {
+		log.Error("failed to remove volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      lv.Name(),
+		})
+		return err
+	}
+	s.notify()
+
+	log.Info("removed a LV", map[string]interface{}{
+		"name": lv.Name(),
+	})
+	return nil
+}
+
+func (s *lvService) AdoptLV(_ context.Context, req *proto.AdoptLVRequest) (*proto.AdoptLVResponse, error) {
+	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	newName := req.GetNewName()
+	if newName != "" {
+		newName = dc.lvName(newName, req.GetTags())
+	}
+	lv, err := vg.FindVolume(req.GetName())
+	if err == command.ErrNotFound && newName != "" {
+		// The volume may have been renamed by the previous request.
+		lv, err = vg.FindVolume(newName)
+	}
+	if err == command.ErrNotFound {
+		log.Error("logical volume is not found", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", req.GetName())
+	}
+	if err != nil {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// verify that the volume belongs to the device class
+	switch dc.Type {
+	case TypeThick:
+		if lv.IsThin() {
+			return nil, status.Errorf(codes.FailedPrecondition, "logical volume %s is a thin volume but device class %s is thick", req.GetName(), req.DeviceClass)
+		}
+	case TypeThin:
+		if !lv.IsThin() {
+			return nil, status.Errorf(codes.FailedPrecondition, "logical volume %s is not a thin volume but device class %s is thin", req.GetName(), req.DeviceClass)
+		}
+		pool, err := lv.Pool()
 		if err != nil {
-			log.Error("failed to remove volume", map[string]interface{}{
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if pool.Name() != dc.ThinPoolConfig.Name {
+			return nil, status.Errorf(codes.FailedPrecondition, "logical volume %s is not in the thin pool %s", req.GetName(), dc.ThinPoolConfig.Name)
+		}
+	default:
+		return nil, status.Error(codes.Internal, fmt.Sprintf("unsupported device class target: %s", dc.Type))
+	}
+
+	if newName != "" && lv.Name() != newName {
+		if err := lv.Rename(newName); err != nil {
+			log.Error("failed to rename volume", map[string]interface{}{
 				log.FnError: err,
-				"name":      lv.Name(),
+				"name":      req.GetName(),
+				"new_name":  newName,
 			})
 			return nil, status.Error(codes.Internal, err.Error())
 		}
-		s.notify()
+	}
+	if err := lv.AddTags(req.GetTags()); err != nil {
+		log.Error("failed to add tags to volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      lv.Name(),
+			"tags":      req.GetTags(),
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.notify()
+
+	log.Info("adopted a LV", map[string]interface{}{
+		"name":         req.GetName(),
+		"new_name":     lv.Name(),
+		"size":         lv.Size(),
+		"tags":         lv.Tags(),
+		"device_class": req.DeviceClass,
+	})
+
+	return &proto.AdoptLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:      lv.Name(),
+			SizeGb:    lv.Size() >> 30,
+			SizeBytes: lv.Size(),
+			DevMajor:  lv.MajorNumber(),
+			DevMinor:  lv.MinorNumber(),
+			Tags:      lv.Tags(),
+		},
+	}, nil
+}
+
+func (s *lvService) ModifyLV(_ context.Context, req *proto.ModifyLVRequest) (*proto.Empty, error) {
+	readAhead := req.GetReadAhead()
+	if readAhead != "" && readAhead != "auto" && readAhead != "none" {
+		if _, err := strconv.ParseUint(readAhead, 10, 32); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid read-ahead: %s", readAhead)
+		}
+	}
+
+	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return nil, err
+	}
 
-		log.Info("removed a LV", map[string]interface{}{
-			"name": req.GetName(),
+	lv, err := vg.FindVolume(req.GetName())
+	if err == command.ErrNotFound {
+		log.Error("logical volume is not found", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", req.GetName())
+	}
+	if err != nil {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
 		})
-		break
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if readAhead != "" {
+		if err := lv.SetReadAhead(readAhead); err != nil {
+			log.Error("failed to change read-ahead of volume", map[string]interface{}{
+				log.FnError:  err,
+				"name":       req.GetName(),
+				"read_ahead": readAhead,
+			})
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := lv.DelTags(req.GetDelTags()); err != nil {
+		log.Error("failed to delete tags from volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+			"tags":      req.GetDelTags(),
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := lv.AddTags(req.GetAddTags()); err != nil {
+		log.Error("failed to add tags to volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+			"tags":      req.GetAddTags(),
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	log.Info("modified a LV", map[string]interface{}{
+		"name":         req.GetName(),
+		"read_ahead":   readAhead,
+		"tags":         lv.Tags(),
+		"device_class": req.DeviceClass,
+	})
 	return &proto.Empty{}, nil
 }
 
@@ -195,6 +475,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 	if err != nil {
 		return nil, err
 	}
+	name := dc.lvName(req.GetName(), req.GetTags())
 
 	// Fetch the source logical volume
 	sourceVolume := req.GetSourceVolume()
@@ -221,7 +502,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 	// In case of thin-snapshots, the size is the same as the source volume on snapshot creation, and then
 	// gets resized after extension into the correct size
 	sizeOnCreation := sourceLV.Size()
-	desiredSize := req.GetSizeGb() << 30
+	desiredSize := alignSize(vg, requestedSize(req.GetSizeBytes(), req.GetSizeGb()))
 
 	// in case there is no desired size in the request, we can still attempt to create the Snapshot with Source size.
 	if desiredSize == 0 {
@@ -233,7 +514,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 	}
 
 	log.Info("lvservice req", map[string]interface{}{
-		"name":           req.Name,
+		"name":           name,
 		"sizeOnCreation": sizeOnCreation,
 		"desiredSize":    desiredSize,
 		"sourceVol":      sourceVolume,
@@ -241,11 +522,11 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 		"accessType":     req.GetAccessType(),
 	})
 	// Create snapshot lv
-	snapLV, err := sourceLV.Snapshot(req.GetName(), sizeOnCreation, req.GetTags(), sourceLV.IsThin())
+	snapLV, err := sourceLV.Snapshot(name, sizeOnCreation, req.GetTags(), sourceLV.IsThin())
 	if err != nil {
 		log.Error("failed to create snapshot volume", map[string]interface{}{
 			log.FnError: err,
-			"name":      req.GetName(),
+			"name":      name,
 		})
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -253,7 +534,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 	if err := snapLV.Resize(desiredSize); err != nil {
 		log.Error("failed to extend snapshot after creation to desired size", map[string]interface{}{
 			log.FnError: err,
-			"name":      req.GetName(),
+			"name":      name,
 		})
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -262,7 +543,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 	if err := snapLV.Activate(req.AccessType); err != nil {
 		log.Error("failed to activate snap volume, deleting snapshot", map[string]interface{}{
 			log.FnError: err,
-			"name":      req.GetName(),
+			"name":      name,
 		})
 		err := snapLV.Remove()
 		if err != nil {
@@ -272,7 +553,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 			})
 		} else {
 			log.Info("deleted a snapshot", map[string]interface{}{
-				"name": req.GetName(),
+				"name": name,
 			})
 		}
 		return nil, status.Error(codes.Internal, err.Error())
@@ -281,7 +562,7 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 	s.notify()
 
 	log.Info("created a new snapshot LV", map[string]interface{}{
-		"name":       req.GetName(),
+		"name":       name,
 		"size":       desiredSize,
 		"accessType": req.AccessType,
 		"sourceID":   sourceVolume,
@@ -289,15 +570,571 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 
 	return &proto.CreateLVSnapshotResponse{
 		Snapshot: &proto.LogicalVolume{
-			Name:     snapLV.Name(),
-			SizeGb:   snapLV.Size() >> 30,
-			DevMajor: snapLV.MajorNumber(),
-			DevMinor: snapLV.MinorNumber(),
+			Name:      snapLV.Name(),
+			SizeGb:    snapLV.Size() >> 30,
+			SizeBytes: snapLV.Size(),
+			DevMajor:  snapLV.MajorNumber(),
+			DevMinor:  snapLV.MinorNumber(),
 		},
 	}, nil
 }
 
-func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*proto.Empty, error) {
+// groupSnapshotTimeout limits the time the source volumes of a group snapshot are suspended,
+// because the I/O to them is blocked meanwhile.
+const groupSnapshotTimeout = 30 * time.Second
+
+func (s *lvService) CreateLVGroupSnapshot(ctx context.Context, req *proto.CreateLVGroupSnapshotRequest) (*proto.CreateLVGroupSnapshotResponse, error) {
+	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	switch dc.Type {
+	case TypeThin:
+	case TypeThick:
+		return nil, status.Error(codes.Unimplemented, "device class is not thin. Thick snapshots are not implemented yet")
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid device class type %v", string(dc.Type))
+	}
+	if len(req.GetMembers()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no members are specified")
+	}
+
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := vg.FindPool(dc.ThinPoolConfig.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	sources := make([]*command.LogicalVolume, 0, len(req.GetMembers()))
+	names := make([]string, 0, len(req.GetMembers()))
+	tags := make([][]string, 0, len(req.GetMembers()))
+	seen := make(map[string]struct{})
+	for _, member := range req.GetMembers() {
+		sourceVolume := member.GetSourceVolume()
+		if _, ok := seen[sourceVolume]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "source logical volume %s is specified more than once", sourceVolume)
+		}
+		seen[sourceVolume] = struct{}{}
+		if member.GetAccessType() != "ro" && member.GetAccessType() != "rw" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid access type: %s", member.GetAccessType())
+		}
+
+		// Only the volumes in the thin pool of the device class can be suspended and snapshotted together.
+		sourceLV, err := pool.FindVolume(sourceVolume)
+		if err == command.ErrNotFound {
+			log.Error("source logical volume is not found", map[string]interface{}{
+				log.FnError: err,
+				"name":      sourceVolume,
+			})
+			return nil, status.Errorf(codes.NotFound, "source logical volume %s is not found", sourceVolume)
+		}
+		if err != nil {
+			log.Error("failed to find source volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      sourceVolume,
+			})
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		sources = append(sources, sourceLV)
+		names = append(names, dc.lvName(member.GetName(), member.GetTags()))
+		tags = append(tags, member.GetTags())
+	}
+
+	log.Info("lvservice req", map[string]interface{}{
+		"names":       names,
+		"deviceClass": req.GetDeviceClass(),
+	})
+	snapshotCtx, cancel := context.WithTimeout(ctx, groupSnapshotTimeout)
+	defer cancel()
+	snapLVs, err := command.SnapshotGroup(snapshotCtx, sources, names, tags)
+	if err != nil {
+		log.Error("failed to create group snapshot", map[string]interface{}{
+			log.FnError: err,
+			"names":     names,
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	for i, snapLV := range snapLVs {
+		if err := snapLV.Activate(req.GetMembers()[i].GetAccessType()); err != nil {
+			log.Error("failed to activate snap volume, deleting group snapshot", map[string]interface{}{
+				log.FnError: err,
+				"name":      snapLV.Name(),
+			})
+			for _, snapLV := range snapLVs {
+				if err := snapLV.Remove(); err != nil {
+					log.Error("failed to delete snapshot", map[string]interface{}{
+						log.FnError: err,
+						"name":      snapLV.Name(),
+					})
+				}
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	s.notify()
+
+	resp := &proto.CreateLVGroupSnapshotResponse{}
+	for i, snapLV := range snapLVs {
+		log.Info("created a new snapshot LV", map[string]interface{}{
+			"name":       snapLV.Name(),
+			"size":       snapLV.Size(),
+			"accessType": req.GetMembers()[i].GetAccessType(),
+			"sourceID":   req.GetMembers()[i].GetSourceVolume(),
+		})
+		resp.Snapshots = append(resp.Snapshots, &proto.LogicalVolume{
+			Name:      snapLV.Name(),
+			SizeGb:    snapLV.Size() >> 30,
+			SizeBytes: snapLV.Size(),
+			DevMajor:  snapLV.MajorNumber(),
+			DevMinor:  snapLV.MinorNumber(),
+		})
+	}
+	return resp, nil
+}
+
+// defaultBlockMetadataMaxResults is the number of ranges in a response of GetLVBlockMetadata
+// when the request does not specify it.
+const defaultBlockMetadataMaxResults = 1024
+
+func (s *lvService) GetLVBlockMetadata(req *proto.GetLVBlockMetadataRequest, server proto.LVService_GetLVBlockMetadataServer) error {
+	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	switch dc.Type {
+	case TypeThin:
+	case TypeThick:
+		return status.Error(codes.Unimplemented, "device class is not thin. Block metadata of thick volumes is not available")
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid device class type %v", string(dc.Type))
+	}
+
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return err
+	}
+	pool, err := vg.FindPool(dc.ThinPoolConfig.Name)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	target, err := s.findThinVolume(pool, req.GetTargetVolume())
+	if err != nil {
+		return err
+	}
+	if req.GetStartingOffset() >= target.Size() {
+		return status.Errorf(codes.OutOfRange, "starting offset %d exceeds the volume size %d", req.GetStartingOffset(), target.Size())
+	}
+	var base *command.LogicalVolume
+	if req.GetBaseVolume() != "" {
+		base, err = s.findThinVolume(pool, req.GetBaseVolume())
+		if err != nil {
+			return err
+		}
+		// The changes are meaningful only when the volumes have the same contents at some point.
+		sameOrigin, err := isSameOrigin(base, target)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if !sameOrigin {
+			return status.Errorf(codes.InvalidArgument, "%s and %s are not snapshots of the same origin", req.GetBaseVolume(), req.GetTargetVolume())
+		}
+	}
+
+	ranges, err := pool.BlockRanges(base, target)
+	if err != nil {
+		log.Error("failed to get block ranges", map[string]interface{}{
+			log.FnError: err,
+			"target":    req.GetTargetVolume(),
+			"base":      req.GetBaseVolume(),
+		})
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	maxResults := int(req.GetMaxResults())
+	if maxResults == 0 {
+		maxResults = defaultBlockMetadataMaxResults
+	}
+	resp := &proto.GetLVBlockMetadataResponse{VolumeSizeBytes: target.Size()}
+	for _, r := range ranges {
+		if r.Offset+r.Length <= req.GetStartingOffset() {
+			continue
+		}
+		if r.Offset < req.GetStartingOffset() {
+			r.Length -= req.GetStartingOffset() - r.Offset
+			r.Offset = req.GetStartingOffset()
+		}
+		resp.Ranges = append(resp.Ranges, &proto.BlockRange{Offset: r.Offset, Length: r.Length})
+		if len(resp.Ranges) < maxResults {
+			continue
+		}
+		if err := server.Send(resp); err != nil {
+			return err
+		}
+		resp = &proto.GetLVBlockMetadataResponse{VolumeSizeBytes: target.Size()}
+	}
+	if len(resp.Ranges) > 0 {
+		return server.Send(resp)
+	}
+	return nil
+}
+
+func (s *lvService) findThinVolume(pool *command.ThinPool, name string) (*command.LogicalVolume, error) {
+	lv, err := pool.FindVolume(name)
+	if err == command.ErrNotFound {
+		log.Error("logical volume is not found", map[string]interface{}{
+			log.FnError: err,
+			"name":      name,
+		})
+		return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", name)
+	}
+	if err != nil {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      name,
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return lv, nil
+}
+
+// isSameOrigin returns true if base and target are snapshots of the same origin, or base is the origin of target.
+func isSameOrigin(base, target *command.LogicalVolume) (bool, error) {
+	targetOrigin, err := target.Origin()
+	if err != nil || targetOrigin == nil {
+		return false, err
+	}
+	if targetOrigin.Name() == base.Name() {
+		return true, nil
+	}
+	baseOrigin, err := base.Origin()
+	if err != nil || baseOrigin == nil {
+		return false, err
+	}
+	return baseOrigin.Name() == targetOrigin.Name(), nil
+}
+
+func (s *lvService) ExportLV(req *proto.ExportLVRequest, server proto.LVService_ExportLVServer) error {
+	chunkSize := req.GetChunkSize()
+	if chunkSize == 0 {
+		chunkSize = defaultTransferChunkSize
+	}
+	if chunkSize > maxTransferChunkSize {
+		return status.Errorf(codes.InvalidArgument, "chunk size must be at most %d", maxTransferChunkSize)
+	}
+
+	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return err
+	}
+	lv, err := vg.FindVolume(req.GetName())
+	if err == command.ErrNotFound {
+		return status.Errorf(codes.NotFound, "logical volume %s is not found", req.GetName())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if req.GetSnapshotId() != "" {
+		snapLV, err := s.createExportSnapshot(req.GetDeviceClass(), lv, req.GetSnapshotId())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := snapLV.Remove(); err != nil {
+				log.Error("failed to remove snapshot for export", map[string]interface{}{
+					log.FnError: err,
+					"name":      snapLV.Name(),
+				})
+			}
+			s.notify()
+		}()
+		lv = snapLV
+	}
+
+	// Only the allocated ranges of thin volumes are read because the others are read as zeros.
+	ranges := []command.BlockRange{{Offset: 0, Length: lv.Size()}}
+	if lv.IsThin() {
+		pool, err := lv.Pool()
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		var base *command.LogicalVolume
+		if req.GetBaseVolume() != "" {
+			base, err = s.findThinVolume(pool, req.GetBaseVolume())
+			if err != nil {
+				return err
+			}
+			sameOrigin, err := isSameOrigin(lv, base)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			if !sameOrigin {
+				return status.Errorf(codes.InvalidArgument, "%s is not a snapshot of %s", req.GetBaseVolume(), req.GetName())
+			}
+		}
+		ranges, err = pool.BlockRanges(base, lv)
+		if err != nil {
+			log.Error("failed to get allocated ranges", map[string]interface{}{
+				log.FnError: err,
+				"name":      req.GetName(),
+			})
+			return status.Error(codes.Internal, err.Error())
+		}
+	} else if req.GetBaseVolume() != "" {
+		return status.Error(codes.InvalidArgument, "base volume is supported only for thin volumes")
+	}
+
+	f, err := lv.OpenDevice(os.O_RDONLY)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer f.Close()
+
+	if err := server.Send(&proto.ExportLVResponse{SizeBytes: lv.Size()}); err != nil {
+		return err
+	}
+	// The changed ranges filled with zeros must be sent because they are not zeros in the base volume.
+	err = exportChunks(f, ranges, chunkSize, req.GetBaseVolume() == "", func(chunk *proto.LVDataChunk) error {
+		return server.Send(&proto.ExportLVResponse{Chunk: chunk})
+	})
+	if err != nil {
+		log.Error("failed to export LV", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	log.Info("exported a LV", map[string]interface{}{
+		"name": req.GetName(),
+		"size": lv.Size(),
+	})
+	return nil
+}
+
+func (s *lvService) ImportLV(server proto.LVService_ImportLVServer) error {
+	req, err := server.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "no volume is specified")
+	}
+	if err != nil {
+		return err
+	}
+	if req.GetVolume() == nil || req.GetChunk() != nil {
+		return status.Error(codes.InvalidArgument, "the first request must have only volume")
+	}
+	if req.GetUpdate() {
+		return s.updateLV(req.GetVolume(), server)
+	}
+
+	// The volume is created under a temporary name without tags, and is renamed and tagged
+	// only after all the contents are written. Thus, an interrupted import is never taken
+	// for a complete volume, and the leftover is removed by the next attempt.
+	volume := req.GetVolume()
+	name := s.lvName(volume.GetDeviceClass(), volume.GetName(), volume.GetTags())
+	tmpName := name + importingSuffix
+	if err := s.removeStaleLV(volume.GetDeviceClass(), tmpName); err != nil {
+		return err
+	}
+	lv, err := s.createLV(&proto.CreateLVRequest{
+		Name:                tmpName,
+		SizeGb:              volume.GetSizeGb(),
+		SizeBytes:           volume.GetSizeBytes(),
+		DeviceClass:         volume.GetDeviceClass(),
+		LvcreateOptionClass: volume.GetLvcreateOptionClass(),
+	})
+	if err != nil {
+		return err
+	}
+	s.notify()
+
+	err = importLV(lv, server, false)
+	if err == nil {
+		err = lv.Rename(name)
+	}
+	if err == nil {
+		err = lv.AddTags(volume.GetTags())
+	}
+	if err != nil {
+		log.Error("failed to import LV, deleting it", map[string]interface{}{
+			log.FnError: err,
+			"name":      lv.Name(),
+		})
+		if err := lv.Remove(); err != nil {
+			log.Error("failed to delete LV", map[string]interface{}{
+				log.FnError: err,
+				"name":      lv.Name(),
+			})
+		}
+		s.notify()
+
+		if errors.Is(err, errInvalidChunk) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	log.Info("imported a new LV", map[string]interface{}{
+		"name": lv.Name(),
+		"size": lv.Size(),
+	})
+	return server.SendAndClose(&proto.ImportLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:      lv.Name(),
+			SizeGb:    lv.Size() >> 30,
+			SizeBytes: lv.Size(),
+			DevMajor:  lv.MajorNumber(),
+			DevMinor:  lv.MinorNumber(),
+		},
+	})
+}
+
+// createExportSnapshot takes a thin snapshot of lv to export its contents at this point in time.
+func (s *lvService) createExportSnapshot(deviceClass string, lv *command.LogicalVolume, id string) (*command.LogicalVolume, error) {
+	if !lv.IsThin() {
+		return nil, status.Error(codes.InvalidArgument, "snapshot is supported only for thin volumes")
+	}
+	if invalidLVNameChars.MatchString(id) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot ID: %s", id)
+	}
+	name := id + exportingSuffix
+	if err := s.removeStaleLV(deviceClass, name); err != nil {
+		return nil, err
+	}
+
+	snapLV, err := lv.Snapshot(name, 0, nil, true)
+	if err != nil {
+		log.Error("failed to create snapshot for export", map[string]interface{}{
+			log.FnError: err,
+			"name":      name,
+		})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.notify()
+
+	log.Info("created a snapshot for export", map[string]interface{}{
+		"name":     name,
+		"sourceID": lv.Name(),
+	})
+	return snapLV, nil
+}
+
+// removeStaleLV removes the volume left by an interrupted operation if it exists.
+func (s *lvService) removeStaleLV(deviceClass, name string) error {
+	dc, err := s.dcmapper.DeviceClass(deviceClass)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: %s", err.Error(), deviceClass)
+	}
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return err
+	}
+	lv, err := vg.FindVolume(name)
+	if err == command.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	log.Info("removing a stale LV", map[string]interface{}{
+		"name": name,
+	})
+	if err := lv.Remove(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	s.notify()
+	return nil
+}
+
+// updateLV writes the chunks received by server into the existing volume.
+func (s *lvService) updateLV(volume *proto.CreateLVRequest, server proto.LVService_ImportLVServer) error {
+	dc, err := s.dcmapper.DeviceClass(volume.GetDeviceClass())
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: %s", err.Error(), volume.GetDeviceClass())
+	}
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return err
+	}
+	lv, err := vg.FindVolume(volume.GetName())
+	if err == command.ErrNotFound {
+		return status.Errorf(codes.NotFound, "logical volume %s is not found", volume.GetName())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if err := importLV(lv, server, true); err != nil {
+		log.Error("failed to update LV", map[string]interface{}{
+			log.FnError: err,
+			"name":      lv.Name(),
+		})
+		if errors.Is(err, errInvalidChunk) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	log.Info("updated a LV", map[string]interface{}{
+		"name": lv.Name(),
+	})
+	return server.SendAndClose(&proto.ImportLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:      lv.Name(),
+			SizeGb:    lv.Size() >> 30,
+			SizeBytes: lv.Size(),
+			DevMajor:  lv.MajorNumber(),
+			DevMinor:  lv.MinorNumber(),
+		},
+	})
+}
+
+// importLV writes the chunks received by server into lv.
+// If update is true, the ranges not covered by any chunk are left unchanged.
+func importLV(lv *command.LogicalVolume, server proto.LVService_ImportLVServer, update bool) error {
+	f, err := lv.OpenDevice(os.O_WRONLY)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var dst importTarget = lvWriter{File: f, lv: lv}
+	if update {
+		dst = updateTarget{dst}
+	}
+	err = importChunks(dst, func() (*proto.LVDataChunk, error) {
+		req, err := server.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if req.GetChunk() == nil {
+			return nil, fmt.Errorf("%w: request without chunk", errInvalidChunk)
+		}
+		return req.GetChunk(), nil
+	})
+	if err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*proto.ResizeLVResponse, error) {
 	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
@@ -324,7 +1161,7 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	requested := req.GetSizeGb() << 30
+	requested := alignSize(vg, requestedSize(req.GetSizeBytes(), req.GetSizeGb()))
 	current := lv.Size()
 
 	if requested < current {
@@ -399,5 +1236,14 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 		"size": requested,
 	})
 
-	return &proto.Empty{}, nil
+	return &proto.ResizeLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:      lv.Name(),
+			SizeGb:    requested >> 30,
+			SizeBytes: requested,
+			DevMajor:  lv.MajorNumber(),
+			DevMinor:  lv.MinorNumber(),
+			Tags:      lv.Tags(),
+		},
+	}, nil
 }
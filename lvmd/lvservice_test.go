@@ -1,6 +1,7 @@
 package lvmd
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"os/exec"
@@ -9,6 +10,7 @@ import (
 	"github.com/topolvm/topolvm/lvmd/command"
 	"github.com/topolvm/topolvm/lvmd/proto"
 	"github.com/topolvm/topolvm/lvmd/testutils"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -92,6 +94,9 @@ func TestLVService(t *testing.T) {
 	if res.GetVolume().GetSizeGb() != 1 {
 		t.Errorf(`res.Volume.SizeGb != 1: %d`, res.GetVolume().GetSizeGb())
 	}
+	if res.GetVolume().GetSizeBytes() != 1<<30 {
+		t.Errorf(`res.Volume.SizeBytes != 1<<30: %d`, res.GetVolume().GetSizeBytes())
+	}
 	err = exec.Command("lvs", vg.Name()+"/test1").Run()
 	if err != nil {
 		t.Error("failed to create logical volume")
@@ -125,7 +130,7 @@ func TestLVService(t *testing.T) {
 		t.Errorf("unexpected count: %d", count)
 	}
 
-	_, err = lvService.ResizeLV(context.Background(), &proto.ResizeLVRequest{
+	resizeRes, err := lvService.ResizeLV(context.Background(), &proto.ResizeLVRequest{
 		Name:        "test1",
 		DeviceClass: thickdev,
 		SizeGb:      2,
@@ -133,6 +138,9 @@ func TestLVService(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	if resizeRes.GetVolume().GetSizeBytes() != 2<<30 {
+		t.Errorf(`response does not match size 2GiB: %d`, resizeRes.GetVolume().GetSizeBytes())
+	}
 	if count != 2 {
 		t.Errorf("unexpected count: %d", count)
 	}
@@ -181,6 +189,114 @@ func TestLVService(t *testing.T) {
 		t.Error("unexpected error: ", err)
 	}
 
+	// adoption of an existing logical volume
+	count = 0
+	_, err = vg.CreateVolume("existing", 1<<30, []string{"testtag1"}, 0, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = lvService.AdoptLV(context.Background(), &proto.AdoptLVRequest{
+		Name:        "existing",
+		NewName:     "adopted",
+		DeviceClass: thindev,
+	})
+	code = status.Code(err)
+	if code != codes.FailedPrecondition {
+		t.Errorf(`code is not codes.FailedPrecondition: %s`, code)
+	}
+	adoptRes, err := lvService.AdoptLV(context.Background(), &proto.AdoptLVRequest{
+		Name:        "existing",
+		NewName:     "adopted",
+		Tags:        []string{"testtag1", "testtag2"},
+		DeviceClass: thickdev,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("is not notified: %d", count)
+	}
+	if adoptRes.GetVolume().GetName() != "adopted" {
+		t.Errorf(`res.Volume.Name != "adopted": %s`, adoptRes.GetVolume().GetName())
+	}
+	if adoptRes.GetVolume().GetSizeGb() != 1 {
+		t.Errorf(`res.Volume.SizeGb != 1: %d`, adoptRes.GetVolume().GetSizeGb())
+	}
+
+	// adoption is idempotent
+	_, err = lvService.AdoptLV(context.Background(), &proto.AdoptLVRequest{
+		Name:        "existing",
+		NewName:     "adopted",
+		Tags:        []string{"testtag1", "testtag2"},
+		DeviceClass: thickdev,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vg.Update(); err != nil {
+		t.Fatal(err)
+	}
+	lv, err = vg.FindVolume("adopted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lv.Tags()) != 2 || lv.Tags()[0] != "testtag1" || lv.Tags()[1] != "testtag2" {
+		t.Errorf(`unexpected tags: %v`, lv.Tags())
+	}
+
+	// modification of attributes
+	_, err = lvService.ModifyLV(context.Background(), &proto.ModifyLVRequest{
+		Name:        "adopted",
+		DeviceClass: thickdev,
+		ReadAhead:   "many",
+	})
+	code = status.Code(err)
+	if code != codes.InvalidArgument {
+		t.Errorf(`code is not codes.InvalidArgument: %s`, code)
+	}
+	_, err = lvService.ModifyLV(context.Background(), &proto.ModifyLVRequest{
+		Name:        "adopted",
+		DeviceClass: thickdev,
+		ReadAhead:   "256",
+		AddTags:     []string{"testtag3"},
+		DelTags:     []string{"testtag1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vg.Update(); err != nil {
+		t.Fatal(err)
+	}
+	lv, err = vg.FindVolume("adopted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lv.Tags()) != 2 || lv.Tags()[0] != "testtag2" || lv.Tags()[1] != "testtag3" {
+		t.Errorf(`unexpected tags: %v`, lv.Tags())
+	}
+	_, err = lvService.ModifyLV(context.Background(), &proto.ModifyLVRequest{
+		Name:        "notfound",
+		DeviceClass: thickdev,
+	})
+	code = status.Code(err)
+	if code != codes.NotFound {
+		t.Errorf(`code is not codes.NotFound: %s`, code)
+	}
+
+	if err := lv.Remove(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = lvService.AdoptLV(context.Background(), &proto.AdoptLVRequest{
+		Name:        "notfound",
+		DeviceClass: thickdev,
+	})
+	code = status.Code(err)
+	if code != codes.NotFound {
+		t.Errorf(`code is not codes.NotFound: %s`, code)
+	}
+
 	// thin logical volume validations
 	count = 0
 	res, err = lvService.CreateLV(context.Background(), &proto.CreateLVRequest{
@@ -411,3 +527,158 @@ func TestLVService(t *testing.T) {
 		t.Errorf(`testsnaptag1 not present on snapshot`)
 	}
 }
+
+type fakeExportServer struct {
+	grpc.ServerStream
+	ctx context.Context
+	// onSize is called when the size of the exported volume is sent.
+	onSize    func()
+	responses []*proto.ExportLVResponse
+}
+
+func (s *fakeExportServer) Send(r *proto.ExportLVResponse) error {
+	if r.GetChunk() == nil && s.onSize != nil {
+		s.onSize()
+	}
+	s.responses = append(s.responses, r)
+	return nil
+}
+
+func (s *fakeExportServer) Context() context.Context {
+	return s.ctx
+}
+
+func TestExportLVSnapshot(t *testing.T) {
+	uid := os.Getuid()
+	if uid != 0 {
+		t.Skip("run as root")
+	}
+
+	vgName := "test_export"
+	loop, err := testutils.MakeLoopbackDevice(vgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testutils.MakeLoopbackVG(vgName, loop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testutils.CleanLoopbackVG(vgName, []string{loop}, []string{vgName})
+
+	vg, err := command.FindVolumeGroup(vgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poolName := "test_pool"
+	_, err = vg.CreatePool(poolName, 1<<30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvService := NewLVService(
+		NewDeviceClassManager(
+			[]*DeviceClass{
+				{
+					Name:        vgName,
+					VolumeGroup: vg.Name(),
+				},
+				{
+					Name:        poolName,
+					VolumeGroup: vg.Name(),
+					Type:        TypeThin,
+					ThinPoolConfig: &ThinPoolConfig{
+						Name:               poolName,
+						OverprovisionRatio: 10,
+					},
+				},
+			},
+		), NewLvcreateOptionClassManager([]*LvcreateOptionClass{}), func() {})
+
+	writeVolume := func(name string, b byte) {
+		t.Helper()
+		if err := vg.Update(); err != nil {
+			t.Fatal(err)
+		}
+		lv, err := vg.FindVolume(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := lv.OpenDevice(os.O_WRONLY)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteAt(bytes.Repeat([]byte{b}, 4096), 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = lvService.CreateLV(context.Background(), &proto.CreateLVRequest{
+		Name:        "thick",
+		DeviceClass: vgName,
+		SizeBytes:   4 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = lvService.ExportLV(&proto.ExportLVRequest{
+		Name:        "thick",
+		DeviceClass: vgName,
+		SnapshotId:  "id1",
+	}, &fakeExportServer{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("snapshot of thick volume should be rejected: %v", err)
+	}
+
+	_, err = lvService.CreateLV(context.Background(), &proto.CreateLVRequest{
+		Name:        "origin",
+		DeviceClass: poolName,
+		SizeBytes:   4 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeVolume("origin", 0xaa)
+
+	// the origin is overwritten while it is exported, and the snapshot keeps the contents before that.
+	server := &fakeExportServer{
+		ctx: context.Background(),
+		onSize: func() {
+			writeVolume("origin", 0x55)
+			if _, err := vg.FindVolume("id1" + exportingSuffix); err != nil {
+				t.Errorf("snapshot is not found during the export: %v", err)
+			}
+		},
+	}
+	err = lvService.ExportLV(&proto.ExportLVRequest{
+		Name:        "origin",
+		DeviceClass: poolName,
+		SnapshotId:  "id1",
+	}, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.responses) != 2 {
+		t.Fatalf("unexpected number of responses: %d", len(server.responses))
+	}
+	if server.responses[0].GetSizeBytes() != 4<<20 {
+		t.Errorf("unexpected size: %d", server.responses[0].GetSizeBytes())
+	}
+	data, err := decodeChunk(server.responses[1].GetChunk())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data[:4096], bytes.Repeat([]byte{0xaa}, 4096)) {
+		t.Error("the data written during the export is exported")
+	}
+
+	if err := vg.Update(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.FindVolume("id1" + exportingSuffix)
+	if err != command.ErrNotFound {
+		t.Error("snapshot is not removed after the export: ", err)
+	}
+}
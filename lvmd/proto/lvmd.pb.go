@@ -4,6 +4,13 @@
 // The protocol consists of two services:
 // - VGService provides information of the volume group.
 // - LVService provides management functions for logical volumes on the volume group.
+//
+// Volume sizes are given in bytes by "size_bytes" fields, and LVMd rounds them up to
+// a multiple of the extent size of the volume group.  The "size_gb" fields are kept for
+// the clients that do not know "size_bytes"; LVMd uses them only if "size_bytes" is zero,
+// and fills them in its responses in addition to "size_bytes".  Clients should set "size_gb"
+// to the size rounded up to GiB as well, so that LVMd that does not know "size_bytes"
+// creates volumes large enough.
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
@@ -71,11 +78,12 @@ type LogicalVolume struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                          // The logical volume name.
-	SizeGb   uint64   `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"`       // Volume size in GiB.
-	DevMajor uint32   `protobuf:"varint,3,opt,name=dev_major,json=devMajor,proto3" json:"dev_major,omitempty"` // Device major number.
-	DevMinor uint32   `protobuf:"varint,4,opt,name=dev_minor,json=devMinor,proto3" json:"dev_minor,omitempty"` // Device minor number.
-	Tags     []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                          // Tags to add to the volume during creation
+	Name      string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                             // The logical volume name.
+	SizeGb    uint64   `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"`          // Volume size in GiB, truncated. Use size_bytes instead.
+	DevMajor  uint32   `protobuf:"varint,3,opt,name=dev_major,json=devMajor,proto3" json:"dev_major,omitempty"`    // Device major number.
+	DevMinor  uint32   `protobuf:"varint,4,opt,name=dev_minor,json=devMinor,proto3" json:"dev_minor,omitempty"`    // Device minor number.
+	Tags      []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                             // Tags to add to the volume during creation
+	SizeBytes uint64   `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Volume size in bytes.
 }
 
 func (x *LogicalVolume) Reset() {
@@ -145,17 +153,25 @@ func (x *LogicalVolume) GetTags() []string {
 	return nil
 }
 
+func (x *LogicalVolume) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
 // Represents the input for CreateLV.
 type CreateLVRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name                string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                    // The logical volume name.
-	SizeGb              uint64   `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"` // Volume size in GiB.
+	Name                string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                    // The logical volume name. The actual name is generated by lv-name-template of the device class if set.
+	SizeGb              uint64   `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"` // Volume size in GiB. Used only if size_bytes is zero.
 	Tags                []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`                    // Tags to add to the volume during creation
 	DeviceClass         string   `protobuf:"bytes,4,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
 	LvcreateOptionClass string   `protobuf:"bytes,5,opt,name=lvcreate_option_class,json=lvcreateOptionClass,proto3" json:"lvcreate_option_class,omitempty"`
+	SizeBytes           uint64   `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Volume size in bytes.
 }
 
 func (x *CreateLVRequest) Reset() {
@@ -225,6 +241,13 @@ func (x *CreateLVRequest) GetLvcreateOptionClass() string {
 	return ""
 }
 
+func (x *CreateLVRequest) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
 // Represents the response of CreateLV.
 type CreateLVResponse struct {
 	state         protoimpl.MessageState
@@ -281,6 +304,7 @@ type RemoveLVRequest struct {
 
 	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // The logical volume name.
 	DeviceClass string `protobuf:"bytes,2,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	AsyncWipe   bool   `protobuf:"varint,3,opt,name=async_wipe,json=asyncWipe,proto3" json:"async_wipe,omitempty"` // If true, wipe the volume in the background and return the progress without waiting for it.
 }
 
 func (x *RemoveLVRequest) Reset() {
@@ -329,23 +353,95 @@ func (x *RemoveLVRequest) GetDeviceClass() string {
 	return ""
 }
 
+func (x *RemoveLVRequest) GetAsyncWipe() bool {
+	if x != nil {
+		return x.AsyncWipe
+	}
+	return false
+}
+
+// Represents the response of RemoveLV.
+type RemoveLVResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wiping     bool   `protobuf:"varint,1,opt,name=wiping,proto3" json:"wiping,omitempty"`                           // True if the volume is being wiped. The volume is removed once the wipe finishes.
+	WipedBytes uint64 `protobuf:"varint,2,opt,name=wiped_bytes,json=wipedBytes,proto3" json:"wiped_bytes,omitempty"` // The number of bytes wiped so far.
+	TotalBytes uint64 `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"` // The number of bytes to be wiped.
+}
+
+func (x *RemoveLVResponse) Reset() {
+	*x = RemoveLVResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveLVResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveLVResponse) ProtoMessage() {}
+
+func (x *RemoveLVResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveLVResponse.ProtoReflect.Descriptor instead.
+func (*RemoveLVResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RemoveLVResponse) GetWiping() bool {
+	if x != nil {
+		return x.Wiping
+	}
+	return false
+}
+
+func (x *RemoveLVResponse) GetWipedBytes() uint64 {
+	if x != nil {
+		return x.WipedBytes
+	}
+	return 0
+}
+
+func (x *RemoveLVResponse) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
 type CreateLVSnapshotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name         string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // The logical volume name.
+	Name         string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // The logical volume name. The actual name is generated by lv-name-template of the device class if set.
 	Tags         []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"` // Tags to add to the volume during creation
 	DeviceClass  string   `protobuf:"bytes,3,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
 	SourceVolume string   `protobuf:"bytes,4,opt,name=source_volume,json=sourceVolume,proto3" json:"source_volume,omitempty"` // Source lv of snapshot.
-	SizeGb       uint64   `protobuf:"varint,5,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"`                  // Volume size in GiB.
+	SizeGb       uint64   `protobuf:"varint,5,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"`                  // Volume size in GiB. Used only if size_bytes is zero.
 	AccessType   string   `protobuf:"bytes,6,opt,name=access_type,json=accessType,proto3" json:"access_type,omitempty"`       // Access type of snapshot
+	SizeBytes    uint64   `protobuf:"varint,7,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`         // Volume size in bytes. The size of the source volume is used if both size_bytes and size_gb are zero.
 }
 
 func (x *CreateLVSnapshotRequest) Reset() {
 	*x = CreateLVSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -358,7 +454,7 @@ func (x *CreateLVSnapshotRequest) String() string {
 func (*CreateLVSnapshotRequest) ProtoMessage() {}
 
 func (x *CreateLVSnapshotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +467,7 @@ func (x *CreateLVSnapshotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateLVSnapshotRequest.ProtoReflect.Descriptor instead.
 func (*CreateLVSnapshotRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{5}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateLVSnapshotRequest) GetName() string {
@@ -416,6 +512,13 @@ func (x *CreateLVSnapshotRequest) GetAccessType() string {
 	return ""
 }
 
+func (x *CreateLVSnapshotRequest) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
 type CreateLVSnapshotResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -427,7 +530,7 @@ type CreateLVSnapshotResponse struct {
 func (x *CreateLVSnapshotResponse) Reset() {
 	*x = CreateLVSnapshotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -440,7 +543,7 @@ func (x *CreateLVSnapshotResponse) String() string {
 func (*CreateLVSnapshotResponse) ProtoMessage() {}
 
 func (x *CreateLVSnapshotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -453,7 +556,7 @@ func (x *CreateLVSnapshotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateLVSnapshotResponse.ProtoReflect.Descriptor instead.
 func (*CreateLVSnapshotResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{6}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateLVSnapshotResponse) GetSnapshot() *LogicalVolume {
@@ -463,37 +566,35 @@ func (x *CreateLVSnapshotResponse) GetSnapshot() *LogicalVolume {
 	return nil
 }
 
-// Represents the input for ResizeLV.
+// Represents the input for CreateLVGroupSnapshot.
 //
-// The volume must already exist.
-// The volume size will be set to exactly "size_gb".
-type ResizeLVRequest struct {
+// The source volumes must be thin volumes in the same thin pool of the device class.
+type CreateLVGroupSnapshotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                    // The logical volume name.
-	SizeGb      uint64 `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"` // Volume size in GiB.
-	DeviceClass string `protobuf:"bytes,3,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	DeviceClass string                   `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	Members     []*LVGroupSnapshotMember `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
 }
 
-func (x *ResizeLVRequest) Reset() {
-	*x = ResizeLVRequest{}
+func (x *CreateLVGroupSnapshotRequest) Reset() {
+	*x = CreateLVGroupSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResizeLVRequest) String() string {
+func (x *CreateLVGroupSnapshotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResizeLVRequest) ProtoMessage() {}
+func (*CreateLVGroupSnapshotRequest) ProtoMessage() {}
 
-func (x *ResizeLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
+func (x *CreateLVGroupSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -504,58 +605,54 @@ func (x *ResizeLVRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResizeLVRequest.ProtoReflect.Descriptor instead.
-func (*ResizeLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CreateLVGroupSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*CreateLVGroupSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ResizeLVRequest) GetName() string {
+func (x *CreateLVGroupSnapshotRequest) GetDeviceClass() string {
 	if x != nil {
-		return x.Name
+		return x.DeviceClass
 	}
 	return ""
 }
 
-func (x *ResizeLVRequest) GetSizeGb() uint64 {
-	if x != nil {
-		return x.SizeGb
-	}
-	return 0
-}
-
-func (x *ResizeLVRequest) GetDeviceClass() string {
+func (x *CreateLVGroupSnapshotRequest) GetMembers() []*LVGroupSnapshotMember {
 	if x != nil {
-		return x.DeviceClass
+		return x.Members
 	}
-	return ""
+	return nil
 }
 
-// Represents the response of GetLVList.
-type GetLVListResponse struct {
+// Represents a snapshot to be created by CreateLVGroupSnapshot.
+type LVGroupSnapshotMember struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Volumes []*LogicalVolume `protobuf:"bytes,1,rep,name=volumes,proto3" json:"volumes,omitempty"` // Information of volumes.
+	Name         string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                     // The logical volume name of the snapshot. The actual name is generated by lv-name-template of the device class if set.
+	SourceVolume string   `protobuf:"bytes,2,opt,name=source_volume,json=sourceVolume,proto3" json:"source_volume,omitempty"` // Source lv of the snapshot.
+	Tags         []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`                                     // Tags to add to the snapshot during creation
+	AccessType   string   `protobuf:"bytes,4,opt,name=access_type,json=accessType,proto3" json:"access_type,omitempty"`       // Access type of the snapshot
 }
 
-func (x *GetLVListResponse) Reset() {
-	*x = GetLVListResponse{}
+func (x *LVGroupSnapshotMember) Reset() {
+	*x = LVGroupSnapshotMember{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetLVListResponse) String() string {
+func (x *LVGroupSnapshotMember) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLVListResponse) ProtoMessage() {}
+func (*LVGroupSnapshotMember) ProtoMessage() {}
 
-func (x *GetLVListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
+func (x *LVGroupSnapshotMember) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -566,76 +663,50 @@ func (x *GetLVListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLVListResponse.ProtoReflect.Descriptor instead.
-func (*GetLVListResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use LVGroupSnapshotMember.ProtoReflect.Descriptor instead.
+func (*LVGroupSnapshotMember) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *GetLVListResponse) GetVolumes() []*LogicalVolume {
+func (x *LVGroupSnapshotMember) GetName() string {
 	if x != nil {
-		return x.Volumes
+		return x.Name
 	}
-	return nil
-}
-
-// Represents the response of GetFreeBytes.
-type GetFreeBytesResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	FreeBytes uint64 `protobuf:"varint,1,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"` // Free space of the volume group in bytes.
+	return ""
 }
 
-func (x *GetFreeBytesResponse) Reset() {
-	*x = GetFreeBytesResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *LVGroupSnapshotMember) GetSourceVolume() string {
+	if x != nil {
+		return x.SourceVolume
 	}
+	return ""
 }
 
-func (x *GetFreeBytesResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*GetFreeBytesResponse) ProtoMessage() {}
-
-func (x *GetFreeBytesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *LVGroupSnapshotMember) GetTags() []string {
+	if x != nil {
+		return x.Tags
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use GetFreeBytesResponse.ProtoReflect.Descriptor instead.
-func (*GetFreeBytesResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{9}
+	return nil
 }
 
-func (x *GetFreeBytesResponse) GetFreeBytes() uint64 {
+func (x *LVGroupSnapshotMember) GetAccessType() string {
 	if x != nil {
-		return x.FreeBytes
+		return x.AccessType
 	}
-	return 0
+	return ""
 }
 
-type GetLVListRequest struct {
+// Represents the response of CreateLVGroupSnapshot.
+type CreateLVGroupSnapshotResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DeviceClass string `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	Snapshots []*LogicalVolume `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"` // Information of the created snapshot lvs in the order of the members.
 }
 
-func (x *GetLVListRequest) Reset() {
-	*x = GetLVListRequest{}
+func (x *CreateLVGroupSnapshotResponse) Reset() {
+	*x = CreateLVGroupSnapshotResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lvmd_proto_lvmd_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -643,13 +714,13 @@ func (x *GetLVListRequest) Reset() {
 	}
 }
 
-func (x *GetLVListRequest) String() string {
+func (x *CreateLVGroupSnapshotResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLVListRequest) ProtoMessage() {}
+func (*CreateLVGroupSnapshotResponse) ProtoMessage() {}
 
-func (x *GetLVListRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateLVGroupSnapshotResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_lvmd_proto_lvmd_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -661,28 +732,37 @@ func (x *GetLVListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLVListRequest.ProtoReflect.Descriptor instead.
-func (*GetLVListRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateLVGroupSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*CreateLVGroupSnapshotResponse) Descriptor() ([]byte, []int) {
 	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetLVListRequest) GetDeviceClass() string {
+func (x *CreateLVGroupSnapshotResponse) GetSnapshots() []*LogicalVolume {
 	if x != nil {
-		return x.DeviceClass
+		return x.Snapshots
 	}
-	return ""
+	return nil
 }
 
-type GetFreeBytesRequest struct {
+// Represents the input for GetLVBlockMetadata.
+//
+// The volumes must be thin volumes in the thin pool of the device class.
+// If base_volume is specified, it must be a thin snapshot of the same origin as target_volume,
+// or the origin itself.
+type GetLVBlockMetadataRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DeviceClass string `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	DeviceClass    string `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	TargetVolume   string `protobuf:"bytes,2,opt,name=target_volume,json=targetVolume,proto3" json:"target_volume,omitempty"`        // The logical volume whose blocks are returned.
+	BaseVolume     string `protobuf:"bytes,3,opt,name=base_volume,json=baseVolume,proto3" json:"base_volume,omitempty"`              // The logical volume to be compared with. The allocated blocks of target_volume are returned if empty.
+	StartingOffset uint64 `protobuf:"varint,4,opt,name=starting_offset,json=startingOffset,proto3" json:"starting_offset,omitempty"` // The byte offset from which the blocks are returned.
+	MaxResults     uint32 `protobuf:"varint,5,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`             // The maximum number of ranges in a response. A default value is used if zero.
 }
 
-func (x *GetFreeBytesRequest) Reset() {
-	*x = GetFreeBytesRequest{}
+func (x *GetLVBlockMetadataRequest) Reset() {
+	*x = GetLVBlockMetadataRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lvmd_proto_lvmd_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -690,13 +770,13 @@ func (x *GetFreeBytesRequest) Reset() {
 	}
 }
 
-func (x *GetFreeBytesRequest) String() string {
+func (x *GetLVBlockMetadataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFreeBytesRequest) ProtoMessage() {}
+func (*GetLVBlockMetadataRequest) ProtoMessage() {}
 
-func (x *GetFreeBytesRequest) ProtoReflect() protoreflect.Message {
+func (x *GetLVBlockMetadataRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_lvmd_proto_lvmd_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -708,30 +788,58 @@ func (x *GetFreeBytesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFreeBytesRequest.ProtoReflect.Descriptor instead.
-func (*GetFreeBytesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetLVBlockMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetLVBlockMetadataRequest) Descriptor() ([]byte, []int) {
 	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetFreeBytesRequest) GetDeviceClass() string {
+func (x *GetLVBlockMetadataRequest) GetDeviceClass() string {
 	if x != nil {
 		return x.DeviceClass
 	}
 	return ""
 }
 
-// Represents the stream output from Watch.
-type WatchResponse struct {
+func (x *GetLVBlockMetadataRequest) GetTargetVolume() string {
+	if x != nil {
+		return x.TargetVolume
+	}
+	return ""
+}
+
+func (x *GetLVBlockMetadataRequest) GetBaseVolume() string {
+	if x != nil {
+		return x.BaseVolume
+	}
+	return ""
+}
+
+func (x *GetLVBlockMetadataRequest) GetStartingOffset() uint64 {
+	if x != nil {
+		return x.StartingOffset
+	}
+	return 0
+}
+
+func (x *GetLVBlockMetadataRequest) GetMaxResults() uint32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+// Represents a range of blocks in a logical volume.
+type BlockRange struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	FreeBytes uint64       `protobuf:"varint,1,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"` // Free space of the default volume group in bytes. In the case of thin pools, free space on the thinpool with overprovision in bytes.
-	Items     []*WatchItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Offset uint64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"` // The byte offset of the range.
+	Length uint64 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"` // The length of the range in bytes.
 }
 
-func (x *WatchResponse) Reset() {
-	*x = WatchResponse{}
+func (x *BlockRange) Reset() {
+	*x = BlockRange{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lvmd_proto_lvmd_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -739,13 +847,13 @@ func (x *WatchResponse) Reset() {
 	}
 }
 
-func (x *WatchResponse) String() string {
+func (x *BlockRange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchResponse) ProtoMessage() {}
+func (*BlockRange) ProtoMessage() {}
 
-func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+func (x *BlockRange) ProtoReflect() protoreflect.Message {
 	mi := &file_lvmd_proto_lvmd_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -757,39 +865,37 @@ func (x *WatchResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
-func (*WatchResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BlockRange.ProtoReflect.Descriptor instead.
+func (*BlockRange) Descriptor() ([]byte, []int) {
 	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *WatchResponse) GetFreeBytes() uint64 {
+func (x *BlockRange) GetOffset() uint64 {
 	if x != nil {
-		return x.FreeBytes
+		return x.Offset
 	}
 	return 0
 }
 
-func (x *WatchResponse) GetItems() []*WatchItem {
+func (x *BlockRange) GetLength() uint64 {
 	if x != nil {
-		return x.Items
+		return x.Length
 	}
-	return nil
+	return 0
 }
 
-// Represents the details of thinpool.
-type ThinPoolItem struct {
+// Represents the stream output from GetLVBlockMetadata.
+type GetLVBlockMetadataResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DataPercent        float64 `protobuf:"fixed64,1,opt,name=data_percent,json=dataPercent,proto3" json:"data_percent,omitempty"`                     // Data percent occupied on the thinpool, used for monitoring.
-	MetadataPercent    float64 `protobuf:"fixed64,2,opt,name=metadata_percent,json=metadataPercent,proto3" json:"metadata_percent,omitempty"`         // Metadata percent occupied on the thinpool, used for monitoring.
-	OverprovisionBytes uint64  `protobuf:"varint,3,opt,name=overprovision_bytes,json=overprovisionBytes,proto3" json:"overprovision_bytes,omitempty"` // Free space on the thinpool with overprovision, used for annotating node.
-	SizeBytes          uint64  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`                            // Physical data space size of the thinpool.
+	VolumeSizeBytes uint64        `protobuf:"varint,1,opt,name=volume_size_bytes,json=volumeSizeBytes,proto3" json:"volume_size_bytes,omitempty"` // The size of target_volume in bytes.
+	Ranges          []*BlockRange `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`                                             // The ranges in ascending order of the offsets.
 }
 
-func (x *ThinPoolItem) Reset() {
-	*x = ThinPoolItem{}
+func (x *GetLVBlockMetadataResponse) Reset() {
+	*x = GetLVBlockMetadataResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lvmd_proto_lvmd_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -797,13 +903,13 @@ func (x *ThinPoolItem) Reset() {
 	}
 }
 
-func (x *ThinPoolItem) String() string {
+func (x *GetLVBlockMetadataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ThinPoolItem) ProtoMessage() {}
+func (*GetLVBlockMetadataResponse) ProtoMessage() {}
 
-func (x *ThinPoolItem) ProtoReflect() protoreflect.Message {
+func (x *GetLVBlockMetadataResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_lvmd_proto_lvmd_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -815,21 +921,999 @@ func (x *ThinPoolItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ThinPoolItem.ProtoReflect.Descriptor instead.
-func (*ThinPoolItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetLVBlockMetadataResponse.ProtoReflect.Descriptor instead.
+func (*GetLVBlockMetadataResponse) Descriptor() ([]byte, []int) {
 	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ThinPoolItem) GetDataPercent() float64 {
+func (x *GetLVBlockMetadataResponse) GetVolumeSizeBytes() uint64 {
 	if x != nil {
-		return x.DataPercent
+		return x.VolumeSizeBytes
 	}
 	return 0
 }
 
-func (x *ThinPoolItem) GetMetadataPercent() float64 {
+func (x *GetLVBlockMetadataResponse) GetRanges() []*BlockRange {
 	if x != nil {
-		return x.MetadataPercent
+		return x.Ranges
+	}
+	return nil
+}
+
+// Represents a chunk of the contents of a logical volume.
+type LVDataChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Offset   uint64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`     // The byte offset of the chunk in the volume.
+	Length   uint64 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`     // The length of the uncompressed data in bytes.
+	Data     []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`          // The data compressed with gzip.
+	Checksum uint32 `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"` // The CRC-32C checksum of the uncompressed data.
+}
+
+func (x *LVDataChunk) Reset() {
+	*x = LVDataChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LVDataChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LVDataChunk) ProtoMessage() {}
+
+func (x *LVDataChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LVDataChunk.ProtoReflect.Descriptor instead.
+func (*LVDataChunk) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LVDataChunk) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *LVDataChunk) GetLength() uint64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *LVDataChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *LVDataChunk) GetChecksum() uint32 {
+	if x != nil {
+		return x.Checksum
+	}
+	return 0
+}
+
+// Represents the input for ExportLV.
+//
+// If snapshot_id is specified, a thin snapshot of the volume named "<snapshot_id>.exporting" is taken and exported
+// instead of the volume, so that the exported contents are consistent even if the volume is written meanwhile.
+// The snapshot is removed when the export finishes. If a snapshot is left by an interrupted export
+// with the same ID, it is removed first. This is supported only for thin volumes.
+type ExportLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // The logical volume name.
+	DeviceClass string `protobuf:"bytes,2,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	ChunkSize   uint64 `protobuf:"varint,3,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`   // The maximum length of the uncompressed data in a chunk, up to 2 MiB. A default value is used if zero.
+	BaseVolume  string `protobuf:"bytes,4,opt,name=base_volume,json=baseVolume,proto3" json:"base_volume,omitempty"` // The name of a thin snapshot of the volume. If specified, only the ranges changed since the snapshot are exported.
+	SnapshotId  string `protobuf:"bytes,5,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"` // The ID of the temporary snapshot to export.
+}
+
+func (x *ExportLVRequest) Reset() {
+	*x = ExportLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLVRequest) ProtoMessage() {}
+
+func (x *ExportLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLVRequest.ProtoReflect.Descriptor instead.
+func (*ExportLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportLVRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExportLVRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+func (x *ExportLVRequest) GetChunkSize() uint64 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *ExportLVRequest) GetBaseVolume() string {
+	if x != nil {
+		return x.BaseVolume
+	}
+	return ""
+}
+
+func (x *ExportLVRequest) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+// Represents the stream output from ExportLV.
+//
+// The first response has only size_bytes, and the following responses have chunks in ascending order of the offsets.
+// The ranges not included in any chunk are filled with zeros, unless base_volume is specified in the request.
+// If base_volume is specified, the changed ranges are exported even if they are filled with zeros,
+// and the ranges not included in any chunk are unchanged since the base volume.
+type ExportLVResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SizeBytes uint64       `protobuf:"varint,1,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // The size of the volume in bytes.
+	Chunk     *LVDataChunk `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+}
+
+func (x *ExportLVResponse) Reset() {
+	*x = ExportLVResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportLVResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLVResponse) ProtoMessage() {}
+
+func (x *ExportLVResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLVResponse.ProtoReflect.Descriptor instead.
+func (*ExportLVResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportLVResponse) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *ExportLVResponse) GetChunk() *LVDataChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// Represents the stream input for ImportLV.
+//
+// The first request must have only volume to create the logical volume,
+// and the following requests have chunks in ascending order of the offsets without overlaps.
+// The ranges not included in any chunk are filled with zeros.
+// The volume is created with the name suffixed by ".importing" and without the tags,
+// and is renamed and tagged as requested after all the chunks are written.
+//
+// If update is true in the first request, the chunks are written into the existing volume
+// specified by the name and the device class of volume instead, and the other ranges are left unchanged.
+type ImportLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Volume *CreateLVRequest `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	Chunk  *LVDataChunk     `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	Update bool             `protobuf:"varint,3,opt,name=update,proto3" json:"update,omitempty"`
+}
+
+func (x *ImportLVRequest) Reset() {
+	*x = ImportLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportLVRequest) ProtoMessage() {}
+
+func (x *ImportLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportLVRequest.ProtoReflect.Descriptor instead.
+func (*ImportLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ImportLVRequest) GetVolume() *CreateLVRequest {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+func (x *ImportLVRequest) GetChunk() *LVDataChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *ImportLVRequest) GetUpdate() bool {
+	if x != nil {
+		return x.Update
+	}
+	return false
+}
+
+// Represents the response of ImportLV.
+type ImportLVResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Volume *LogicalVolume `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"` // Information of the imported volume.
+}
+
+func (x *ImportLVResponse) Reset() {
+	*x = ImportLVResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportLVResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportLVResponse) ProtoMessage() {}
+
+func (x *ImportLVResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportLVResponse.ProtoReflect.Descriptor instead.
+func (*ImportLVResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ImportLVResponse) GetVolume() *LogicalVolume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+// Represents the input for ResizeLV.
+//
+// The volume must already exist.
+// The volume size will be set to "size_bytes" rounded up to a multiple of the extent size.
+type ResizeLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                    // The logical volume name.
+	SizeGb      uint64 `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"` // Volume size in GiB. Used only if size_bytes is zero.
+	DeviceClass string `protobuf:"bytes,3,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	SizeBytes   uint64 `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Volume size in bytes.
+}
+
+func (x *ResizeLVRequest) Reset() {
+	*x = ResizeLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResizeLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResizeLVRequest) ProtoMessage() {}
+
+func (x *ResizeLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResizeLVRequest.ProtoReflect.Descriptor instead.
+func (*ResizeLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResizeLVRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ResizeLVRequest) GetSizeGb() uint64 {
+	if x != nil {
+		return x.SizeGb
+	}
+	return 0
+}
+
+func (x *ResizeLVRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+func (x *ResizeLVRequest) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+// Represents the response of ResizeLV.
+//
+// lvmd older than this message returns Empty, which leaves "volume" unset.
+type ResizeLVResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Volume *LogicalVolume `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"` // Information of the resized volume.
+}
+
+func (x *ResizeLVResponse) Reset() {
+	*x = ResizeLVResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResizeLVResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResizeLVResponse) ProtoMessage() {}
+
+func (x *ResizeLVResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResizeLVResponse.ProtoReflect.Descriptor instead.
+func (*ResizeLVResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ResizeLVResponse) GetVolume() *LogicalVolume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+// Represents the input for AdoptLV.
+//
+// The volume must already exist in the device class.
+type AdoptLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                      // The name of the existing logical volume.
+	NewName     string   `protobuf:"bytes,2,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"` // The new name of the logical volume. The volume is not renamed if empty. The actual name is generated by lv-name-template of the device class if set.
+	Tags        []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`                      // Tags to add to the volume.
+	DeviceClass string   `protobuf:"bytes,4,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+}
+
+func (x *AdoptLVRequest) Reset() {
+	*x = AdoptLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdoptLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptLVRequest) ProtoMessage() {}
+
+func (x *AdoptLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptLVRequest.ProtoReflect.Descriptor instead.
+func (*AdoptLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AdoptLVRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdoptLVRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+func (x *AdoptLVRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AdoptLVRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+// Represents the response of AdoptLV.
+type AdoptLVResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Volume *LogicalVolume `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"` // Information of the adopted volume.
+}
+
+func (x *AdoptLVResponse) Reset() {
+	*x = AdoptLVResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdoptLVResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptLVResponse) ProtoMessage() {}
+
+func (x *AdoptLVResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptLVResponse.ProtoReflect.Descriptor instead.
+func (*AdoptLVResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AdoptLVResponse) GetVolume() *LogicalVolume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+// Represents the input for ModifyLV.
+type ModifyLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // The logical volume name.
+	DeviceClass string   `protobuf:"bytes,2,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	ReadAhead   string   `protobuf:"bytes,3,opt,name=read_ahead,json=readAhead,proto3" json:"read_ahead,omitempty"` // "auto", "none" or the number of sectors. The read-ahead is not changed if empty.
+	AddTags     []string `protobuf:"bytes,4,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty"`       // Tags to add to the volume.
+	DelTags     []string `protobuf:"bytes,5,rep,name=del_tags,json=delTags,proto3" json:"del_tags,omitempty"`       // Tags to delete from the volume.
+}
+
+func (x *ModifyLVRequest) Reset() {
+	*x = ModifyLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModifyLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModifyLVRequest) ProtoMessage() {}
+
+func (x *ModifyLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModifyLVRequest.ProtoReflect.Descriptor instead.
+func (*ModifyLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ModifyLVRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModifyLVRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+func (x *ModifyLVRequest) GetReadAhead() string {
+	if x != nil {
+		return x.ReadAhead
+	}
+	return ""
+}
+
+func (x *ModifyLVRequest) GetAddTags() []string {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *ModifyLVRequest) GetDelTags() []string {
+	if x != nil {
+		return x.DelTags
+	}
+	return nil
+}
+
+// Represents the response of GetLVList.
+type GetLVListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Volumes    []*LogicalVolume `protobuf:"bytes,1,rep,name=volumes,proto3" json:"volumes,omitempty"`                          // Information of volumes.
+	ExtentSize uint64           `protobuf:"varint,2,opt,name=extent_size,json=extentSize,proto3" json:"extent_size,omitempty"` // Extent size of the volume group in bytes. The sizes of the volumes are multiples of this.
+}
+
+func (x *GetLVListResponse) Reset() {
+	*x = GetLVListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLVListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLVListResponse) ProtoMessage() {}
+
+func (x *GetLVListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLVListResponse.ProtoReflect.Descriptor instead.
+func (*GetLVListResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetLVListResponse) GetVolumes() []*LogicalVolume {
+	if x != nil {
+		return x.Volumes
+	}
+	return nil
+}
+
+func (x *GetLVListResponse) GetExtentSize() uint64 {
+	if x != nil {
+		return x.ExtentSize
+	}
+	return 0
+}
+
+// Represents the response of GetFreeBytes.
+type GetFreeBytesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FreeBytes uint64 `protobuf:"varint,1,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"` // Free space of the volume group in bytes.
+}
+
+func (x *GetFreeBytesResponse) Reset() {
+	*x = GetFreeBytesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFreeBytesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFreeBytesResponse) ProtoMessage() {}
+
+func (x *GetFreeBytesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFreeBytesResponse.ProtoReflect.Descriptor instead.
+func (*GetFreeBytesResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetFreeBytesResponse) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+type GetLVListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceClass string `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+}
+
+func (x *GetLVListRequest) Reset() {
+	*x = GetLVListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLVListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLVListRequest) ProtoMessage() {}
+
+func (x *GetLVListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLVListRequest.ProtoReflect.Descriptor instead.
+func (*GetLVListRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetLVListRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+type GetFreeBytesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceClass string `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+}
+
+func (x *GetFreeBytesRequest) Reset() {
+	*x = GetFreeBytesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFreeBytesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFreeBytesRequest) ProtoMessage() {}
+
+func (x *GetFreeBytesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFreeBytesRequest.ProtoReflect.Descriptor instead.
+func (*GetFreeBytesRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetFreeBytesRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+// Represents the stream output from Watch.
+type WatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FreeBytes uint64       `protobuf:"varint,1,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"` // Free space of the default volume group in bytes. In the case of thin pools, free space on the thinpool with overprovision in bytes.
+	Items     []*WatchItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchResponse) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+func (x *WatchResponse) GetItems() []*WatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Represents the details of thinpool.
+type ThinPoolItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataPercent        float64 `protobuf:"fixed64,1,opt,name=data_percent,json=dataPercent,proto3" json:"data_percent,omitempty"`                     // Data percent occupied on the thinpool, used for monitoring.
+	MetadataPercent    float64 `protobuf:"fixed64,2,opt,name=metadata_percent,json=metadataPercent,proto3" json:"metadata_percent,omitempty"`         // Metadata percent occupied on the thinpool, used for monitoring.
+	OverprovisionBytes uint64  `protobuf:"varint,3,opt,name=overprovision_bytes,json=overprovisionBytes,proto3" json:"overprovision_bytes,omitempty"` // Free space on the thinpool with overprovision, used for annotating node.
+	SizeBytes          uint64  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`                            // Physical data space size of the thinpool.
+}
+
+func (x *ThinPoolItem) Reset() {
+	*x = ThinPoolItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThinPoolItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThinPoolItem) ProtoMessage() {}
+
+func (x *ThinPoolItem) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThinPoolItem.ProtoReflect.Descriptor instead.
+func (*ThinPoolItem) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ThinPoolItem) GetDataPercent() float64 {
+	if x != nil {
+		return x.DataPercent
+	}
+	return 0
+}
+
+func (x *ThinPoolItem) GetMetadataPercent() float64 {
+	if x != nil {
+		return x.MetadataPercent
 	}
 	return 0
 }
@@ -858,12 +1942,13 @@ type WatchItem struct {
 	DeviceClass string        `protobuf:"bytes,2,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
 	SizeBytes   uint64        `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Size of volume group in bytes.
 	ThinPool    *ThinPoolItem `protobuf:"bytes,4,opt,name=thin_pool,json=thinPool,proto3" json:"thin_pool,omitempty"`
+	Default     bool          `protobuf:"varint,5,opt,name=default,proto3" json:"default,omitempty"` // True if the device class is the default device class.
 }
 
 func (x *WatchItem) Reset() {
 	*x = WatchItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -876,7 +1961,7 @@ func (x *WatchItem) String() string {
 func (*WatchItem) ProtoMessage() {}
 
 func (x *WatchItem) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,7 +1974,7 @@ func (x *WatchItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchItem.ProtoReflect.Descriptor instead.
 func (*WatchItem) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{14}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *WatchItem) GetFreeBytes() uint64 {
@@ -920,12 +2005,19 @@ func (x *WatchItem) GetThinPool() *ThinPoolItem {
 	return nil
 }
 
+func (x *WatchItem) GetDefault() bool {
+	if x != nil {
+		return x.Default
+	}
+	return false
+}
+
 var File_lvmd_proto_lvmd_proto protoreflect.FileDescriptor
 
 var file_lvmd_proto_lvmd_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x6c, 0x76, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6c, 0x76, 0x6d,
 	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x07,
-	0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x69,
+	0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0xa9, 0x01, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x69,
 	0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a,
 	0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
@@ -934,123 +2026,278 @@ var file_lvmd_proto_lvmd_proto_rawDesc = []byte{
 	0x6a, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x5f, 0x6d, 0x69, 0x6e, 0x6f, 0x72,
 	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x76, 0x4d, 0x69, 0x6e, 0x6f, 0x72,
 	0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
-	0x74, 0x61, 0x67, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c,
-	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07,
-	0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73,
-	0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x32, 0x0a, 0x15,
-	0x6c, 0x76, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x6c, 0x76, 0x63,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x61, 0x73, 0x73,
-	0x22, 0x40, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67,
-	0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75,
-	0x6d, 0x65, 0x22, 0x48, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0xc3, 0x01, 0x0a,
-	0x17, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
-	0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
-	0x61, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x76, 0x6f,
-	0x6c, 0x75, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72,
-	0x63, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65,
-	0x5f, 0x67, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47,
-	0x62, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x79,
-	0x70, 0x65, 0x22, 0x4c, 0x0a, 0x18, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e,
-	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30,
-	0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
-	0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x22, 0x61, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
+	0x74, 0x61, 0x67, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x22, 0xc8, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73,
+	0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69,
+	0x7a, 0x65, 0x47, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x6c,
+	0x76, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x6c, 0x76, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x40,
+	0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63,
+	0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x22, 0x67, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x73,
+	0x79, 0x6e, 0x63, 0x5f, 0x77, 0x69, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x61, 0x73, 0x79, 0x6e, 0x63, 0x57, 0x69, 0x70, 0x65, 0x22, 0x6c, 0x0a, 0x10, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x77, 0x69, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x77,
+	0x69, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x70, 0x65, 0x64, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x77, 0x69, 0x70, 0x65,
+	0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xe2, 0x01, 0x0a, 0x17, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f,
-	0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47, 0x62,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x1f, 0x0a, 0x0b,
+	0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x4c, 0x0a, 0x18,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x79, 0x0a, 0x1c, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x36, 0x0a,
+	0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x56, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x07, 0x6d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x15, 0x4c, 0x56, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x76, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x79, 0x70, 0x65, 0x22, 0x53, 0x0a,
+	0x1d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32,
+	0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61,
+	0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x73, 0x22, 0xce, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
-	0x61, 0x73, 0x73, 0x22, 0x43, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75,
-	0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52,
-	0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x22, 0x35, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x46,
-	0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22,
-	0x35, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c,
-	0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63,
-	0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0x38, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x46, 0x72, 0x65,
-	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a,
-	0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x76, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x61, 0x73, 0x65,
+	0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62,
+	0x61, 0x73, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x22, 0x3c, 0x0a, 0x0a, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x22, 0x73, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x76, 0x6f, 0x6c, 0x75,
+	0x6d, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x06, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x6d, 0x0a, 0x0b, 0x4c, 0x56, 0x44, 0x61, 0x74, 0x61,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22, 0xa9, 0x01, 0x0a, 0x0f, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73,
-	0x22, 0x56, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
-	0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65,
-	0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x0c, 0x54, 0x68, 0x69,
-	0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x74,
-	0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x0b, 0x64, 0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x70,
-	0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x76, 0x65, 0x72, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73,
-	0x69, 0x6f, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65,
-	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69,
-	0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x9e, 0x01, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63,
-	0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79,
-	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42,
-	0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63,
-	0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f,
-	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a,
-	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x74, 0x68, 0x69, 0x6e, 0x5f, 0x70,
-	0x6f, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x54, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x08,
-	0x74, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x32, 0x81, 0x02, 0x0a, 0x09, 0x4c, 0x56, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x12,
-	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x30, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c,
-	0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65,
-	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x53, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc3, 0x01, 0x0a,
-	0x09, 0x56, 0x47, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x47, 0x65,
-	0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69,
-	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x47, 0x65,
-	0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
-	0x65, 0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0c, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x30, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d, 0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d,
-	0x2f, 0x6c, 0x76, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x73, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49,
+	0x64, 0x22, 0x5b, 0x0a, 0x10, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x56, 0x44, 0x61,
+	0x74, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x83,
+	0x01, 0x0a, 0x0f, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75,
+	0x6d, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x56, 0x44, 0x61, 0x74, 0x61,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x06,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x22, 0x40, 0x0a, 0x10, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x69, 0x7a,
+	0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x73, 0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69,
+	0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x40, 0x0a, 0x10, 0x52, 0x65, 0x73,
+	0x69, 0x7a, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a,
+	0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x22, 0x76, 0x0a, 0x0e, 0x41,
+	0x64, 0x6f, 0x70, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x22, 0x3f, 0x0a, 0x0f, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x4c, 0x56, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06, 0x76, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x0f, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x79, 0x4c,
+	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x61, 0x68, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x61, 0x64, 0x41, 0x68, 0x65, 0x61, 0x64, 0x12, 0x19,
+	0x0a, 0x08, 0x61, 0x64, 0x64, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x61, 0x64, 0x64, 0x54, 0x61, 0x67, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x65, 0x6c,
+	0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x6c,
+	0x54, 0x61, 0x67, 0x73, 0x22, 0x64, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x52, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x78, 0x74,
+	0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x35, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x22, 0x35, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0x38, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x46,
+	0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61,
+	0x73, 0x73, 0x22, 0x56, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49,
+	0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x0c, 0x54,
+	0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x64,
+	0x61, 0x74, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x29,
+	0x0a, 0x10, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x6f, 0x76, 0x65,
+	0x72, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x76, 0x65, 0x72, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69,
+	0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xb8, 0x01, 0x0a, 0x09, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a,
+	0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73,
+	0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x74, 0x68, 0x69, 0x6e,
+	0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x08, 0x74, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x32, 0xc2, 0x05, 0x0a, 0x09, 0x4c, 0x56, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x12, 0x16,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3b, 0x0a, 0x08, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x08,
+	0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c,
+	0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x10, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62,
+	0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x5b, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12,
+	0x3d, 0x0a, 0x08, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x3d,
+	0x0a, 0x08, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x38, 0x0a,
+	0x07, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x4c, 0x56, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x4c, 0x56, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x4d, 0x6f, 0x64, 0x69, 0x66,
+	0x79, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x6f, 0x64, 0x69,
+	0x66, 0x79, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x32, 0xc3, 0x01, 0x0a, 0x09, 0x56, 0x47,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x4c, 0x56,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74,
+	0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x46, 0x72,
+	0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x47, 0x65, 0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x46,
+	0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2d, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42,
+	0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x6f,
+	0x70, 0x6f, 0x6c, 0x76, 0x6d, 0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d, 0x2f, 0x6c, 0x76,
+	0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1065,49 +2312,86 @@ func file_lvmd_proto_lvmd_proto_rawDescGZIP() []byte {
 	return file_lvmd_proto_lvmd_proto_rawDescData
 }
 
-var file_lvmd_proto_lvmd_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_lvmd_proto_lvmd_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_lvmd_proto_lvmd_proto_goTypes = []interface{}{
-	(*Empty)(nil),                    // 0: proto.Empty
-	(*LogicalVolume)(nil),            // 1: proto.LogicalVolume
-	(*CreateLVRequest)(nil),          // 2: proto.CreateLVRequest
-	(*CreateLVResponse)(nil),         // 3: proto.CreateLVResponse
-	(*RemoveLVRequest)(nil),          // 4: proto.RemoveLVRequest
-	(*CreateLVSnapshotRequest)(nil),  // 5: proto.CreateLVSnapshotRequest
-	(*CreateLVSnapshotResponse)(nil), // 6: proto.CreateLVSnapshotResponse
-	(*ResizeLVRequest)(nil),          // 7: proto.ResizeLVRequest
-	(*GetLVListResponse)(nil),        // 8: proto.GetLVListResponse
-	(*GetFreeBytesResponse)(nil),     // 9: proto.GetFreeBytesResponse
-	(*GetLVListRequest)(nil),         // 10: proto.GetLVListRequest
-	(*GetFreeBytesRequest)(nil),      // 11: proto.GetFreeBytesRequest
-	(*WatchResponse)(nil),            // 12: proto.WatchResponse
-	(*ThinPoolItem)(nil),             // 13: proto.ThinPoolItem
-	(*WatchItem)(nil),                // 14: proto.WatchItem
+	(*Empty)(nil),                         // 0: proto.Empty
+	(*LogicalVolume)(nil),                 // 1: proto.LogicalVolume
+	(*CreateLVRequest)(nil),               // 2: proto.CreateLVRequest
+	(*CreateLVResponse)(nil),              // 3: proto.CreateLVResponse
+	(*RemoveLVRequest)(nil),               // 4: proto.RemoveLVRequest
+	(*RemoveLVResponse)(nil),              // 5: proto.RemoveLVResponse
+	(*CreateLVSnapshotRequest)(nil),       // 6: proto.CreateLVSnapshotRequest
+	(*CreateLVSnapshotResponse)(nil),      // 7: proto.CreateLVSnapshotResponse
+	(*CreateLVGroupSnapshotRequest)(nil),  // 8: proto.CreateLVGroupSnapshotRequest
+	(*LVGroupSnapshotMember)(nil),         // 9: proto.LVGroupSnapshotMember
+	(*CreateLVGroupSnapshotResponse)(nil), // 10: proto.CreateLVGroupSnapshotResponse
+	(*GetLVBlockMetadataRequest)(nil),     // 11: proto.GetLVBlockMetadataRequest
+	(*BlockRange)(nil),                    // 12: proto.BlockRange
+	(*GetLVBlockMetadataResponse)(nil),    // 13: proto.GetLVBlockMetadataResponse
+	(*LVDataChunk)(nil),                   // 14: proto.LVDataChunk
+	(*ExportLVRequest)(nil),               // 15: proto.ExportLVRequest
+	(*ExportLVResponse)(nil),              // 16: proto.ExportLVResponse
+	(*ImportLVRequest)(nil),               // 17: proto.ImportLVRequest
+	(*ImportLVResponse)(nil),              // 18: proto.ImportLVResponse
+	(*ResizeLVRequest)(nil),               // 19: proto.ResizeLVRequest
+	(*ResizeLVResponse)(nil),              // 20: proto.ResizeLVResponse
+	(*AdoptLVRequest)(nil),                // 21: proto.AdoptLVRequest
+	(*AdoptLVResponse)(nil),               // 22: proto.AdoptLVResponse
+	(*ModifyLVRequest)(nil),               // 23: proto.ModifyLVRequest
+	(*GetLVListResponse)(nil),             // 24: proto.GetLVListResponse
+	(*GetFreeBytesResponse)(nil),          // 25: proto.GetFreeBytesResponse
+	(*GetLVListRequest)(nil),              // 26: proto.GetLVListRequest
+	(*GetFreeBytesRequest)(nil),           // 27: proto.GetFreeBytesRequest
+	(*WatchResponse)(nil),                 // 28: proto.WatchResponse
+	(*ThinPoolItem)(nil),                  // 29: proto.ThinPoolItem
+	(*WatchItem)(nil),                     // 30: proto.WatchItem
 }
 var file_lvmd_proto_lvmd_proto_depIdxs = []int32{
 	1,  // 0: proto.CreateLVResponse.volume:type_name -> proto.LogicalVolume
 	1,  // 1: proto.CreateLVSnapshotResponse.snapshot:type_name -> proto.LogicalVolume
-	1,  // 2: proto.GetLVListResponse.volumes:type_name -> proto.LogicalVolume
-	14, // 3: proto.WatchResponse.items:type_name -> proto.WatchItem
-	13, // 4: proto.WatchItem.thin_pool:type_name -> proto.ThinPoolItem
-	2,  // 5: proto.LVService.CreateLV:input_type -> proto.CreateLVRequest
-	4,  // 6: proto.LVService.RemoveLV:input_type -> proto.RemoveLVRequest
-	7,  // 7: proto.LVService.ResizeLV:input_type -> proto.ResizeLVRequest
-	5,  // 8: proto.LVService.CreateLVSnapshot:input_type -> proto.CreateLVSnapshotRequest
-	10, // 9: proto.VGService.GetLVList:input_type -> proto.GetLVListRequest
-	11, // 10: proto.VGService.GetFreeBytes:input_type -> proto.GetFreeBytesRequest
-	0,  // 11: proto.VGService.Watch:input_type -> proto.Empty
-	3,  // 12: proto.LVService.CreateLV:output_type -> proto.CreateLVResponse
-	0,  // 13: proto.LVService.RemoveLV:output_type -> proto.Empty
-	0,  // 14: proto.LVService.ResizeLV:output_type -> proto.Empty
-	6,  // 15: proto.LVService.CreateLVSnapshot:output_type -> proto.CreateLVSnapshotResponse
-	8,  // 16: proto.VGService.GetLVList:output_type -> proto.GetLVListResponse
-	9,  // 17: proto.VGService.GetFreeBytes:output_type -> proto.GetFreeBytesResponse
-	12, // 18: proto.VGService.Watch:output_type -> proto.WatchResponse
-	12, // [12:19] is the sub-list for method output_type
-	5,  // [5:12] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	9,  // 2: proto.CreateLVGroupSnapshotRequest.members:type_name -> proto.LVGroupSnapshotMember
+	1,  // 3: proto.CreateLVGroupSnapshotResponse.snapshots:type_name -> proto.LogicalVolume
+	12, // 4: proto.GetLVBlockMetadataResponse.ranges:type_name -> proto.BlockRange
+	14, // 5: proto.ExportLVResponse.chunk:type_name -> proto.LVDataChunk
+	2,  // 6: proto.ImportLVRequest.volume:type_name -> proto.CreateLVRequest
+	14, // 7: proto.ImportLVRequest.chunk:type_name -> proto.LVDataChunk
+	1,  // 8: proto.ImportLVResponse.volume:type_name -> proto.LogicalVolume
+	1,  // 9: proto.ResizeLVResponse.volume:type_name -> proto.LogicalVolume
+	1,  // 10: proto.AdoptLVResponse.volume:type_name -> proto.LogicalVolume
+	1,  // 11: proto.GetLVListResponse.volumes:type_name -> proto.LogicalVolume
+	30, // 12: proto.WatchResponse.items:type_name -> proto.WatchItem
+	29, // 13: proto.WatchItem.thin_pool:type_name -> proto.ThinPoolItem
+	2,  // 14: proto.LVService.CreateLV:input_type -> proto.CreateLVRequest
+	4,  // 15: proto.LVService.RemoveLV:input_type -> proto.RemoveLVRequest
+	19, // 16: proto.LVService.ResizeLV:input_type -> proto.ResizeLVRequest
+	6,  // 17: proto.LVService.CreateLVSnapshot:input_type -> proto.CreateLVSnapshotRequest
+	8,  // 18: proto.LVService.CreateLVGroupSnapshot:input_type -> proto.CreateLVGroupSnapshotRequest
+	11, // 19: proto.LVService.GetLVBlockMetadata:input_type -> proto.GetLVBlockMetadataRequest
+	15, // 20: proto.LVService.ExportLV:input_type -> proto.ExportLVRequest
+	17, // 21: proto.LVService.ImportLV:input_type -> proto.ImportLVRequest
+	21, // 22: proto.LVService.AdoptLV:input_type -> proto.AdoptLVRequest
+	23, // 23: proto.LVService.ModifyLV:input_type -> proto.ModifyLVRequest
+	26, // 24: proto.VGService.GetLVList:input_type -> proto.GetLVListRequest
+	27, // 25: proto.VGService.GetFreeBytes:input_type -> proto.GetFreeBytesRequest
+	0,  // 26: proto.VGService.Watch:input_type -> proto.Empty
+	3,  // 27: proto.LVService.CreateLV:output_type -> proto.CreateLVResponse
+	5,  // 28: proto.LVService.RemoveLV:output_type -> proto.RemoveLVResponse
+	20, // 29: proto.LVService.ResizeLV:output_type -> proto.ResizeLVResponse
+	7,  // 30: proto.LVService.CreateLVSnapshot:output_type -> proto.CreateLVSnapshotResponse
+	10, // 31: proto.LVService.CreateLVGroupSnapshot:output_type -> proto.CreateLVGroupSnapshotResponse
+	13, // 32: proto.LVService.GetLVBlockMetadata:output_type -> proto.GetLVBlockMetadataResponse
+	16, // 33: proto.LVService.ExportLV:output_type -> proto.ExportLVResponse
+	18, // 34: proto.LVService.ImportLV:output_type -> proto.ImportLVResponse
+	22, // 35: proto.LVService.AdoptLV:output_type -> proto.AdoptLVResponse
+	0,  // 36: proto.LVService.ModifyLV:output_type -> proto.Empty
+	24, // 37: proto.VGService.GetLVList:output_type -> proto.GetLVListResponse
+	25, // 38: proto.VGService.GetFreeBytes:output_type -> proto.GetFreeBytesResponse
+	28, // 39: proto.VGService.Watch:output_type -> proto.WatchResponse
+	27, // [27:40] is the sub-list for method output_type
+	14, // [14:27] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_lvmd_proto_lvmd_proto_init() }
@@ -1177,7 +2461,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateLVSnapshotRequest); i {
+			switch v := v.(*RemoveLVResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1189,7 +2473,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateLVSnapshotResponse); i {
+			switch v := v.(*CreateLVSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1201,7 +2485,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResizeLVRequest); i {
+			switch v := v.(*CreateLVSnapshotResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1213,7 +2497,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetLVListResponse); i {
+			switch v := v.(*CreateLVGroupSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1225,7 +2509,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetFreeBytesResponse); i {
+			switch v := v.(*LVGroupSnapshotMember); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1237,7 +2521,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetLVListRequest); i {
+			switch v := v.(*CreateLVGroupSnapshotResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1249,7 +2533,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetFreeBytesRequest); i {
+			switch v := v.(*GetLVBlockMetadataRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1261,7 +2545,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WatchResponse); i {
+			switch v := v.(*BlockRange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1273,7 +2557,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThinPoolItem); i {
+			switch v := v.(*GetLVBlockMetadataResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1285,6 +2569,198 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LVDataChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportLVResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportLVResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResizeLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResizeLVResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdoptLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdoptLVResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModifyLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLVListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFreeBytesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLVListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFreeBytesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThinPoolItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WatchItem); i {
 			case 0:
 				return &v.state
@@ -1303,7 +2779,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_lvmd_proto_lvmd_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
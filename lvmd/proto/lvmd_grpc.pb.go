@@ -24,11 +24,23 @@ const _ = grpc.SupportPackageIsVersion7
 type LVServiceClient interface {
 	// Create a logical volume.
 	CreateLV(ctx context.Context, in *CreateLVRequest, opts ...grpc.CallOption) (*CreateLVResponse, error)
-	// Remove a logical volume.
-	RemoveLV(ctx context.Context, in *RemoveLVRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Remove a logical volume after wiping it according to the wipe policy of the device class.
+	RemoveLV(ctx context.Context, in *RemoveLVRequest, opts ...grpc.CallOption) (*RemoveLVResponse, error)
 	// Resize a logical volume.
-	ResizeLV(ctx context.Context, in *ResizeLVRequest, opts ...grpc.CallOption) (*Empty, error)
+	ResizeLV(ctx context.Context, in *ResizeLVRequest, opts ...grpc.CallOption) (*ResizeLVResponse, error)
 	CreateLVSnapshot(ctx context.Context, in *CreateLVSnapshotRequest, opts ...grpc.CallOption) (*CreateLVSnapshotResponse, error)
+	// Create snapshots of several logical volumes at the same point in time.
+	CreateLVGroupSnapshot(ctx context.Context, in *CreateLVGroupSnapshotRequest, opts ...grpc.CallOption) (*CreateLVGroupSnapshotResponse, error)
+	// Get the allocated block ranges of a thin volume, or the changed block ranges between two thin volumes.
+	GetLVBlockMetadata(ctx context.Context, in *GetLVBlockMetadataRequest, opts ...grpc.CallOption) (LVService_GetLVBlockMetadataClient, error)
+	// Stream the contents of a logical volume. Only the allocated ranges are read from thin volumes.
+	ExportLV(ctx context.Context, in *ExportLVRequest, opts ...grpc.CallOption) (LVService_ExportLVClient, error)
+	// Create a logical volume and write the streamed contents into it.
+	ImportLV(ctx context.Context, opts ...grpc.CallOption) (LVService_ImportLVClient, error)
+	// Adopt an existing logical volume by renaming and tagging it.
+	AdoptLV(ctx context.Context, in *AdoptLVRequest, opts ...grpc.CallOption) (*AdoptLVResponse, error)
+	// Modify the mutable attributes of a logical volume.
+	ModifyLV(ctx context.Context, in *ModifyLVRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type lVServiceClient struct {
@@ -48,8 +60,8 @@ func (c *lVServiceClient) CreateLV(ctx context.Context, in *CreateLVRequest, opt
 	return out, nil
 }
 
-func (c *lVServiceClient) RemoveLV(ctx context.Context, in *RemoveLVRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *lVServiceClient) RemoveLV(ctx context.Context, in *RemoveLVRequest, opts ...grpc.CallOption) (*RemoveLVResponse, error) {
+	out := new(RemoveLVResponse)
 	err := c.cc.Invoke(ctx, "/proto.LVService/RemoveLV", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -57,8 +69,8 @@ func (c *lVServiceClient) RemoveLV(ctx context.Context, in *RemoveLVRequest, opt
 	return out, nil
 }
 
-func (c *lVServiceClient) ResizeLV(ctx context.Context, in *ResizeLVRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *lVServiceClient) ResizeLV(ctx context.Context, in *ResizeLVRequest, opts ...grpc.CallOption) (*ResizeLVResponse, error) {
+	out := new(ResizeLVResponse)
 	err := c.cc.Invoke(ctx, "/proto.LVService/ResizeLV", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -75,17 +87,154 @@ func (c *lVServiceClient) CreateLVSnapshot(ctx context.Context, in *CreateLVSnap
 	return out, nil
 }
 
+func (c *lVServiceClient) CreateLVGroupSnapshot(ctx context.Context, in *CreateLVGroupSnapshotRequest, opts ...grpc.CallOption) (*CreateLVGroupSnapshotResponse, error) {
+	out := new(CreateLVGroupSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/proto.LVService/CreateLVGroupSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVServiceClient) GetLVBlockMetadata(ctx context.Context, in *GetLVBlockMetadataRequest, opts ...grpc.CallOption) (LVService_GetLVBlockMetadataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LVService_ServiceDesc.Streams[0], "/proto.LVService/GetLVBlockMetadata", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lVServiceGetLVBlockMetadataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LVService_GetLVBlockMetadataClient interface {
+	Recv() (*GetLVBlockMetadataResponse, error)
+	grpc.ClientStream
+}
+
+type lVServiceGetLVBlockMetadataClient struct {
+	grpc.ClientStream
+}
+
+func (x *lVServiceGetLVBlockMetadataClient) Recv() (*GetLVBlockMetadataResponse, error) {
+	m := new(GetLVBlockMetadataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lVServiceClient) ExportLV(ctx context.Context, in *ExportLVRequest, opts ...grpc.CallOption) (LVService_ExportLVClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LVService_ServiceDesc.Streams[1], "/proto.LVService/ExportLV", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lVServiceExportLVClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LVService_ExportLVClient interface {
+	Recv() (*ExportLVResponse, error)
+	grpc.ClientStream
+}
+
+type lVServiceExportLVClient struct {
+	grpc.ClientStream
+}
+
+func (x *lVServiceExportLVClient) Recv() (*ExportLVResponse, error) {
+	m := new(ExportLVResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lVServiceClient) ImportLV(ctx context.Context, opts ...grpc.CallOption) (LVService_ImportLVClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LVService_ServiceDesc.Streams[2], "/proto.LVService/ImportLV", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lVServiceImportLVClient{stream}
+	return x, nil
+}
+
+type LVService_ImportLVClient interface {
+	Send(*ImportLVRequest) error
+	CloseAndRecv() (*ImportLVResponse, error)
+	grpc.ClientStream
+}
+
+type lVServiceImportLVClient struct {
+	grpc.ClientStream
+}
+
+func (x *lVServiceImportLVClient) Send(m *ImportLVRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lVServiceImportLVClient) CloseAndRecv() (*ImportLVResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportLVResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lVServiceClient) AdoptLV(ctx context.Context, in *AdoptLVRequest, opts ...grpc.CallOption) (*AdoptLVResponse, error) {
+	out := new(AdoptLVResponse)
+	err := c.cc.Invoke(ctx, "/proto.LVService/AdoptLV", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVServiceClient) ModifyLV(ctx context.Context, in *ModifyLVRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.LVService/ModifyLV", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LVServiceServer is the server API for LVService service.
 // All implementations must embed UnimplementedLVServiceServer
 // for forward compatibility
 type LVServiceServer interface {
 	// Create a logical volume.
 	CreateLV(context.Context, *CreateLVRequest) (*CreateLVResponse, error)
-	// Remove a logical volume.
-	RemoveLV(context.Context, *RemoveLVRequest) (*Empty, error)
+	// Remove a logical volume after wiping it according to the wipe policy of the device class.
+	RemoveLV(context.Context, *RemoveLVRequest) (*RemoveLVResponse, error)
 	// Resize a logical volume.
-	ResizeLV(context.Context, *ResizeLVRequest) (*Empty, error)
+	ResizeLV(context.Context, *ResizeLVRequest) (*ResizeLVResponse, error)
 	CreateLVSnapshot(context.Context, *CreateLVSnapshotRequest) (*CreateLVSnapshotResponse, error)
+	// Create snapshots of several logical volumes at the same point in time.
+	CreateLVGroupSnapshot(context.Context, *CreateLVGroupSnapshotRequest) (*CreateLVGroupSnapshotResponse, error)
+	// Get the allocated block ranges of a thin volume, or the changed block ranges between two thin volumes.
+	GetLVBlockMetadata(*GetLVBlockMetadataRequest, LVService_GetLVBlockMetadataServer) error
+	// Stream the contents of a logical volume. Only the allocated ranges are read from thin volumes.
+	ExportLV(*ExportLVRequest, LVService_ExportLVServer) error
+	// Create a logical volume and write the streamed contents into it.
+	ImportLV(LVService_ImportLVServer) error
+	// Adopt an existing logical volume by renaming and tagging it.
+	AdoptLV(context.Context, *AdoptLVRequest) (*AdoptLVResponse, error)
+	// Modify the mutable attributes of a logical volume.
+	ModifyLV(context.Context, *ModifyLVRequest) (*Empty, error)
 	mustEmbedUnimplementedLVServiceServer()
 }
 
@@ -96,15 +245,33 @@ type UnimplementedLVServiceServer struct {
 func (UnimplementedLVServiceServer) CreateLV(context.Context, *CreateLVRequest) (*CreateLVResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateLV not implemented")
 }
-func (UnimplementedLVServiceServer) RemoveLV(context.Context, *RemoveLVRequest) (*Empty, error) {
+func (UnimplementedLVServiceServer) RemoveLV(context.Context, *RemoveLVRequest) (*RemoveLVResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveLV not implemented")
 }
-func (UnimplementedLVServiceServer) ResizeLV(context.Context, *ResizeLVRequest) (*Empty, error) {
+func (UnimplementedLVServiceServer) ResizeLV(context.Context, *ResizeLVRequest) (*ResizeLVResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResizeLV not implemented")
 }
 func (UnimplementedLVServiceServer) CreateLVSnapshot(context.Context, *CreateLVSnapshotRequest) (*CreateLVSnapshotResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateLVSnapshot not implemented")
 }
+func (UnimplementedLVServiceServer) CreateLVGroupSnapshot(context.Context, *CreateLVGroupSnapshotRequest) (*CreateLVGroupSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLVGroupSnapshot not implemented")
+}
+func (UnimplementedLVServiceServer) GetLVBlockMetadata(*GetLVBlockMetadataRequest, LVService_GetLVBlockMetadataServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetLVBlockMetadata not implemented")
+}
+func (UnimplementedLVServiceServer) ExportLV(*ExportLVRequest, LVService_ExportLVServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportLV not implemented")
+}
+func (UnimplementedLVServiceServer) ImportLV(LVService_ImportLVServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportLV not implemented")
+}
+func (UnimplementedLVServiceServer) AdoptLV(context.Context, *AdoptLVRequest) (*AdoptLVResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdoptLV not implemented")
+}
+func (UnimplementedLVServiceServer) ModifyLV(context.Context, *ModifyLVRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyLV not implemented")
+}
 func (UnimplementedLVServiceServer) mustEmbedUnimplementedLVServiceServer() {}
 
 // UnsafeLVServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -190,6 +357,128 @@ func _LVService_CreateLVSnapshot_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LVService_CreateLVGroupSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLVGroupSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVServiceServer).CreateLVGroupSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVService/CreateLVGroupSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVServiceServer).CreateLVGroupSnapshot(ctx, req.(*CreateLVGroupSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVService_GetLVBlockMetadata_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLVBlockMetadataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LVServiceServer).GetLVBlockMetadata(m, &lVServiceGetLVBlockMetadataServer{stream})
+}
+
+type LVService_GetLVBlockMetadataServer interface {
+	Send(*GetLVBlockMetadataResponse) error
+	grpc.ServerStream
+}
+
+type lVServiceGetLVBlockMetadataServer struct {
+	grpc.ServerStream
+}
+
+func (x *lVServiceGetLVBlockMetadataServer) Send(m *GetLVBlockMetadataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LVService_ExportLV_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportLVRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LVServiceServer).ExportLV(m, &lVServiceExportLVServer{stream})
+}
+
+type LVService_ExportLVServer interface {
+	Send(*ExportLVResponse) error
+	grpc.ServerStream
+}
+
+type lVServiceExportLVServer struct {
+	grpc.ServerStream
+}
+
+func (x *lVServiceExportLVServer) Send(m *ExportLVResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LVService_ImportLV_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LVServiceServer).ImportLV(&lVServiceImportLVServer{stream})
+}
+
+type LVService_ImportLVServer interface {
+	SendAndClose(*ImportLVResponse) error
+	Recv() (*ImportLVRequest, error)
+	grpc.ServerStream
+}
+
+type lVServiceImportLVServer struct {
+	grpc.ServerStream
+}
+
+func (x *lVServiceImportLVServer) SendAndClose(m *ImportLVResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lVServiceImportLVServer) Recv() (*ImportLVRequest, error) {
+	m := new(ImportLVRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LVService_AdoptLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdoptLVRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVServiceServer).AdoptLV(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVService/AdoptLV",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVServiceServer).AdoptLV(ctx, req.(*AdoptLVRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVService_ModifyLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModifyLVRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVServiceServer).ModifyLV(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVService/ModifyLV",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVServiceServer).ModifyLV(ctx, req.(*ModifyLVRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LVService_ServiceDesc is the grpc.ServiceDesc for LVService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -213,8 +502,36 @@ var LVService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateLVSnapshot",
 			Handler:    _LVService_CreateLVSnapshot_Handler,
 		},
+		{
+			MethodName: "CreateLVGroupSnapshot",
+			Handler:    _LVService_CreateLVGroupSnapshot_Handler,
+		},
+		{
+			MethodName: "AdoptLV",
+			Handler:    _LVService_AdoptLV_Handler,
+		},
+		{
+			MethodName: "ModifyLV",
+			Handler:    _LVService_ModifyLV_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetLVBlockMetadata",
+			Handler:       _LVService_GetLVBlockMetadata_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportLV",
+			Handler:       _LVService_ExportLV_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportLV",
+			Handler:       _LVService_ImportLV_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "lvmd/proto/lvmd.proto",
 }
 
@@ -0,0 +1,210 @@
+package lvmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cybozu-go/log"
+	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+const (
+	// defaultTransferChunkSize is the length of the data in a chunk of ExportLV
+	// when the request does not specify it.
+	defaultTransferChunkSize = 1 << 20
+	// maxTransferChunkSize is the maximum length of the data in a chunk.
+	// It keeps the messages under the default limit of gRPC even if the data is not compressible.
+	maxTransferChunkSize = 2 << 20
+	// importingSuffix is appended to the name of a volume while its contents are imported.
+	importingSuffix = ".importing"
+	// exportingSuffix is appended to snapshot_id of ExportLV to name the snapshot being exported.
+	exportingSuffix = ".exporting"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// RemoveTransferLeftovers removes the volumes left in vgs by ImportLV and ExportLV interrupted by a restart of lvmd.
+// They have no owner tags, so OrphanedLVCollector does not find them.
+// This must be called before lvmd starts serving because the volumes being transferred have the same suffixes.
+func RemoveTransferLeftovers(vgs []*command.VolumeGroup) error {
+	for _, vg := range vgs {
+		for _, lv := range vg.ListVolumes() {
+			if !strings.HasSuffix(lv.Name(), importingSuffix) && !strings.HasSuffix(lv.Name(), exportingSuffix) {
+				continue
+			}
+			log.Info("removing a volume left by an interrupted transfer", map[string]interface{}{
+				"name":         lv.Name(),
+				"volume_group": vg.Name(),
+			})
+			if err := lv.Remove(); err != nil {
+				return fmt.Errorf("failed to remove %s in %s: %w", lv.Name(), vg.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// errInvalidChunk is returned when a received chunk is corrupted or out of order.
+var errInvalidChunk = errors.New("invalid chunk")
+
+// encodeChunk compresses data read at offset into a chunk.
+func encodeChunk(offset uint64, data []byte) (*proto.LVDataChunk, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &proto.LVDataChunk{
+		Offset:   offset,
+		Length:   uint64(len(data)),
+		Data:     buf.Bytes(),
+		Checksum: crc32.Checksum(data, castagnoli),
+	}, nil
+}
+
+// decodeChunk decompresses the data in chunk and verifies its length and checksum.
+func decodeChunk(chunk *proto.LVDataChunk) ([]byte, error) {
+	if chunk.GetLength() > maxTransferChunkSize {
+		return nil, fmt.Errorf("%w: chunk at %d is too large: %d", errInvalidChunk, chunk.GetOffset(), chunk.GetLength())
+	}
+	r, err := gzip.NewReader(bytes.NewReader(chunk.GetData()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: chunk at %d: %v", errInvalidChunk, chunk.GetOffset(), err)
+	}
+	defer r.Close()
+
+	// read one more byte to detect the data longer than the length.
+	data, err := io.ReadAll(io.LimitReader(r, int64(chunk.GetLength())+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: chunk at %d: %v", errInvalidChunk, chunk.GetOffset(), err)
+	}
+	if uint64(len(data)) != chunk.GetLength() {
+		return nil, fmt.Errorf("%w: length mismatch in chunk at %d: expected=%d, actual=%d", errInvalidChunk, chunk.GetOffset(), chunk.GetLength(), len(data))
+	}
+	if crc32.Checksum(data, castagnoli) != chunk.GetChecksum() {
+		return nil, fmt.Errorf("%w: checksum mismatch in chunk at %d", errInvalidChunk, chunk.GetOffset())
+	}
+	return data, nil
+}
+
+// exportChunks reads the ranges from src and sends them as chunks of at most chunkSize bytes.
+// If skipZero is true, the chunks filled with zeros are not sent.
+func exportChunks(src io.ReaderAt, ranges []command.BlockRange, chunkSize uint64, skipZero bool, send func(*proto.LVDataChunk) error) error {
+	buf := make([]byte, chunkSize)
+	zero := make([]byte, chunkSize)
+	for _, r := range ranges {
+		end := r.Offset + r.Length
+		for offset := r.Offset; offset < end; offset += chunkSize {
+			length := chunkSize
+			if end-offset < length {
+				length = end - offset
+			}
+
+			data := buf[:length]
+			if _, err := src.ReadAt(data, int64(offset)); err != nil {
+				return err
+			}
+			if skipZero && bytes.Equal(data, zero[:length]) {
+				continue
+			}
+			chunk, err := encodeChunk(offset, data)
+			if err != nil {
+				return err
+			}
+			if err := send(chunk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importTarget is the volume into which the contents are imported.
+type importTarget interface {
+	io.WriterAt
+	Size() uint64
+	ZeroOut(offset, length uint64) error
+}
+
+// lvWriter is the importTarget to write into a logical volume.
+type lvWriter struct {
+	*os.File
+	lv *command.LogicalVolume
+}
+
+func (w lvWriter) Size() uint64 {
+	return w.lv.Size()
+}
+
+func (w lvWriter) ZeroOut(offset, length uint64) error {
+	// The unallocated ranges of thin volumes are read as zeros.
+	if w.lv.IsThin() {
+		return nil
+	}
+	return w.lv.ZeroOut(offset, length)
+}
+
+// updateTarget wraps an importTarget to write only the received chunks, leaving the other ranges unchanged.
+type updateTarget struct {
+	importTarget
+}
+
+func (t updateTarget) ZeroOut(offset, length uint64) error {
+	return nil
+}
+
+// importChunks writes the chunks received by recv into dst until recv returns io.EOF.
+// The chunks must be in ascending order of their offsets without overlaps.
+// The ranges not covered by any chunk are zeroed out.
+func importChunks(dst importTarget, recv func() (*proto.LVDataChunk, error)) error {
+	size := dst.Size()
+	var next uint64
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if chunk.GetOffset() < next {
+			return fmt.Errorf("%w: chunk at %d overlaps with the previous chunk", errInvalidChunk, chunk.GetOffset())
+		}
+		if chunk.GetOffset()+chunk.GetLength() > size {
+			return fmt.Errorf("%w: chunk at %d exceeds the volume size %d", errInvalidChunk, chunk.GetOffset(), size)
+		}
+		data, err := decodeChunk(chunk)
+		if err != nil {
+			return err
+		}
+
+		if chunk.GetOffset() > next {
+			if err := dst.ZeroOut(next, chunk.GetOffset()-next); err != nil {
+				return err
+			}
+		}
+		if _, err := dst.WriteAt(data, int64(chunk.GetOffset())); err != nil {
+			return err
+		}
+		next = chunk.GetOffset() + chunk.GetLength()
+	}
+
+	if next < size {
+		return dst.ZeroOut(next, size-next)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package lvmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+type fakeImportTarget struct {
+	data []byte
+	ops  []string
+}
+
+func (t *fakeImportTarget) Size() uint64 {
+	return uint64(len(t.data))
+}
+
+func (t *fakeImportTarget) WriteAt(p []byte, off int64) (int, error) {
+	t.ops = append(t.ops, fmt.Sprintf("write %d %d", off, len(p)))
+	return copy(t.data[off:], p), nil
+}
+
+func (t *fakeImportTarget) ZeroOut(offset, length uint64) error {
+	t.ops = append(t.ops, fmt.Sprintf("zero %d %d", offset, length))
+	for i := offset; i < offset+length; i++ {
+		t.data[i] = 0
+	}
+	return nil
+}
+
+func TestExportImportChunks(t *testing.T) {
+	src := make([]byte, 64<<10)
+	for i := 8 << 10; i < 20<<10; i++ {
+		src[i] = byte(i)
+	}
+	src[40<<10] = 1
+	src[60<<10] = 1
+
+	// the last allocated range is omitted to test that only the ranges are exported.
+	ranges := []command.BlockRange{
+		{Offset: 0, Length: 32 << 10},
+		{Offset: 40 << 10, Length: 8 << 10},
+	}
+	var chunks []*proto.LVDataChunk
+	err := exportChunks(bytes.NewReader(src), ranges, 4<<10, true, func(chunk *proto.LVDataChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offsets []uint64
+	for _, chunk := range chunks {
+		offsets = append(offsets, chunk.GetOffset())
+	}
+	if expected := []uint64{8 << 10, 12 << 10, 16 << 10, 40 << 10}; !reflect.DeepEqual(offsets, expected) {
+		t.Errorf("unexpected chunks: expected=%v, actual=%v", expected, offsets)
+	}
+
+	dst := &fakeImportTarget{data: bytes.Repeat([]byte{0xff}, len(src))}
+	i := 0
+	err = importChunks(dst, func() (*proto.LVDataChunk, error) {
+		if i == len(chunks) {
+			return nil, io.EOF
+		}
+		i++
+		return chunks[i-1], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := make([]byte, len(src))
+	copy(expected, src[:48<<10])
+	if !bytes.Equal(dst.data, expected) {
+		t.Error("imported data is different from the exported data")
+	}
+	expectedOps := []string{
+		"zero 0 8192",
+		"write 8192 4096",
+		"write 12288 4096",
+		"write 16384 4096",
+		"zero 20480 20480",
+		"write 40960 4096",
+		"zero 45056 20480",
+	}
+	if !reflect.DeepEqual(dst.ops, expectedOps) {
+		t.Errorf("unexpected operations: expected=%v, actual=%v", expectedOps, dst.ops)
+	}
+}
+
+func TestExportChangedChunks(t *testing.T) {
+	src := make([]byte, 16<<10)
+	src[4<<10] = 1
+
+	// The changed ranges are exported even if they are filled with zeros,
+	// and the other ranges are left unchanged by the import.
+	ranges := []command.BlockRange{{Offset: 0, Length: 8 << 10}}
+	var chunks []*proto.LVDataChunk
+	err := exportChunks(bytes.NewReader(src), ranges, 4<<10, false, func(chunk *proto.LVDataChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := &fakeImportTarget{data: bytes.Repeat([]byte{0xff}, len(src))}
+	i := 0
+	err = importChunks(updateTarget{dst}, func() (*proto.LVDataChunk, error) {
+		if i == len(chunks) {
+			return nil, io.EOF
+		}
+		i++
+		return chunks[i-1], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := bytes.Repeat([]byte{0xff}, len(src))
+	copy(expected, src[:8<<10])
+	if !bytes.Equal(dst.data, expected) {
+		t.Error("updated data is different from the expected data")
+	}
+	expectedOps := []string{
+		"write 0 4096",
+		"write 4096 4096",
+	}
+	if !reflect.DeepEqual(dst.ops, expectedOps) {
+		t.Errorf("unexpected operations: expected=%v, actual=%v", expectedOps, dst.ops)
+	}
+}
+
+func TestImportInvalidChunks(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 4096)
+	chunk, err := encodeChunk(4096, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted, err := encodeChunk(4096, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted.Checksum++
+
+	cases := []struct {
+		name   string
+		chunks []*proto.LVDataChunk
+	}{
+		{name: "checksum mismatch", chunks: []*proto.LVDataChunk{corrupted}},
+		{name: "overlap", chunks: []*proto.LVDataChunk{chunk, chunk}},
+		{name: "out of range", chunks: []*proto.LVDataChunk{{Offset: 8192, Length: 4096, Data: chunk.Data, Checksum: chunk.Checksum}}},
+		{name: "not compressed", chunks: []*proto.LVDataChunk{{Offset: 0, Length: 4096, Data: data, Checksum: chunk.Checksum}}},
+	}
+	for _, tc := range cases {
+		i := 0
+		err := importChunks(&fakeImportTarget{data: make([]byte, 8192)}, func() (*proto.LVDataChunk, error) {
+			if i == len(tc.chunks) {
+				return nil, io.EOF
+			}
+			i++
+			return tc.chunks[i-1], nil
+		})
+		if !errors.Is(err, errInvalidChunk) {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
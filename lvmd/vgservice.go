@@ -70,14 +70,15 @@ func (s *vgService) GetLVList(_ context.Context, req *proto.GetLVListRequest) (*
 			continue
 		}
 		vols = append(vols, &proto.LogicalVolume{
-			Name:     lv.Name(),
-			SizeGb:   (lv.Size() + (1 << 30) - 1) >> 30,
-			DevMajor: lv.MajorNumber(),
-			DevMinor: lv.MinorNumber(),
-			Tags:     lv.Tags(),
+			Name:      lv.Name(),
+			SizeGb:    (lv.Size() + (1 << 30) - 1) >> 30,
+			SizeBytes: lv.Size(),
+			DevMajor:  lv.MajorNumber(),
+			DevMinor:  lv.MinorNumber(),
+			Tags:      lv.Tags(),
 		})
 	}
-	return &proto.GetLVListResponse{Volumes: vols}, nil
+	return &proto.GetLVListResponse{Volumes: vols, ExtentSize: vg.ExtentSize()}, nil
 }
 
 func (s *vgService) GetFreeBytes(_ context.Context, req *proto.GetFreeBytesRequest) (*proto.GetFreeBytesResponse, error) {
@@ -190,6 +191,7 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 				FreeBytes:   vgFree,
 				SizeBytes:   vgSize,
 				ThinPool:    tpi,
+				Default:     dc.Default,
 			})
 		}
 
@@ -213,6 +215,7 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 			DeviceClass: dc.Name,
 			FreeBytes:   vgFree,
 			SizeBytes:   vgSize,
+			Default:     dc.Default,
 		})
 	}
 	return server.Send(res)
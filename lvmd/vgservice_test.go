@@ -193,6 +193,9 @@ func testVGService(t *testing.T, vg *command.VolumeGroup) {
 	if numVols1 != 0 {
 		t.Errorf("numVolumes must be 0: %d", numVols1)
 	}
+	if res.GetExtentSize() != vg.ExtentSize() {
+		t.Errorf("extent size must be %d: %d", vg.ExtentSize(), res.GetExtentSize())
+	}
 
 	// thin lvs
 	res, err = vgService.GetLVList(context.Background(), &proto.GetLVListRequest{DeviceClass: thindev})
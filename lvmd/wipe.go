@@ -0,0 +1,128 @@
+package lvmd
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// wipeChunkSize is the size of the range wiped at once.
+// The progress of wiping is updated for each chunk.
+const wipeChunkSize = 1 << 30
+
+// wipeTarget is the volume to be wiped.
+type wipeTarget interface {
+	Size() uint64
+	Discard(offset, length uint64) error
+	ZeroOut(offset, length uint64) error
+	IsZero(offset, length uint64) (bool, error)
+}
+
+// wipe wipes target according to policy.
+// progress is called with the number of wiped bytes each time a chunk is wiped.
+func wipe(target wipeTarget, policy WipePolicy, progress func(uint64)) error {
+	size := target.Size()
+	for offset := uint64(0); offset < size; offset += wipeChunkSize {
+		length := uint64(wipeChunkSize)
+		if size-offset < length {
+			length = size - offset
+		}
+
+		switch policy {
+		case WipeBlkdiscard:
+			if err := target.Discard(offset, length); err != nil {
+				return err
+			}
+		case WipeZeroFill:
+			if err := target.ZeroOut(offset, length); err != nil {
+				return err
+			}
+		case WipeDiscardThenVerify:
+			if err := target.Discard(offset, length); err != nil {
+				return err
+			}
+			// discarded blocks are not guaranteed to be read as zeros on every device.
+			zero, err := target.IsZero(offset, length)
+			if err != nil {
+				return err
+			}
+			if !zero {
+				if err := target.ZeroOut(offset, length); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unsupported wipe policy: %s", policy)
+		}
+		progress(offset + length)
+	}
+	return nil
+}
+
+// wipeJob represents a wipe running in the background.
+type wipeJob struct {
+	total uint64
+	wiped atomic.Uint64
+	done  chan struct{}
+	err   error
+}
+
+// finished returns true if the job has finished.
+func (j *wipeJob) finished() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// wipeJobs manages the wipe jobs keyed by the full name of logical volumes.
+type wipeJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*wipeJob
+}
+
+func newWipeJobs() *wipeJobs {
+	return &wipeJobs{
+		jobs: make(map[string]*wipeJob),
+	}
+}
+
+func (w *wipeJobs) get(key string) *wipeJob {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.jobs[key]
+}
+
+func (w *wipeJobs) delete(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.jobs, key)
+}
+
+// start runs fn in the background unless a job for key already exists.
+// fn should report the progress via the given function.
+// The job is forgotten when fn succeeds, while a failed job is kept until
+// it is deleted so that the error can be reported to the caller.
+func (w *wipeJobs) start(key string, total uint64, fn func(progress func(uint64)) error) *wipeJob {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if job, ok := w.jobs[key]; ok {
+		return job
+	}
+
+	job := &wipeJob{
+		total: total,
+		done:  make(chan struct{}),
+	}
+	w.jobs[key] = job
+	go func() {
+		job.err = fn(job.wiped.Store)
+		if job.err == nil {
+			w.delete(key)
+		}
+		close(job.done)
+	}()
+	return job
+}
@@ -0,0 +1,138 @@
+package lvmd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeWipeTarget struct {
+	size    uint64
+	nonZero map[uint64]bool
+	ops     []string
+}
+
+func (t *fakeWipeTarget) Size() uint64 {
+	return t.size
+}
+
+func (t *fakeWipeTarget) Discard(offset, length uint64) error {
+	t.ops = append(t.ops, fmt.Sprintf("discard %d %d", offset, length))
+	return nil
+}
+
+func (t *fakeWipeTarget) ZeroOut(offset, length uint64) error {
+	t.ops = append(t.ops, fmt.Sprintf("zero %d %d", offset, length))
+	return nil
+}
+
+func (t *fakeWipeTarget) IsZero(offset, length uint64) (bool, error) {
+	t.ops = append(t.ops, fmt.Sprintf("verify %d %d", offset, length))
+	return !t.nonZero[offset], nil
+}
+
+func TestWipe(t *testing.T) {
+	size := uint64(2<<30 + 4096)
+	cases := []struct {
+		policy  WipePolicy
+		nonZero map[uint64]bool
+		ops     []string
+	}{
+		{
+			policy: WipeBlkdiscard,
+			ops: []string{
+				"discard 0 1073741824",
+				"discard 1073741824 1073741824",
+				"discard 2147483648 4096",
+			},
+		},
+		{
+			policy: WipeZeroFill,
+			ops: []string{
+				"zero 0 1073741824",
+				"zero 1073741824 1073741824",
+				"zero 2147483648 4096",
+			},
+		},
+		{
+			policy:  WipeDiscardThenVerify,
+			nonZero: map[uint64]bool{1 << 30: true},
+			ops: []string{
+				"discard 0 1073741824",
+				"verify 0 1073741824",
+				"discard 1073741824 1073741824",
+				"verify 1073741824 1073741824",
+				"zero 1073741824 1073741824",
+				"discard 2147483648 4096",
+				"verify 2147483648 4096",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		target := &fakeWipeTarget{size: size, nonZero: c.nonZero}
+		var progress []uint64
+		err := wipe(target, c.policy, func(wiped uint64) {
+			progress = append(progress, wiped)
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", c.policy, err)
+		}
+		if !reflect.DeepEqual(target.ops, c.ops) {
+			t.Errorf("%s: unexpected operations: %v", c.policy, target.ops)
+		}
+		if !reflect.DeepEqual(progress, []uint64{1 << 30, 2 << 30, size}) {
+			t.Errorf("%s: unexpected progress: %v", c.policy, progress)
+		}
+	}
+
+	err := wipe(&fakeWipeTarget{size: size}, WipeNone, func(uint64) {})
+	if err == nil {
+		t.Error("should fail for none")
+	}
+}
+
+func TestWipeJobs(t *testing.T) {
+	jobs := newWipeJobs()
+
+	release := make(chan struct{})
+	job := jobs.start("vg/lv1", 100, func(progress func(uint64)) error {
+		progress(50)
+		<-release
+		return nil
+	})
+	if j := jobs.start("vg/lv1", 100, func(func(uint64)) error { return nil }); j != job {
+		t.Error("should return the running job")
+	}
+	if jobs.get("vg/lv1") != job {
+		t.Error("job should be found")
+	}
+	close(release)
+	<-job.done
+	if job.wiped.Load() != 50 {
+		t.Errorf("unexpected progress: %d", job.wiped.Load())
+	}
+	if !job.finished() {
+		t.Error("job should be finished")
+	}
+	if jobs.get("vg/lv1") != nil {
+		t.Error("succeeded job should be forgotten")
+	}
+
+	failure := errors.New("failure")
+	job = jobs.start("vg/lv2", 100, func(func(uint64)) error { return failure })
+	select {
+	case <-job.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("job should finish")
+	}
+	if jobs.get("vg/lv2") != job || job.err != failure {
+		t.Error("failed job should be kept with the error")
+	}
+	jobs.delete("vg/lv2")
+	if jobs.get("vg/lv2") != nil {
+		t.Error("job should be deleted")
+	}
+}
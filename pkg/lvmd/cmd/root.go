@@ -62,6 +62,9 @@ func subMain() error {
 		return err
 	}
 
+	// dcVGs has the volume groups used by the device classes. Several device classes can share one.
+	var dcVGs []*command.VolumeGroup
+	seen := make(map[string]struct{})
 	for _, dc := range config.DeviceClasses {
 		vg, err := command.SearchVolumeGroupList(vgs, dc.VolumeGroup)
 		if err != nil {
@@ -80,6 +83,13 @@ func subMain() error {
 				return err
 			}
 		}
+		if _, ok := seen[vg.Name()]; !ok {
+			seen[vg.Name()] = struct{}{}
+			dcVGs = append(dcVGs, vg)
+		}
+	}
+	if err := lvmd.RemoveTransferLeftovers(dcVGs); err != nil {
+		return err
 	}
 
 	// UNIX domain socket file should be removed before listening.
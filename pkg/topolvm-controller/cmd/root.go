@@ -25,6 +25,8 @@ var config struct {
 	leaderElectionRenewDeadline time.Duration
 	leaderElectionRetryPeriod   time.Duration
 	skipNodeFinalize            bool
+	autoResizeCheckInterval     time.Duration
+	autoResizeMinInterval       time.Duration
 	zapOpts                     zap.Options
 }
 
@@ -64,6 +66,8 @@ func init() {
 	fs.DurationVar(&config.leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second, "Duration that the acting controlplane will retry refreshing leadership before giving up. This is measured against time of last observed ack.")
 	fs.DurationVar(&config.leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second, "Duration the LeaderElector clients should wait between tries of actions.")
 	fs.BoolVar(&config.skipNodeFinalize, "skip-node-finalize", false, "skips automatic cleanup of PhysicalVolumeClaims when a Node is deleted")
+	fs.DurationVar(&config.autoResizeCheckInterval, "auto-resize-check-interval", 1*time.Minute, "Interval to check the filesystem usage of PVCs to be expanded automatically. 0 disables the automatic expansion.")
+	fs.DurationVar(&config.autoResizeMinInterval, "auto-resize-min-interval", 10*time.Minute, "Minimum interval between automatic expansions of a PVC")
 
 	goflags := flag.NewFlagSet("klog", flag.ExitOnError)
 	klog.InitFlags(goflags)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	"github.com/topolvm/topolvm"
 	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
 	topolvmv1 "github.com/topolvm/topolvm/api/v1"
@@ -38,6 +39,7 @@ func init() {
 
 	utilruntime.Must(topolvmv1.AddToScheme(scheme))
 	utilruntime.Must(topolvmlegacyv1.AddToScheme(scheme))
+	utilruntime.Must(snapapi.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -105,6 +107,32 @@ func subMain() error {
 		return err
 	}
 
+	pvcontroller := controllers.NewPersistentVolumeReconciler(client, mgr.GetEventRecorderFor("topolvm-controller"))
+	if err := pvcontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PersistentVolume")
+		return err
+	}
+
+	migrationcontroller := controllers.NewVolumeMigrationReconciler(client, apiReader, mgr.GetEventRecorderFor("topolvm-controller"))
+	if err := migrationcontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VolumeMigration")
+		return err
+	}
+
+	snapshotschedulecontroller := controllers.NewSnapshotScheduleReconciler(client, apiReader, mgr.GetEventRecorderFor("topolvm-controller"))
+	if err := snapshotschedulecontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SnapshotSchedule")
+		return err
+	}
+
+	if config.autoResizeCheckInterval > 0 {
+		autoresizer := controllers.NewPVCAutoresizer(client, mgr.GetEventRecorderFor("topolvm-controller"),
+			config.autoResizeCheckInterval, config.autoResizeMinInterval)
+		if err := mgr.Add(autoresizer); err != nil {
+			return err
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	// Add health checker to manager
@@ -124,11 +152,12 @@ func subMain() error {
 	// Add gRPC server to manager.
 	grpcServer := grpc.NewServer()
 	csi.RegisterIdentityServer(grpcServer, driver.NewIdentityServer(checker.Ready))
-	controllerSever, err := driver.NewControllerServer(mgr)
+	controllerSever, groupControllerServer, err := driver.NewControllerServer(mgr)
 	if err != nil {
 		return err
 	}
 	csi.RegisterControllerServer(grpcServer, controllerSever)
+	csi.RegisterGroupControllerServer(grpcServer, groupControllerServer)
 
 	// gRPC service itself should run even when the manager is *not* a leader
 	// because CSI sidecar containers choose a leader.
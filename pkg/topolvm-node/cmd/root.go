@@ -4,19 +4,32 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/controllers"
+	"github.com/topolvm/topolvm/driver"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
 var config struct {
-	csiSocket   string
-	lvmdSocket  string
-	metricsAddr string
-	zapOpts     zap.Options
+	csiSocket              string
+	lvmdSocket             string
+	metricsAddr            string
+	driftDetectionInterval time.Duration
+	driftAutoHealSize      bool
+	orphanCheckInterval    time.Duration
+	orphanGracePeriod      time.Duration
+	cgroupRoot             string
+	fstrimCheckInterval    time.Duration
+	freezeTimeout          time.Duration
+	transferAddr           string
+	transferAdvertiseAddr  string
+	transferCertDir        string
+	zapOpts                zap.Options
 }
 
 var rootCmd = &cobra.Command{
@@ -49,6 +62,16 @@ func init() {
 	fs.StringVar(&config.csiSocket, "csi-socket", topolvm.DefaultCSISocket, "UNIX domain socket filename for CSI")
 	fs.StringVar(&config.lvmdSocket, "lvmd-socket", topolvm.DefaultLVMdSocket, "UNIX domain socket of lvmd service")
 	fs.StringVar(&config.metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	fs.DurationVar(&config.driftDetectionInterval, "drift-detection-interval", 10*time.Minute, "Interval to compare LogicalVolumes with the actual LVs. Set 0 to disable.")
+	fs.BoolVar(&config.driftAutoHealSize, "drift-auto-heal-size", false, "Extend LVs that became smaller than the size recorded in LogicalVolumes.")
+	fs.DurationVar(&config.orphanCheckInterval, "orphaned-lv-check-interval", 10*time.Minute, "Interval to find LVs not owned by any LogicalVolume. Set 0 to disable.")
+	fs.DurationVar(&config.orphanGracePeriod, "orphaned-lv-deletion-grace-period", 0, "Delete orphaned LVs after this period. Set 0 to only report them.")
+	fs.DurationVar(&config.fstrimCheckInterval, "fstrim-check-interval", time.Minute, "Interval to check if fstrim is due on the mounted volumes. Set 0 to disable fstrim.")
+	fs.DurationVar(&config.freezeTimeout, "fsfreeze-timeout", controllers.DefaultFreezeTimeout, "Maximum duration for which the filesystem of a source volume is frozen to take a snapshot.")
+	fs.StringVar(&config.cgroupRoot, "cgroup-root", driver.DefaultCgroupRoot, "Mount point of the cgroup v2 hierarchy of the host to apply I/O limits.")
+	fs.StringVar(&config.transferAddr, "transfer-bind-address", "", "The address to serve the contents of volumes to other nodes. Empty disables volume transfer.")
+	fs.StringVar(&config.transferAdvertiseAddr, "transfer-advertise-address", "", "The address advertised to other nodes for volume transfer. Defaults to --transfer-bind-address.")
+	fs.StringVar(&config.transferCertDir, "transfer-cert-dir", "/certs/transfer", "The directory that has tls.crt, tls.key, and ca.crt for volume transfer.")
 	fs.String("nodename", "", "The resource name of the running node")
 
 	viper.BindEnv("nodename", "NODE_NAME")
@@ -18,6 +18,7 @@ import (
 	"github.com/topolvm/topolvm/lvmd/proto"
 	"github.com/topolvm/topolvm/runners"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -73,13 +75,44 @@ func subMain() error {
 	}
 	defer conn.Close()
 
-	lvcontroller := controllers.NewLogicalVolumeReconciler(client, nodename, conn)
+	var transferServerCreds, transferClientCreds credentials.TransportCredentials
+	if config.transferAddr != "" {
+		transferServerCreds, transferClientCreds, err = driver.LoadTransferCredentials(config.transferCertDir)
+		if err != nil {
+			setupLog.Error(err, "unable to load credentials for volume transfer")
+			return err
+		}
+	}
+
+	lvcontroller := controllers.NewLogicalVolumeReconciler(client, mgr.GetEventRecorderFor("topolvm-node"), nodename, conn, config.freezeTimeout, transferClientCreds)
 	if err := lvcontroller.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LogicalVolume")
 		return err
 	}
 	//+kubebuilder:scaffold:builder
 
+	if config.driftDetectionInterval > 0 {
+		detector := controllers.NewLogicalVolumeDriftDetector(client, mgr.GetEventRecorderFor("topolvm-node"), nodename, conn,
+			config.driftDetectionInterval, config.driftAutoHealSize)
+		if err := mgr.Add(detector); err != nil {
+			return err
+		}
+	}
+
+	if config.orphanCheckInterval > 0 {
+		collector := controllers.NewOrphanedLVCollector(client, mgr.GetEventRecorderFor("topolvm-node"), nodename, conn,
+			config.orphanCheckInterval, config.orphanGracePeriod)
+		if err := mgr.Add(collector); err != nil {
+			return err
+		}
+	}
+
+	if config.fstrimCheckInterval > 0 {
+		if err := mgr.Add(driver.NewFSTrimmer(client, nodename, conn, config.fstrimCheckInterval)); err != nil {
+			return err
+		}
+	}
+
 	// Add health checker to manager
 	checker := runners.NewChecker(checkFunc(conn, apiReader), 1*time.Minute)
 	if err := mgr.Add(checker); err != nil {
@@ -99,16 +132,38 @@ func subMain() error {
 	}
 	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(ErrorLoggingInterceptor))
 	csi.RegisterIdentityServer(grpcServer, driver.NewIdentityServer(checker.Ready))
-	nodeServer, err := driver.NewNodeServer(nodename, conn, mgr)
+	nodeServer, snapshotMetadataServer, err := driver.NewNodeServer(nodename, conn, mgr, config.cgroupRoot)
 	if err != nil {
 		return err
 	}
 	csi.RegisterNodeServer(grpcServer, nodeServer)
+	csi.RegisterSnapshotMetadataServer(grpcServer, snapshotMetadataServer)
 	err = mgr.Add(runners.NewGRPCRunner(grpcServer, config.csiSocket, false))
 	if err != nil {
 		return err
 	}
 
+	// Add the server for volume transfer to manager, and advertise its address to other nodes.
+	// The address is removed from the node when volume transfer is disabled.
+	transferEndpoint := ""
+	if config.transferAddr != "" {
+		transferServer := grpc.NewServer(grpc.Creds(transferServerCreds))
+		proto.RegisterLVServiceServer(transferServer, driver.NewTransferServer(conn))
+		if err := mgr.Add(runners.NewGRPCTCPRunner(transferServer, config.transferAddr, false)); err != nil {
+			return err
+		}
+		transferEndpoint = config.transferAdvertiseAddr
+		if transferEndpoint == "" {
+			transferEndpoint = config.transferAddr
+		}
+	}
+	err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return driver.AdvertiseTransferEndpoint(ctx, client, nodename, transferEndpoint)
+	}))
+	if err != nil {
+		return err
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -230,6 +230,13 @@ func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_Wat
 				freeSize = item.FreeBytes
 			}
 			node2.Annotations[topolvm.GetCapacityKeyPrefix()+item.DeviceClass] = strconv.FormatUint(freeSize, 10)
+			if item.ThinPool != nil {
+				dataPercent := strconv.FormatFloat(item.ThinPool.DataPercent, 'f', 2, 64)
+				node2.Annotations[topolvm.GetThinPoolDataPercentKeyPrefix()+item.DeviceClass] = dataPercent
+				if item.Default {
+					node2.Annotations[topolvm.GetThinPoolDataPercentKeyPrefix()+topolvm.DefaultDeviceClassAnnotationName] = dataPercent
+				}
+			}
 		}
 		if err := m.client.Patch(ctx, node2, client.MergeFrom(&node)); err != nil {
 			return err